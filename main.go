@@ -15,15 +15,32 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/rdmnl/kubepulse/pkg/audit"
+	"github.com/rdmnl/kubepulse/pkg/config"
+	"github.com/rdmnl/kubepulse/pkg/demo"
 	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/pkg/metrics"
+	"github.com/rdmnl/kubepulse/pkg/snapshot"
 	"github.com/rdmnl/kubepulse/ui"
 	"github.com/rivo/tview"
 )
 
 func main() {
+	readOnly := flag.Bool("readonly", false, "Disable every action that changes the cluster")
+	configPath := flag.String("config", config.DefaultPath(), "Path to the kubepulse configuration file")
+	auditPath := flag.String("audit-log", audit.DefaultPath(), "Path to the audit file recording every change, empty to disable")
+	demoMode := flag.Bool("demo", false, "Run against a synthetic in-memory cluster instead of a real one")
+	recordPath := flag.String("record", "", "Record a snapshot of the cluster to this file and exit")
+	replayPath := flag.String("replay", "", "Open a snapshot recorded with --record instead of a live cluster")
+	flag.Parse()
+
 	file, err := os.OpenFile("app.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
 	if err != nil {
 		panic(err)
@@ -31,25 +48,106 @@ func main() {
 	defer file.Close()
 	log.SetOutput(file)
 
-	kubeconfigPath := os.Getenv("KUBECONFIG")
-	if kubeconfigPath == "" {
-		kubeconfigPath = os.ExpandEnv("$HOME/.kube/config")
+	var client *kubernetes.Client
+	switch {
+	case *demoMode:
+		client = demo.NewClient()
+	case *replayPath != "":
+		recorded, err := snapshot.Load(*replayPath)
+		if err == nil {
+			client, err = snapshot.NewClient(recorded)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open snapshot: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		kubeconfigPath := os.Getenv("KUBECONFIG")
+		if kubeconfigPath == "" {
+			kubeconfigPath = os.ExpandEnv("$HOME/.kube/config")
+		}
+
+		namespace := "default"
+		client, err = kubernetes.NewClient(kubeconfigPath, namespace)
+		if err != nil {
+			log.Fatalf("Failed to create Kubernetes client: %v. Ensure your KUBECONFIG environment variable is correctly set or provide a valid kubeconfig path.", err)
+		}
 	}
 
-	namespace := "default"
-	client, err := kubernetes.NewClient(kubeconfigPath, namespace)
+	cfg, err := config.Load(*configPath)
 	if err != nil {
-		log.Fatalf("Failed to create Kubernetes client: %v. Ensure your KUBECONFIG environment variable is correctly set or provide a valid kubeconfig path.", err)
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	protection := cfg.ProtectionFor(client.ContextName())
+	client.SetReadOnly(client.ReadOnly() || *readOnly || (protection != nil && protection.ReadOnly))
+	if *recordPath != "" {
+		recordSnapshot(client, *recordPath)
+		return
+	}
+
+	// Changes to the demo cluster are not real, so they stay out of the audit log.
+	if *auditPath != "" && !*demoMode && *replayPath == "" {
+		client.SetAuditLog(audit.NewLog(*auditPath))
 	}
 
 	app := tview.NewApplication()
 
-	uiManager, layout := ui.SetupUILayout(app, client)
+	uiManager, layout := ui.SetupUILayout(app, client, cfg)
 
-	controller := ui.NewUIController(app, uiManager, client)
+	controller := ui.NewUIController(app, uiManager, client, cfg)
+	controller.ConfigPath = *configPath
 	ui.SetupNavigation(app, controller)
+	// Synthetic and recorded usage would only pollute the stored trends.
+	if cfg.MetricsStore != nil && cfg.MetricsStore.Enabled && !*demoMode && *replayPath == "" {
+		if store, err := openMetricsStore(cfg.MetricsStore, client.ContextName()); err != nil {
+			log.Printf("Metrics will not be stored: %v", err)
+		} else {
+			controller.MetricsStore = store
+			defer store.Close()
+		}
+	}
+	go controller.StartMetricsSampler()
+	go controller.StartClusterSummary()
 
 	if err := app.SetRoot(layout, true).Run(); err != nil {
 		panic(err)
 	}
 }
+
+// openMetricsStore opens the metrics store of the current context.
+func openMetricsStore(storeConfig *config.MetricsStore, contextName string) (*metrics.Store, error) {
+	root := storeConfig.Path
+	if root == "" {
+		root = metrics.DefaultStorePath()
+	} else if strings.HasPrefix(root, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			root = filepath.Join(home, root[2:])
+		}
+	}
+	if root == "" {
+		return nil, fmt.Errorf("no directory for the metrics store")
+	}
+	retention, err := storeConfig.RetentionDuration()
+	if err != nil {
+		return nil, err
+	}
+	return metrics.OpenStore(metrics.ContextDir(root, contextName), retention)
+}
+
+// recordSnapshot writes a snapshot of the cluster to path and reports what
+// could not be recorded.
+func recordSnapshot(client *kubernetes.Client, path string) {
+	fmt.Printf("Recording a snapshot of %s...\n", client.ContextName())
+	recorded, err := snapshot.Record(client)
+	if err == nil {
+		err = recorded.Save(path)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to record snapshot: %v\n", err)
+		os.Exit(1)
+	}
+	for _, warning := range recorded.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	fmt.Printf("Recorded %d pods on %d nodes to %s. Open it with --replay %s\n", len(recorded.Pods), len(recorded.Nodes), path, path)
+}
@@ -0,0 +1,207 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// ResultStarted marks the start of a session, such as exec, whose outcome
+	// is recorded in a later entry.
+	ResultStarted = "started"
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	ResultDenied  = "denied"
+)
+
+// Entry is one line of the audit file.
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	User      string    `json:"user"`
+	Context   string    `json:"context"`
+	Verb      string    `json:"verb"`
+	Resource  string    `json:"resource"`
+	Result    string    `json:"result"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// DefaultMaxSize is the size at which the audit file is rotated.
+const DefaultMaxSize = 10 << 20
+
+// Log appends entries to a JSON lines file. Once the file reaches MaxSize it
+// is renamed with a ".1" suffix, replacing the previous one, and a new file is
+// started. It is safe for concurrent use.
+type Log struct {
+	path string
+	// MaxSize is the size in bytes at which the file is rotated.
+	MaxSize int64
+	mu      sync.Mutex
+}
+
+// DefaultPath returns the location of the audit file in the user configuration
+// directory, for example ~/.config/kubepulse/audit.jsonl on Linux.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kubepulse", "audit.jsonl")
+}
+
+// NewLog returns a log writing to path. An empty path disables it: entries
+// are dropped and none are read back.
+func NewLog(path string) *Log {
+	return &Log{path: path, MaxSize: DefaultMaxSize}
+}
+
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append writes entry as a new line at the end of the audit file, creating the
+// file and its directory if needed.
+func (l *Log) Append(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %v", err)
+	}
+
+	if l.path == "" {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create audit directory: %v", err)
+	}
+	if err := l.rotate(int64(len(data)) + 1); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file %s: %v", l.path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit file %s: %v", l.path, err)
+	}
+	return nil
+}
+
+// rotate moves the file aside when writing size more bytes would take it past
+// MaxSize.
+func (l *Log) rotate(size int64) error {
+	info, err := os.Stat(l.path)
+	if errors.Is(err, os.ErrNotExist) || l.MaxSize <= 0 {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat audit file %s: %v", l.path, err)
+	}
+	if info.Size() == 0 || info.Size()+size <= l.MaxSize {
+		return nil
+	}
+	if err := os.Rename(l.path, l.rotatedPath()); err != nil {
+		return fmt.Errorf("failed to rotate audit file %s: %v", l.path, err)
+	}
+	return nil
+}
+
+func (l *Log) rotatedPath() string {
+	return l.path + ".1"
+}
+
+// Entries returns up to limit of the most recent entries, newest first, from
+// the file and the one rotated before it. Lines that cannot be parsed are
+// skipped. A limit of 0 returns every entry.
+func (l *Log) Entries(limit int) ([]Entry, error) {
+	if l.path == "" {
+		return nil, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var entries []Entry
+	for _, path := range []string{l.rotatedPath(), l.path} {
+		var err error
+		if entries, err = readEntries(path, entries, limit); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+// readEntries appends the entries of the file at path to entries, oldest
+// first, keeping only the last limit of them.
+func readEntries(path string, entries []Entry, limit int) ([]Entry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file %s: %v", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+		if limit > 0 && len(entries) > limit {
+			entries = entries[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit file %s: %v", path, err)
+	}
+	return entries, nil
+}
+
+// Outcomes drops the start of every session that has ended from entries,
+// newest first as returned by Entries, so that a drain or a shell shows up
+// once with its outcome. Sessions still running, or whose end was never
+// recorded, keep their start entry.
+func Outcomes(entries []Entry) []Entry {
+	type session struct{ user, context, verb, resource string }
+	ended := make(map[session]int)
+	var outcomes []Entry
+	for _, entry := range entries {
+		key := session{entry.User, entry.Context, entry.Verb, entry.Resource}
+		switch entry.Result {
+		case ResultStarted:
+			if ended[key] > 0 {
+				ended[key]--
+				continue
+			}
+		case ResultSuccess, ResultFailure:
+			ended[key]++
+		}
+		outcomes = append(outcomes, entry)
+	}
+	return outcomes
+}
@@ -0,0 +1,143 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testEntry(i int) Entry {
+	return Entry{
+		Timestamp: time.Date(2024, 10, 18, 12, 0, i, 0, time.UTC),
+		User:      "admin",
+		Context:   "prod-eu",
+		Verb:      "delete",
+		Resource:  fmt.Sprintf("default/pod/web-%d", i),
+		Result:    ResultSuccess,
+	}
+}
+
+func resources(entries []Entry) []string {
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Resource)
+	}
+	return names
+}
+
+func TestAppendAndEntries(t *testing.T) {
+	log := NewLog(filepath.Join(t.TempDir(), "kubepulse", "audit.jsonl"))
+	if entries, err := log.Entries(0); err != nil || entries != nil {
+		t.Errorf("Entries before the first write = %v, %v, want none", entries, err)
+	}
+
+	failed := testEntry(3)
+	failed.Result, failed.Error = ResultFailure, "forbidden"
+	for _, entry := range []Entry{testEntry(1), testEntry(2), failed} {
+		if err := log.Append(entry); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	entries, err := log.Entries(0)
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	if want := []Entry{failed, testEntry(2), testEntry(1)}; !reflect.DeepEqual(entries, want) {
+		t.Errorf("Entries = %+v, want %+v", entries, want)
+	}
+	if entries, _ := log.Entries(2); !reflect.DeepEqual(resources(entries), []string{"default/pod/web-3", "default/pod/web-2"}) {
+		t.Errorf("Entries(2) = %q, want the two newest", resources(entries))
+	}
+
+	info, err := os.Stat(log.Path())
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("audit file mode = %v, %v, want 0600", info.Mode(), err)
+	}
+}
+
+func TestEntriesSkipsUnreadableLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log := NewLog(path)
+	log.Append(testEntry(1))
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("{truncated\n")
+	file.Close()
+	log.Append(testEntry(2))
+
+	if entries, err := log.Entries(0); err != nil || !reflect.DeepEqual(resources(entries), []string{"default/pod/web-2", "default/pod/web-1"}) {
+		t.Errorf("Entries = %q, %v, want both valid entries", resources(entries), err)
+	}
+}
+
+func TestAppendRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log := NewLog(path)
+	log.Append(testEntry(1))
+	info, _ := os.Stat(path)
+	// Room for two entries per file.
+	log.MaxSize = 2*info.Size() + 1
+
+	for i := 2; i <= 5; i++ {
+		if err := log.Append(testEntry(i)); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	// 1 and 2 were rotated out when 3 came, then 3 and 4 replaced them.
+	if entries, err := log.Entries(0); err != nil || !reflect.DeepEqual(resources(entries), []string{"default/pod/web-5", "default/pod/web-4", "default/pod/web-3"}) {
+		t.Errorf("Entries after rotating = %q, %v, want 5, 4 and 3", resources(entries), err)
+	}
+	if entries, _ := log.Entries(2); !reflect.DeepEqual(resources(entries), []string{"default/pod/web-5", "default/pod/web-4"}) {
+		t.Errorf("Entries(2) after rotating = %q, want 5 and 4", resources(entries))
+	}
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("rotated file: %v", err)
+	}
+}
+
+func TestDisabledLog(t *testing.T) {
+	log := NewLog("")
+	if err := log.Append(testEntry(1)); err != nil {
+		t.Errorf("Append to a disabled log: %v", err)
+	}
+	if entries, err := log.Entries(0); err != nil || entries != nil {
+		t.Errorf("Entries of a disabled log = %v, %v, want none", entries, err)
+	}
+}
+
+func TestOutcomes(t *testing.T) {
+	entry := func(verb string, resource string, result string) Entry {
+		return Entry{User: "admin", Context: "prod-eu", Verb: verb, Resource: resource, Result: result}
+	}
+	// Newest first: a finished drain, a shell still running and an older
+	// shell on the same pod whose end was never recorded.
+	entries := []Entry{
+		entry("drain", "node/node-a", ResultFailure),
+		entry("evict", "default/pod/web", ResultSuccess),
+		entry("cordon", "node/node-a", ResultSuccess),
+		entry("drain", "node/node-a", ResultStarted),
+		entry("exec", "default/pod/db", ResultStarted),
+		entry("exec", "default/pod/db", ResultStarted),
+	}
+	want := []Entry{entries[0], entries[1], entries[2], entries[4], entries[5]}
+	if got := Outcomes(entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("Outcomes = %+v, want %+v", got, want)
+	}
+	if got := Outcomes(entries[:1]); !reflect.DeepEqual(got, entries[:1]) {
+		t.Errorf("Outcomes of an end without its start = %+v, want it kept", got)
+	}
+}
@@ -0,0 +1,202 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Config is the user configuration read from config.yaml.
+type Config struct {
+	ProtectedContexts []ContextProtection `json:"protectedContexts,omitempty"`
+	MetricsStore      *MetricsStore       `json:"metricsStore,omitempty"`
+	RightSizing       *RightSizing        `json:"rightSizing,omitempty"`
+	// Views keeps the layout of the pod and node tables, keyed by PodsView
+	// and NodesView. KubePulse saves it when a table is rearranged.
+	Views map[string]*TableView `json:"views,omitempty"`
+}
+
+// RightSizing tunes the recommended requests and limits.
+type RightSizing struct {
+	// Percentile is the percentile of usage a request covers, 90 when unset.
+	Percentile *int `json:"percentile,omitempty"`
+	// Headroom is the percentage added to the observed usage, 15 when unset.
+	Headroom *int `json:"headroom,omitempty"`
+}
+
+// MetricsStore keeps the sampled usage on disk, so trends can be shown across
+// sessions.
+type MetricsStore struct {
+	Enabled bool `json:"enabled"`
+	// Path is the directory of the store, ~/.cache/kubepulse/metrics when empty.
+	Path string `json:"path,omitempty"`
+	// Retention is how long samples are kept, such as "24h" or "7d".
+	Retention string `json:"retention,omitempty"`
+}
+
+// ContextProtection adds safeguards to the kubeconfig contexts matching Pattern.
+type ContextProtection struct {
+	// Pattern is a shell glob such as "prod-*" matched against the whole
+	// context name. Unlike in file paths, "*" and "?" also match "/", so
+	// "*prod*" covers an EKS context such as "arn:aws:eks:...:cluster/prod".
+	Pattern string `json:"pattern"`
+	// ConfirmByName requires typing the name of the object to confirm changes.
+	// It is on unless set to false.
+	ConfirmByName *bool `json:"confirmByName,omitempty"`
+	// ReadOnly disables every change to the cluster, like --readonly.
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// Color is the header color, red when empty.
+	Color string `json:"color,omitempty"`
+	// Banner replaces the default header warning.
+	Banner string `json:"banner,omitempty"`
+}
+
+// RequiresTypedName reports whether changes must be confirmed by typing the
+// name of the object, which protected contexts do unless they opt out.
+func (p *ContextProtection) RequiresTypedName() bool {
+	return p.ConfirmByName == nil || *p.ConfirmByName
+}
+
+// DefaultPath returns the location of config.yaml in the user configuration
+// directory, for example ~/.config/kubepulse/config.yaml on Linux.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kubepulse", "config.yaml")
+}
+
+// Load reads the configuration at configPath. A missing file yields an empty
+// configuration.
+func Load(configPath string) (*Config, error) {
+	config := &Config{}
+	if configPath == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %v", configPath, err)
+	}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %v", configPath, err)
+	}
+
+	for _, protection := range config.ProtectedContexts {
+		if _, err := globRegexp(protection.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid context pattern %q in %s: %v", protection.Pattern, configPath, err)
+		}
+	}
+	if config.MetricsStore != nil {
+		if _, err := config.MetricsStore.RetentionDuration(); err != nil {
+			return nil, fmt.Errorf("invalid metrics retention in %s: %v", configPath, err)
+		}
+	}
+	if rightSizing := config.RightSizing; rightSizing != nil {
+		if percentile := rightSizing.Percentile; percentile != nil && (*percentile < 1 || *percentile > 100) {
+			return nil, fmt.Errorf("invalid right-sizing percentile %d in %s: must be between 1 and 100", *percentile, configPath)
+		}
+		if rightSizing.Headroom != nil && *rightSizing.Headroom < 0 {
+			return nil, fmt.Errorf("invalid right-sizing headroom %d in %s: must not be negative", *rightSizing.Headroom, configPath)
+		}
+	}
+	for name := range config.Views {
+		if name != PodsView && name != NodesView {
+			return nil, fmt.Errorf("unknown view %q in %s: must be %s or %s", name, configPath, PodsView, NodesView)
+		}
+	}
+	return config, nil
+}
+
+// RetentionDuration parses Retention. Besides the units of time.Duration it
+// accepts whole days such as "7d". It returns zero when Retention is empty.
+func (m *MetricsStore) RetentionDuration() (time.Duration, error) {
+	if m.Retention == "" {
+		return 0, nil
+	}
+	var days int
+	if n, err := fmt.Sscanf(m.Retention, "%dd", &days); err == nil && n == 1 && fmt.Sprintf("%dd", days) == m.Retention {
+		if days <= 0 {
+			return 0, fmt.Errorf("retention %q is not positive", m.Retention)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	retention, err := time.ParseDuration(m.Retention)
+	if err != nil {
+		return 0, err
+	}
+	if retention <= 0 {
+		return 0, fmt.Errorf("retention %q is not positive", m.Retention)
+	}
+	return retention, nil
+}
+
+// ProtectionFor returns the first protection rule matching contextName, or nil
+// if the context is not protected.
+func (c *Config) ProtectionFor(contextName string) *ContextProtection {
+	for i, protection := range c.ProtectedContexts {
+		if pattern, err := globRegexp(protection.Pattern); err == nil && pattern.MatchString(contextName) {
+			return &c.ProtectedContexts[i]
+		}
+	}
+	return nil
+}
+
+// globRegexp translates a shell glob into a regular expression matching whole
+// names, where "*" matches any run of characters, "?" any single one and
+// "[...]" a character class, negated by a leading "!".
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		case '\\':
+			if i++; i == len(pattern) {
+				return nil, fmt.Errorf("pattern ends with a backslash")
+			}
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case '[':
+			end := i + 1
+			for end < len(pattern) && pattern[end] != ']' {
+				if pattern[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(pattern) {
+				return nil, fmt.Errorf("unterminated character class")
+			}
+			class := pattern[i+1 : end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i = end
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
@@ -0,0 +1,153 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes content to a config.yaml in a temporary directory.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return configPath
+}
+
+func TestLoad(t *testing.T) {
+	config, err := Load(writeConfig(t, `
+protectedContexts:
+  - pattern: "prod-*"
+    banner: PRODUCTION
+  - pattern: "staging-?"
+    confirmByName: false
+  - pattern: "*-audit"
+    readOnly: true
+metricsStore:
+  enabled: true
+  retention: 7d
+rightSizing:
+  percentile: 95
+views:
+  pods:
+    columns: [name, cpu]
+`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(config.ProtectedContexts) != 3 || config.ProtectedContexts[0].Banner != "PRODUCTION" {
+		t.Errorf("protected contexts = %+v", config.ProtectedContexts)
+	}
+	if retention, _ := config.MetricsStore.RetentionDuration(); retention != 7*24*time.Hour {
+		t.Errorf("retention = %v, want 7 days", retention)
+	}
+	if *config.RightSizing.Percentile != 95 || len(config.Views[PodsView].Columns) != 2 {
+		t.Errorf("right-sizing = %+v, views = %+v", config.RightSizing, config.Views)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	for _, configPath := range []string{"", filepath.Join(t.TempDir(), "missing.yaml")} {
+		config, err := Load(configPath)
+		if err != nil || config == nil || len(config.ProtectedContexts) != 0 {
+			t.Errorf("Load(%q) = %+v, %v, want an empty configuration", configPath, config, err)
+		}
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	tests := map[string]string{
+		"protectedContexts:\n  - pattern: \"prod-[\"\n":         "invalid context pattern",
+		"protectedContexts:\n  - pattern: \"prod-[]\"\n":        "invalid context pattern",
+		"protectedContexts:\n  - pattern: \"prod-\\\\\"\n":      "invalid context pattern",
+		"protectedContexts:\n  - pattern: prod\n    color: [\n": "failed to parse config",
+		"unknownSetting: true\n":                                "failed to parse config",
+		"metricsStore:\n  retention: 0d\n":                      "invalid metrics retention",
+		"metricsStore:\n  retention: soon\n":                    "invalid metrics retention",
+		"rightSizing:\n  percentile: 101\n":                     "invalid right-sizing percentile",
+		"rightSizing:\n  percentile: 0\n":                       "invalid right-sizing percentile",
+		"rightSizing:\n  headroom: -5\n":                        "invalid right-sizing headroom",
+		"views:\n  deployments:\n    wide: true\n":              "unknown view",
+	}
+	for content, want := range tests {
+		if _, err := Load(writeConfig(t, content)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Load(%q) error = %v, want %q", content, err, want)
+		}
+	}
+}
+
+func TestProtectionFor(t *testing.T) {
+	config, err := Load(writeConfig(t, `
+protectedContexts:
+  - pattern: "prod-*"
+  - pattern: "staging-?"
+    confirmByName: false
+  - pattern: "*cluster/prod*"
+    readOnly: true
+  - pattern: "*"
+    readOnly: true
+    confirmByName: true
+`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		context   string
+		pattern   string
+		typedName bool
+		readOnly  bool
+	}{
+		// The first matching rule wins, and typing the name is the default.
+		{"prod-eu", "prod-*", true, false},
+		{"staging-1", "staging-?", false, false},
+		{"staging-10", "*", true, true},
+		// "*" also matches the "/" of an EKS cluster ARN.
+		{"arn:aws:eks:eu-west-1:123:cluster/prod", "*cluster/prod*", true, true},
+		{"kind-dev", "*", true, true},
+	}
+	for _, test := range tests {
+		protection := config.ProtectionFor(test.context)
+		if protection == nil {
+			t.Errorf("ProtectionFor(%q) = nil, want %q", test.context, test.pattern)
+			continue
+		}
+		if protection.Pattern != test.pattern || protection.RequiresTypedName() != test.typedName || protection.ReadOnly != test.readOnly {
+			t.Errorf("ProtectionFor(%q) = %+v, want pattern %q, typed name %v, read-only %v", test.context, protection, test.pattern, test.typedName, test.readOnly)
+		}
+	}
+
+	if protection := (&Config{}).ProtectionFor("prod-eu"); protection != nil {
+		t.Errorf("ProtectionFor without rules = %+v, want nil", protection)
+	}
+}
+
+func TestRetentionDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":    0,
+		"12h": 12 * time.Hour,
+		"90m": 90 * time.Minute,
+		"2d":  48 * time.Hour,
+	}
+	for retention, want := range tests {
+		if got, err := (&MetricsStore{Retention: retention}).RetentionDuration(); err != nil || got != want {
+			t.Errorf("RetentionDuration(%q) = %v, %v, want %v", retention, got, err, want)
+		}
+	}
+	for _, retention := range []string{"-1h", "0d", "1.5d", "week"} {
+		if _, err := (&MetricsStore{Retention: retention}).RetentionDuration(); err == nil {
+			t.Errorf("RetentionDuration(%q) succeeded, want an error", retention)
+		}
+	}
+}
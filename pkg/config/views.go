@@ -0,0 +1,151 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	yamlv3 "gopkg.in/yaml.v3"
+	"sigs.k8s.io/yaml"
+)
+
+// The tables whose layout can be configured.
+const (
+	PodsView  = "pods"
+	NodesView = "nodes"
+)
+
+// TableView is the layout of a table: which columns it shows, in which order,
+// and how its rows are sorted.
+type TableView struct {
+	// Columns lists the visible columns in order, the default ones when empty.
+	Columns []string `json:"columns,omitempty"`
+	// Wide shows every column, like kubectl get -o wide.
+	Wide bool `json:"wide,omitempty"`
+	// SortBy is the column the rows are sorted by, the API order when empty.
+	SortBy     string `json:"sortBy,omitempty"`
+	Descending bool   `json:"descending,omitempty"`
+}
+
+// View returns the layout of the table name, adding an empty one to the
+// configuration when there is none.
+func (c *Config) View(name string) *TableView {
+	if c.Views == nil {
+		c.Views = make(map[string]*TableView)
+	}
+	if c.Views[name] == nil {
+		c.Views[name] = &TableView{}
+	}
+	return c.Views[name]
+}
+
+// SaveViews writes views to the configuration at configPath, replacing only
+// its views section so the rest of the file and its comments are kept. It
+// does nothing when configPath is empty.
+func SaveViews(configPath string, views map[string]*TableView) error {
+	if configPath == "" {
+		return nil
+	}
+
+	var document yamlv3.Node
+	data, err := os.ReadFile(configPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read config %s: %v", configPath, err)
+	}
+	if err := yamlv3.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("failed to parse config %s: %v", configPath, err)
+	}
+	if len(document.Content) == 0 {
+		document = yamlv3.Node{Kind: yamlv3.DocumentNode, Content: []*yamlv3.Node{{Kind: yamlv3.MappingNode}}}
+	}
+	root := document.Content[0]
+	if root.Kind != yamlv3.MappingNode {
+		return fmt.Errorf("failed to update config %s: it is not a mapping", configPath)
+	}
+
+	// The views go through sigs.k8s.io/yaml first, so they are written with
+	// the same keys Load reads.
+	viewsData, err := yaml.Marshal(views)
+	if err != nil {
+		return fmt.Errorf("failed to encode views: %v", err)
+	}
+	var viewsDocument yamlv3.Node
+	if err := yamlv3.Unmarshal(viewsData, &viewsDocument); err != nil {
+		return fmt.Errorf("failed to encode views: %v", err)
+	}
+	value := viewsDocument.Content[0]
+
+	replaced := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "views" {
+			root.Content[i+1] = value
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		root.Content = append(root.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: "views"}, value)
+	}
+
+	var buffer bytes.Buffer
+	encoder := yamlv3.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return fmt.Errorf("failed to encode config %s: %v", configPath, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode config %s: %v", configPath, err)
+	}
+	return writeFile(configPath, buffer.Bytes())
+}
+
+// writeFile replaces the file at filePath with data through a temporary file,
+// so an interrupted write cannot leave a truncated configuration. A symlink is
+// followed, so the file it points to is replaced rather than the link, and
+// the mode of the file is kept.
+func writeFile(filePath string, data []byte) error {
+	mode := os.FileMode(0o600)
+	if target, err := filepath.EvalSymlinks(filePath); err == nil {
+		filePath = target
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to write config %s: %v", filePath, err)
+		}
+		mode = info.Mode().Perm()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to write config %s: %v", filePath, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+	file, err := os.CreateTemp(filepath.Dir(filePath), ".config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to write config %s: %v", filePath, err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write config %s: %v", filePath, err)
+	}
+	if err := file.Chmod(mode); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write config %s: %v", filePath, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write config %s: %v", filePath, err)
+	}
+	if err := os.Rename(file.Name(), filePath); err != nil {
+		return fmt.Errorf("failed to write config %s: %v", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSaveViewsKeepsTheRestOfTheFile(t *testing.T) {
+	configPath := writeConfig(t, `# kubepulse settings
+protectedContexts:
+  # production needs care
+  - pattern: "prod-*"
+    banner: PRODUCTION
+views:
+  nodes:
+    wide: true # replaced
+rightSizing:
+  percentile: 95 # the busy hours
+`)
+	views := map[string]*TableView{
+		PodsView:  {Columns: []string{"name", "status"}, SortBy: "restarts", Descending: true},
+		NodesView: {Wide: false},
+	}
+	if err := SaveViews(configPath, views); err != nil {
+		t.Fatalf("SaveViews: %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# kubepulse settings", "# production needs care", "percentile: 95 # the busy hours"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("saved config is missing %q:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "# replaced") {
+		t.Errorf("saved config kept the old views:\n%s", data)
+	}
+
+	config, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("loading the saved config: %v", err)
+	}
+	if !reflect.DeepEqual(config.Views, views) {
+		t.Errorf("views = %+v, want %+v", config.Views, views)
+	}
+	if protection := config.ProtectionFor("prod-eu"); protection == nil || protection.Banner != "PRODUCTION" {
+		t.Errorf("protection of prod-eu = %+v, want the PRODUCTION banner", protection)
+	}
+	if *config.RightSizing.Percentile != 95 {
+		t.Errorf("percentile = %d, want 95", *config.RightSizing.Percentile)
+	}
+}
+
+func TestSaveViewsCreatesTheFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "kubepulse", "config.yaml")
+	views := map[string]*TableView{PodsView: {Wide: true}}
+	if err := SaveViews(configPath, views); err != nil {
+		t.Fatalf("SaveViews: %v", err)
+	}
+	config, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("loading the saved config: %v", err)
+	}
+	if !reflect.DeepEqual(config.Views, views) {
+		t.Errorf("views = %+v, want %+v", config.Views, views)
+	}
+}
+
+func TestSaveViewsFollowsSymlinksAndKeepsTheMode(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "kubepulse.yaml")
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("rightSizing:\n  headroom: 20\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.Symlink(target, configPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SaveViews(configPath, map[string]*TableView{NodesView: {Wide: true}}); err != nil {
+		t.Fatalf("SaveViews: %v", err)
+	}
+	if info, err := os.Lstat(configPath); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("config is no longer a symlink: %v, %v", info, err)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o640 {
+		t.Errorf("mode = %v, want -rw-r-----", mode)
+	}
+	if data, _ := os.ReadFile(target); !strings.Contains(string(data), "wide: true") || !strings.Contains(string(data), "headroom: 20") {
+		t.Errorf("target of the symlink = %s, want the views added", data)
+	}
+}
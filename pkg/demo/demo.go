@@ -0,0 +1,396 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+// Package demo provides a synthetic in-memory cluster, so KubePulse can be
+// explored, demonstrated and developed without access to a real cluster.
+package demo
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+const (
+	ContextName = "kubepulse-demo"
+	UserName    = "demo-user"
+
+	kubeletVersion = "v1.31.1"
+	nameAlphabet   = "bcdfghjklmnpqrstvwxz2456789"
+)
+
+type podState int
+
+const (
+	stateRunning podState = iota
+	statePending
+	stateCreating
+	stateCrashLoop
+	stateImagePull
+	stateCompleted
+	stateFailed
+)
+
+// workload describes a group of generated pods. States are applied to the
+// replicas in order; the remaining replicas are running.
+type workload struct {
+	namespace string
+	name      string
+	kind      string
+	replicas  int
+	image     string
+	cpu       int64 // typical usage in millicores
+	memory    int64 // typical usage in MiB
+	// memoryLimit is the memory limit in MiB, twice the typical usage when
+	// zero.
+	memoryLimit int64
+	states      []podState
+	logs        []string
+}
+
+// pod is the generated state behind one pod object.
+type pod struct {
+	namespace string
+	name      string
+	container string
+	node      string
+	state     podState
+	started   time.Time
+	cpu       int64
+	memory    int64
+	phase     float64
+	interval  time.Duration
+	logs      []string
+}
+
+// Cluster is a synthetic cluster backed by fake clientsets. Metrics follow
+// slow waves over time and pod logs grow as time passes.
+type Cluster struct {
+	now            func() time.Time
+	started        time.Time
+	nodes          []string
+	templateHashes map[string]string // pod-template-hash of each ReplicaSet workload
+	mu             sync.Mutex
+	pods           map[string]*pod
+	clientset      *clientset
+	metrics        *metricsfake.Clientset
+	dynamic        *dynamicfake.FakeDynamicClient
+}
+
+// NewClient returns a KubePulse client connected to a new synthetic cluster.
+func NewClient() *kubernetes.Client {
+	return NewCluster(time.Now, time.Now().UnixNano()).Client()
+}
+
+// NewCluster generates a cluster from seed, so equal seeds produce equal pod
+// names and placements. now is the clock metrics and logs are computed from.
+func NewCluster(now func() time.Time, seed int64) *Cluster {
+	cluster := &Cluster{
+		now:            now,
+		started:        now(),
+		templateHashes: make(map[string]string),
+		pods:           make(map[string]*pod),
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	var objects []runtime.Object
+	for _, namespace := range []string{"default", "kube-system", "monitoring", "payments", "frontend"} {
+		objects = append(objects, &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace, CreationTimestamp: metav1.NewTime(cluster.started.Add(-30 * 24 * time.Hour))},
+			Status:     v1.NamespaceStatus{Phase: v1.NamespaceActive},
+		})
+	}
+
+	objects = append(objects, &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "compute"},
+		Spec: v1.ResourceQuotaSpec{Hard: v1.ResourceList{
+			v1.ResourceRequestsCPU:    resource.MustParse("4"),
+			v1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+		}},
+	})
+
+	cluster.nodes = []string{"demo-control-plane", "demo-worker-1", "demo-worker-2", "demo-worker-3"}
+	for i, name := range cluster.nodes {
+		objects = append(objects, cluster.newNode(name, i == 0))
+	}
+
+	for _, w := range workloads {
+		for i := 0; i < w.replicas; i++ {
+			state := stateRunning
+			if i < len(w.states) {
+				state = w.states[i]
+			}
+			objects = append(objects, cluster.newPod(rng, w, i, state))
+		}
+	}
+
+	cluster.clientset = &clientset{Clientset: fake.NewSimpleClientset(objects...), cluster: cluster}
+	cluster.clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: kubeletVersion}
+	cluster.metrics = metricsfake.NewSimpleClientset()
+	cluster.dynamic = dynamicfake.NewSimpleDynamicClient(scheme.Scheme, objects...)
+	cluster.addReactors()
+	return cluster
+}
+
+// Client returns a KubePulse client for the cluster, in the default namespace.
+func (cluster *Cluster) Client() *kubernetes.Client {
+	client := kubernetes.NewClientFromInterfaces(cluster.clientset, cluster.metrics, cluster.dynamic, "default")
+	client.SetIdentity(ContextName, UserName)
+	return client
+}
+
+func (cluster *Cluster) newNode(name string, controlPlane bool) *v1.Node {
+	labels := map[string]string{
+		"kubernetes.io/hostname": name,
+		"kubernetes.io/os":       "linux",
+		"kubernetes.io/arch":     "amd64",
+	}
+	var taints []v1.Taint
+	if controlPlane {
+		labels["node-role.kubernetes.io/control-plane"] = ""
+		taints = append(taints, v1.Taint{Key: "node-role.kubernetes.io/control-plane", Effect: v1.TaintEffectNoSchedule})
+	}
+	capacity := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("16Gi"),
+		v1.ResourcePods:   resource.MustParse("110"),
+	}
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, CreationTimestamp: metav1.NewTime(cluster.started.Add(-30 * 24 * time.Hour))},
+		Spec:       v1.NodeSpec{Taints: taints},
+		Status: v1.NodeStatus{
+			Capacity:    capacity,
+			Allocatable: capacity,
+			Conditions: []v1.NodeCondition{
+				{Type: v1.NodeReady, Status: v1.ConditionTrue, Reason: "KubeletReady", Message: "kubelet is posting ready status"},
+			},
+			NodeInfo: v1.NodeSystemInfo{KubeletVersion: kubeletVersion, OSImage: "Ubuntu 24.04 LTS", ContainerRuntimeVersion: "containerd://1.7.22"},
+		},
+	}
+}
+
+func (cluster *Cluster) newPod(rng *rand.Rand, w workload, index int, state podState) *v1.Pod {
+	name, node, owner := cluster.placePod(rng, w, index)
+	if state == statePending {
+		node = ""
+	}
+	age := time.Duration(10+rng.Intn(72*60)) * time.Minute
+	p := &pod{
+		namespace: w.namespace,
+		name:      name,
+		container: w.name,
+		node:      node,
+		state:     state,
+		started:   cluster.started.Add(-age),
+		cpu:       w.cpu,
+		memory:    w.memory,
+		phase:     rng.Float64() * 6.28,
+		interval:  time.Duration(2+rng.Intn(4)) * time.Second,
+		logs:      w.logs,
+	}
+	cluster.pods[w.namespace+"/"+name] = p
+
+	requests := v1.ResourceList{
+		v1.ResourceCPU:    *resource.NewMilliQuantity(w.cpu*3/2, resource.DecimalSI),
+		v1.ResourceMemory: *resource.NewQuantity(w.memory*3/2*1024*1024, resource.BinarySI),
+	}
+	memoryLimit := w.memoryLimit
+	if memoryLimit == 0 {
+		memoryLimit = w.memory * 2
+	}
+	limits := v1.ResourceList{
+		v1.ResourceCPU:    *resource.NewMilliQuantity(w.cpu*4, resource.DecimalSI),
+		v1.ResourceMemory: *resource.NewQuantity(memoryLimit*1024*1024, resource.BinarySI),
+	}
+	object := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         w.namespace,
+			Labels:            map[string]string{"app": w.name},
+			CreationTimestamp: metav1.NewTime(p.started),
+		},
+		Spec: v1.PodSpec{
+			NodeName: node,
+			Containers: []v1.Container{{
+				Name:      w.name,
+				Image:     w.image,
+				Resources: v1.ResourceRequirements{Requests: requests, Limits: limits},
+			}},
+		},
+	}
+	if owner != nil {
+		object.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	if w.kind == "ReplicaSet" {
+		object.Labels["pod-template-hash"] = cluster.templateHashes[w.namespace+"/"+w.name]
+	}
+	if w.kind == "Node" {
+		object.Annotations = map[string]string{v1.MirrorPodAnnotationKey: "demo"}
+	}
+	setPodStatus(object, p, rng)
+	return object
+}
+
+// placePod names the pod after its controller, like the real controllers do,
+// and picks its node.
+func (cluster *Cluster) placePod(rng *rand.Rand, w workload, index int) (string, string, *metav1.OwnerReference) {
+	workers := cluster.nodes[1:]
+	node := workers[rng.Intn(len(workers))]
+	controller := true
+	owner := &metav1.OwnerReference{APIVersion: "apps/v1", Kind: w.kind, Name: w.name, Controller: &controller}
+
+	switch w.kind {
+	case "ReplicaSet":
+		key := w.namespace + "/" + w.name
+		if cluster.templateHashes[key] == "" {
+			cluster.templateHashes[key] = randomName(rng, 10)
+		}
+		owner.Name = fmt.Sprintf("%s-%s", w.name, cluster.templateHashes[key])
+		return fmt.Sprintf("%s-%s", owner.Name, randomName(rng, 5)), node, owner
+	case "StatefulSet":
+		return fmt.Sprintf("%s-%d", w.name, index), node, owner
+	case "DaemonSet":
+		node = cluster.nodes[index%len(cluster.nodes)]
+		return fmt.Sprintf("%s-%s", w.name, randomName(rng, 5)), node, owner
+	case "Job":
+		owner.APIVersion = "batch/v1"
+		return fmt.Sprintf("%s-%s", w.name, randomName(rng, 5)), node, owner
+	case "Node":
+		node = cluster.nodes[0]
+		owner.APIVersion = "v1"
+		owner.Name = node
+		return fmt.Sprintf("%s-%s", w.name, node), node, owner
+	default:
+		return fmt.Sprintf("%s-%s", w.name, randomName(rng, 5)), node, nil
+	}
+}
+
+func setPodStatus(object *v1.Pod, p *pod, rng *rand.Rand) {
+	containerName := object.Spec.Containers[0].Name
+	started := metav1.NewTime(p.started)
+	status := v1.ContainerStatus{Name: containerName, Image: object.Spec.Containers[0].Image}
+	object.Status.StartTime = &started
+
+	switch p.state {
+	case statePending:
+		object.Status.StartTime = nil
+		object.Status.Phase = v1.PodPending
+		object.Status.Conditions = []v1.PodCondition{{
+			Type:    v1.PodScheduled,
+			Status:  v1.ConditionFalse,
+			Reason:  v1.PodReasonUnschedulable,
+			Message: "0/4 nodes are available: 1 node(s) had untolerated taint {node-role.kubernetes.io/control-plane: }, 3 Insufficient memory.",
+		}}
+		return
+	case stateCreating:
+		object.Status.Phase = v1.PodPending
+		status.State.Waiting = &v1.ContainerStateWaiting{Reason: "ContainerCreating"}
+	case stateCrashLoop:
+		object.Status.Phase = v1.PodRunning
+		status.RestartCount = int32(5 + rng.Intn(40))
+		status.State.Waiting = &v1.ContainerStateWaiting{
+			Reason:  "CrashLoopBackOff",
+			Message: fmt.Sprintf("back-off 5m0s restarting failed container=%s pod=%s", containerName, p.name),
+		}
+		status.LastTerminationState.Terminated = &v1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}
+	case stateImagePull:
+		object.Status.Phase = v1.PodPending
+		status.State.Waiting = &v1.ContainerStateWaiting{
+			Reason:  "ImagePullBackOff",
+			Message: fmt.Sprintf("Back-off pulling image %q", status.Image),
+		}
+	case stateCompleted:
+		object.Status.Phase = v1.PodSucceeded
+		status.State.Terminated = &v1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed", StartedAt: started}
+	case stateFailed:
+		object.Status.Phase = v1.PodFailed
+		status.State.Terminated = &v1.ContainerStateTerminated{ExitCode: 2, Reason: "Error", StartedAt: started}
+	default:
+		object.Status.Phase = v1.PodRunning
+		status.Ready = true
+		status.Started = &status.Ready
+		status.State.Running = &v1.ContainerStateRunning{StartedAt: started}
+		object.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+	}
+	object.Status.ContainerStatuses = []v1.ContainerStatus{status}
+}
+
+// addReactors serves metrics from the generated usage, grants the demo user
+// every permission and makes evictions remove the pod.
+func (cluster *Cluster) addReactors() {
+	cluster.metrics.PrependReactor("get", "nodes", cluster.getNodeMetrics)
+	cluster.metrics.PrependReactor("list", "nodes", cluster.listNodeMetrics)
+	cluster.metrics.PrependReactor("get", "pods", cluster.getPodMetrics)
+	cluster.metrics.PrependReactor("list", "pods", cluster.listPodMetrics)
+
+	cluster.clientset.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &authorizationv1.SelfSubjectRulesReview{
+			Status: authorizationv1.SubjectRulesReviewStatus{
+				ResourceRules: []authorizationv1.ResourceRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}},
+			},
+		}, nil
+	})
+	cluster.clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &authorizationv1.SelfSubjectAccessReview{
+			Status: authorizationv1.SubjectAccessReviewStatus{Allowed: true, Reason: "demo cluster"},
+		}, nil
+	})
+	cluster.clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(metav1.Object)
+		podsResource := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+		if err := cluster.clientset.Tracker().Delete(podsResource, action.GetNamespace(), eviction.GetName()); err != nil {
+			return true, nil, err
+		}
+		cluster.removePod(action.GetNamespace(), eviction.GetName())
+		return true, nil, nil
+	})
+	cluster.clientset.PrependReactor("list", "pods", kubernetes.ListPodsReactor(cluster.clientset.Tracker()))
+	cluster.clientset.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		cluster.removePod(action.GetNamespace(), action.(k8stesting.DeleteAction).GetName())
+		return false, nil, nil
+	})
+}
+
+func (cluster *Cluster) pod(namespace string, name string) (*pod, bool) {
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	p, ok := cluster.pods[namespace+"/"+name]
+	return p, ok
+}
+
+func (cluster *Cluster) removePod(namespace string, name string) {
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	delete(cluster.pods, namespace+"/"+name)
+}
+
+func randomName(rng *rand.Rand, length int) string {
+	name := make([]byte, length)
+	for i := range name {
+		name[i] = nameAlphabet[rng.Intn(len(nameAlphabet))]
+	}
+	return string(name)
+}
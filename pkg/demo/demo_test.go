@@ -0,0 +1,216 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package demo
+
+import (
+	"bufio"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// testClock is a clock tests advance by hand.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestCluster(t *testing.T) (*Cluster, *kubernetes.Client, *testClock) {
+	t.Helper()
+	clock := &testClock{now: time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)}
+	cluster := NewCluster(clock.Now, 1)
+	return cluster, cluster.Client(), clock
+}
+
+// findPod returns the name of the first generated pod in namespace whose name
+// starts with prefix and that is in state.
+func findPod(t *testing.T, cluster *Cluster, namespace string, prefix string, state podState) kubernetes.Pod {
+	t.Helper()
+	for _, p := range cluster.pods {
+		if p.namespace == namespace && strings.HasPrefix(p.name, prefix) && p.state == state {
+			return kubernetes.Pod{Name: p.name, Namespace: p.namespace, NodeName: p.node}
+		}
+	}
+	t.Fatalf("no pod %s/%s* in state %d", namespace, prefix, state)
+	return kubernetes.Pod{}
+}
+
+func TestClusterIsDeterministic(t *testing.T) {
+	first := NewCluster(time.Now, 42)
+	second := NewCluster(time.Now, 42)
+	for key, p := range first.pods {
+		other, ok := second.pods[key]
+		if !ok || other.node != p.node || other.state != p.state {
+			t.Fatalf("pod %s differs between clusters with the same seed", key)
+		}
+	}
+}
+
+func TestClusterListing(t *testing.T) {
+	_, client, _ := newTestCluster(t)
+
+	nodes, err := client.GetNodes()
+	if err != nil || len(nodes) != 4 {
+		t.Fatalf("GetNodes = %v, %v, want 4 nodes", nodes, err)
+	}
+	namespaces, err := client.ListNamespaces()
+	if err != nil || len(namespaces) != 5 {
+		t.Fatalf("ListNamespaces = %v, %v, want 5 namespaces", namespaces, err)
+	}
+
+	client.SetNamespace("")
+	pods, err := client.GetPods()
+	if err != nil {
+		t.Fatalf("GetPods: %v", err)
+	}
+	if len(pods) != 33 {
+		t.Errorf("GetPods returned %d pods, want 33", len(pods))
+	}
+	// Every field the API server selects on is applied, not only the node.
+	if pods, err := client.ListPods("", kubernetes.Filter{Fields: "spec.hostNetwork=false"}); err != nil || len(pods) != 33 {
+		t.Errorf("ListPods with spec.hostNetwork=false = %d pods, %v, want 33", len(pods), err)
+	}
+
+	nodePods, err := client.GetPodsByNode("demo-control-plane")
+	if err != nil {
+		t.Fatalf("GetPodsByNode: %v", err)
+	}
+	for _, pod := range nodePods {
+		if pod.NodeName != "demo-control-plane" {
+			t.Errorf("GetPodsByNode returned %s on %s", pod.Name, pod.NodeName)
+		}
+	}
+}
+
+func TestMetricsFluctuate(t *testing.T) {
+	cluster, client, clock := newTestCluster(t)
+	pod := findPod(t, cluster, "default", "api-", stateRunning)
+
+	seen := map[string]bool{}
+	for i := 0; i < 10; i++ {
+		cpu, _, err := client.GetPodMetrics(pod)
+		if err != nil {
+			t.Fatalf("GetPodMetrics: %v", err)
+		}
+		seen[cpu] = true
+		clock.Advance(7 * time.Second)
+	}
+	if len(seen) < 3 {
+		t.Errorf("pod CPU took only the values %v over 70s", seen)
+	}
+
+	if _, _, err := client.GetNodeMetrics("demo-worker-1"); err != nil {
+		t.Errorf("GetNodeMetrics: %v", err)
+	}
+	pending := findPod(t, cluster, "default", "worker-", statePending)
+	if _, _, err := client.GetPodMetrics(pending); !apierrors.IsNotFound(err) {
+		t.Errorf("GetPodMetrics of a pending pod = %v, want not found", err)
+	}
+}
+
+func TestLogsGrowOverTime(t *testing.T) {
+	cluster, client, clock := newTestCluster(t)
+	pod := findPod(t, cluster, "default", "web-", stateRunning)
+
+	before, err := client.GetPodLogs(pod)
+	if err != nil {
+		t.Fatalf("GetPodLogs: %v", err)
+	}
+	clock.Advance(time.Minute)
+	after, err := client.GetPodLogs(pod)
+	if err != nil {
+		t.Fatalf("GetPodLogs: %v", err)
+	}
+	beforeLines := strings.Split(strings.TrimSpace(before), "\n")
+	afterLines := strings.Split(strings.TrimSpace(after), "\n")
+	last := beforeLines[len(beforeLines)-1]
+	if !strings.Contains(after, last) || afterLines[len(afterLines)-1] == last {
+		t.Errorf("logs did not grow after a minute")
+	}
+
+	crashing := findPod(t, cluster, "default", "api-", stateCrashLoop)
+	logs, err := client.GetPodLogs(crashing)
+	if err != nil || !strings.Contains(logs, "panic:") {
+		t.Errorf("GetPodLogs of a crashing pod = %q, %v, want a panic", logs, err)
+	}
+
+	pending := findPod(t, cluster, "default", "worker-", statePending)
+	if _, err := client.GetPodLogs(pending); !apierrors.IsBadRequest(err) {
+		t.Errorf("GetPodLogs of a pending pod = %v, want bad request", err)
+	}
+}
+
+func TestFollowStreamsNewLines(t *testing.T) {
+	cluster, _, _ := newTestCluster(t)
+	pod := findPod(t, cluster, "default", "web-", stateRunning)
+	tail := int64(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := cluster.clientset.CoreV1().Pods(pod.Namespace).
+		GetLogs(pod.Name, &v1.PodLogOptions{Follow: true, TailLines: &tail}).
+		Stream(ctx)
+	if err != nil {
+		t.Fatalf("streaming logs: %v", err)
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	for i := 0; i < 2; i++ {
+		if !scanner.Scan() {
+			t.Fatalf("stream ended after %d lines: %v", i, scanner.Err())
+		}
+	}
+}
+
+func TestDeleteAndEvictRemovePods(t *testing.T) {
+	cluster, client, _ := newTestCluster(t)
+	web := findPod(t, cluster, "default", "web-", stateRunning)
+	api := findPod(t, cluster, "default", "api-", stateRunning)
+
+	if err := client.DeletePod(web, false); err != nil {
+		t.Fatalf("DeletePod: %v", err)
+	}
+	if err := client.EvictPod(api); err != nil {
+		t.Fatalf("EvictPod: %v", err)
+	}
+	pods, _ := client.GetPods()
+	for _, pod := range pods {
+		if pod.Name == web.Name || pod.Name == api.Name {
+			t.Errorf("pod %s still listed after removal", pod.Name)
+		}
+	}
+	if _, _, err := client.GetPodMetrics(api); !apierrors.IsNotFound(err) {
+		t.Errorf("metrics of an evicted pod = %v, want not found", err)
+	}
+}
+
+func TestDemoUserMayDoEverything(t *testing.T) {
+	_, client, _ := newTestCluster(t)
+	if !client.CanI("delete", kubernetes.PodResource, "", "default") || !client.CanI("patch", kubernetes.NodeResource, "", "") {
+		t.Errorf("the demo user is missing permissions")
+	}
+}
@@ -0,0 +1,196 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package demo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	fakerest "k8s.io/client-go/rest/fake"
+)
+
+// maxLogLines bounds the history returned for a pod without TailLines, like
+// the log rotation of a real node would.
+const maxLogLines = 500
+
+// clientset replaces the fixed "fake logs" of the fake clientset with logs
+// generated for each pod.
+type clientset struct {
+	*fake.Clientset
+	cluster *Cluster
+}
+
+func (c *clientset) CoreV1() corev1client.CoreV1Interface {
+	return &coreV1{CoreV1Interface: c.Clientset.CoreV1(), cluster: c.cluster}
+}
+
+type coreV1 struct {
+	corev1client.CoreV1Interface
+	cluster *Cluster
+}
+
+func (c *coreV1) Pods(namespace string) corev1client.PodInterface {
+	return &pods{PodInterface: c.CoreV1Interface.Pods(namespace), namespace: namespace, cluster: c.cluster}
+}
+
+type pods struct {
+	corev1client.PodInterface
+	namespace string
+	cluster   *Cluster
+}
+
+// GetLogs returns the lines the pod has written so far. With Follow set, the
+// stream stays open and a new line arrives at the pod's logging interval.
+func (p *pods) GetLogs(name string, options *v1.PodLogOptions) *rest.Request {
+	client := &fakerest.RESTClient{
+		Client: fakerest.CreateHTTPClient(func(request *http.Request) (*http.Response, error) {
+			if _, err := p.PodInterface.Get(request.Context(), name, metav1.GetOptions{}); err != nil {
+				return errorResponse(err), nil
+			}
+			generated, ok := p.cluster.pod(p.namespace, name)
+			if !ok {
+				return errorResponse(apierrors.NewNotFound(v1.Resource("pods"), name)), nil
+			}
+			if err := generated.logError(); err != nil {
+				return errorResponse(err), nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       p.cluster.logStream(request.Context(), generated, options),
+			}, nil
+		}),
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		GroupVersion:         v1.SchemeGroupVersion,
+		VersionedAPIPath:     fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/log", p.namespace, name),
+	}
+	return client.Request()
+}
+
+// logError returns the error the API server reports for containers that have
+// not started yet.
+func (p *pod) logError() error {
+	switch p.state {
+	case statePending, stateCreating:
+		return apierrors.NewBadRequest(fmt.Sprintf("container %q in pod %q is waiting to start: ContainerCreating", p.container, p.name))
+	case stateImagePull:
+		return apierrors.NewBadRequest(fmt.Sprintf("container %q in pod %q is waiting to start: trying and failing to pull image", p.container, p.name))
+	}
+	return nil
+}
+
+// logLines returns every line p has written up to the given time.
+func (cluster *Cluster) logLines(p *pod, at time.Time) []string {
+	switch p.state {
+	case stateCrashLoop:
+		return []string{
+			p.logLine(0),
+			p.logLine(1),
+			`panic: runtime error: invalid memory address or nil pointer dereference`,
+			`[signal SIGSEGV: segmentation violation code=0x1 addr=0x18 pc=0x6d2f4a]`,
+			``,
+			`goroutine 1 [running]:`,
+			`main.(*OrderService).Reconcile(0x0, {0xc0001a2000, 0x24})`,
+			`	/src/internal/orders/service.go:142 +0x4a`,
+		}
+	case stateCompleted:
+		return []string{p.logLine(0), p.logLine(1), p.logLine(2), "all migrations applied"}
+	case stateFailed:
+		return []string{"starting reconciliation", p.logLine(0), "exiting with status 2"}
+	}
+
+	count := int(at.Sub(p.started) / p.interval)
+	first := 0
+	if count > maxLogLines {
+		first = count - maxLogLines
+	}
+	lines := make([]string, 0, count-first)
+	for i := first; i < count; i++ {
+		lines = append(lines, p.logLine(i))
+	}
+	return lines
+}
+
+// logLine formats line i from the pod's templates. The values vary from line
+// to line but are the same on every read.
+func (p *pod) logLine(i int) string {
+	if len(p.logs) == 0 {
+		return ""
+	}
+	line := p.logs[i%len(p.logs)]
+	if strings.Contains(line, "%d") {
+		line = fmt.Sprintf(line, 3+(i*37+len(p.name)*11)%180)
+	}
+	return p.started.Add(time.Duration(i)*p.interval).UTC().Format(time.RFC3339) + " " + line
+}
+
+func (cluster *Cluster) logStream(ctx context.Context, p *pod, options *v1.PodLogOptions) io.ReadCloser {
+	lines := cluster.logLines(p, cluster.now())
+	if options != nil && options.TailLines != nil && int(*options.TailLines) < len(lines) {
+		lines = lines[len(lines)-int(*options.TailLines):]
+	}
+	history := strings.Join(lines, "\n")
+	if len(lines) > 0 {
+		history += "\n"
+	}
+	if options == nil || !options.Follow || p.state != stateRunning {
+		return io.NopCloser(strings.NewReader(history))
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		next := int(cluster.now().Sub(p.started) / p.interval)
+		if _, err := io.WriteString(writer, history); err != nil {
+			return
+		}
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				writer.CloseWithError(ctx.Err())
+				return
+			case <-ticker.C:
+				if _, err := io.WriteString(writer, p.logLine(next)+"\n"); err != nil {
+					return
+				}
+				next++
+			}
+		}
+	}()
+	return reader
+}
+
+// errorResponse encodes err as the Status object the API server would send.
+func errorResponse(err error) *http.Response {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		status = apierrors.NewInternalError(err)
+	}
+	body := status.Status()
+	body.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	encoded, _ := json.Marshal(body)
+	return &http.Response{
+		StatusCode: int(body.Code),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(string(encoded))),
+	}
+}
@@ -0,0 +1,144 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package demo
+
+import (
+	"math"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+const (
+	// Usage follows a slow and a fast wave, so repeated refreshes show change.
+	slowPeriod   = 90 * time.Second
+	fastPeriod   = 23 * time.Second
+	memoryPeriod = 5 * time.Minute
+
+	nodeOverheadCPU    = 150               // millicores used by the kubelet and system daemons
+	nodeOverheadMemory = 600 * 1024 * 1024 // bytes
+)
+
+var metricsGroupResource = schema.GroupResource{Group: "metrics.k8s.io", Resource: "pods"}
+
+// podUsage returns the CPU in millicores and memory in bytes p uses at the
+// given time. Only running containers report metrics.
+func (cluster *Cluster) podUsage(p *pod, at time.Time) (int64, int64, bool) {
+	if p.state != stateRunning {
+		return 0, 0, false
+	}
+	elapsed := at.Sub(cluster.started).Seconds()
+	wave := 1 + 0.35*math.Sin(2*math.Pi*elapsed/slowPeriod.Seconds()+p.phase) +
+		0.12*math.Sin(2*math.Pi*elapsed/fastPeriod.Seconds()+2*p.phase)
+	memoryWave := 1 + 0.08*math.Sin(2*math.Pi*elapsed/memoryPeriod.Seconds()+p.phase)
+
+	cpu := int64(math.Max(1, float64(p.cpu)*wave))
+	memory := int64(float64(p.memory*1024*1024) * memoryWave)
+	return cpu, memory, true
+}
+
+// nodeUsage adds up the usage of every pod on the node and the system overhead.
+func (cluster *Cluster) nodeUsage(node string, at time.Time) (int64, int64) {
+	cpu, memory := int64(nodeOverheadCPU), int64(nodeOverheadMemory)
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	for _, p := range cluster.pods {
+		if p.node != node {
+			continue
+		}
+		podCPU, podMemory, ok := cluster.podUsage(p, at)
+		if ok {
+			cpu += podCPU
+			memory += podMemory
+		}
+	}
+	return cpu, memory
+}
+
+func (cluster *Cluster) nodeMetrics(node string, at time.Time) metricsv1beta1.NodeMetrics {
+	cpu, memory := cluster.nodeUsage(node, at)
+	return metricsv1beta1.NodeMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: node},
+		Timestamp:  metav1.NewTime(at),
+		Window:     metav1.Duration{Duration: 15 * time.Second},
+		Usage:      usage(cpu, memory),
+	}
+}
+
+func (cluster *Cluster) podMetrics(p *pod, at time.Time) (metricsv1beta1.PodMetrics, bool) {
+	cpu, memory, ok := cluster.podUsage(p, at)
+	if !ok {
+		return metricsv1beta1.PodMetrics{}, false
+	}
+	return metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: p.name, Namespace: p.namespace},
+		Timestamp:  metav1.NewTime(at),
+		Window:     metav1.Duration{Duration: 15 * time.Second},
+		Containers: []metricsv1beta1.ContainerMetrics{{Name: p.container, Usage: usage(cpu, memory)}},
+	}, true
+}
+
+func (cluster *Cluster) getNodeMetrics(action k8stesting.Action) (bool, runtime.Object, error) {
+	name := action.(k8stesting.GetAction).GetName()
+	for _, node := range cluster.nodes {
+		if node == name {
+			metrics := cluster.nodeMetrics(node, cluster.now())
+			return true, &metrics, nil
+		}
+	}
+	return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "metrics.k8s.io", Resource: "nodes"}, name)
+}
+
+func (cluster *Cluster) listNodeMetrics(action k8stesting.Action) (bool, runtime.Object, error) {
+	list := &metricsv1beta1.NodeMetricsList{}
+	now := cluster.now()
+	for _, node := range cluster.nodes {
+		list.Items = append(list.Items, cluster.nodeMetrics(node, now))
+	}
+	return true, list, nil
+}
+
+func (cluster *Cluster) getPodMetrics(action k8stesting.Action) (bool, runtime.Object, error) {
+	get := action.(k8stesting.GetAction)
+	if p, ok := cluster.pod(get.GetNamespace(), get.GetName()); ok {
+		if metrics, ok := cluster.podMetrics(p, cluster.now()); ok {
+			return true, &metrics, nil
+		}
+	}
+	return true, nil, apierrors.NewNotFound(metricsGroupResource, get.GetName())
+}
+
+func (cluster *Cluster) listPodMetrics(action k8stesting.Action) (bool, runtime.Object, error) {
+	list := &metricsv1beta1.PodMetricsList{}
+	now := cluster.now()
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	for _, p := range cluster.pods {
+		if namespace := action.GetNamespace(); namespace != "" && p.namespace != namespace {
+			continue
+		}
+		if metrics, ok := cluster.podMetrics(p, now); ok {
+			list.Items = append(list.Items, metrics)
+		}
+	}
+	return true, list, nil
+}
+
+func usage(millicores int64, bytes int64) v1.ResourceList {
+	return v1.ResourceList{
+		v1.ResourceCPU:    *resource.NewMilliQuantity(millicores, resource.DecimalSI),
+		v1.ResourceMemory: *resource.NewQuantity(bytes, resource.BinarySI),
+	}
+}
@@ -0,0 +1,143 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package demo
+
+// workloads are the applications running in the demo cluster. Log lines may
+// contain one %d verb, filled with a value that changes from line to line.
+var workloads = []workload{
+	{
+		namespace: "kube-system", name: "etcd", kind: "Node", replicas: 1,
+		image: "registry.k8s.io/etcd:3.5.15-0", cpu: 45, memory: 96,
+		logs: []string{
+			`{"level":"info","msg":"finished scheduled compaction","compact-revision":%d}`,
+			`{"level":"info","msg":"saved snapshot","snapshot-index":%d}`,
+		},
+	},
+	{
+		namespace: "kube-system", name: "kube-apiserver", kind: "Node", replicas: 1,
+		image: "registry.k8s.io/kube-apiserver:v1.31.1", cpu: 120, memory: 410,
+		logs: []string{
+			`I1018 httplog.go:134] "HTTP" verb="LIST" URI="/api/v1/pods?limit=500" latency="%dms" resp=200`,
+			`I1018 httplog.go:134] "HTTP" verb="WATCH" URI="/apis/apps/v1/deployments" latency="%dms" resp=200`,
+		},
+	},
+	{
+		namespace: "kube-system", name: "coredns", kind: "ReplicaSet", replicas: 2,
+		image: "registry.k8s.io/coredns/coredns:v1.11.3", cpu: 4, memory: 18,
+		logs: []string{
+			`[INFO] 10.244.1.%d:53211 - "A IN api.default.svc.cluster.local. udp 52 false 512" NOERROR qr,aa,rd 104 0.000132s`,
+			`[INFO] 10.244.2.%d:40122 - "AAAA IN ledger.payments.svc.cluster.local. udp 61 false 512" NOERROR qr,aa,rd 154 0.000098s`,
+		},
+	},
+	{
+		namespace: "kube-system", name: "kube-proxy", kind: "DaemonSet", replicas: 4,
+		image: "registry.k8s.io/kube-proxy:v1.31.1", cpu: 2, memory: 24,
+		logs: []string{
+			`I1018 proxier.go:805] "SyncProxyRules complete" elapsed="%dms"`,
+		},
+	},
+	{
+		namespace: "kube-system", name: "metrics-server", kind: "ReplicaSet", replicas: 1,
+		image: "registry.k8s.io/metrics-server/metrics-server:v0.7.2", cpu: 6, memory: 32,
+		logs: []string{
+			`I1018 server.go:136] "Scraped nodes" count=4 duration="%dms"`,
+		},
+	},
+	{
+		namespace: "default", name: "web", kind: "ReplicaSet", replicas: 3,
+		image: "nginx:1.27", cpu: 25, memory: 48,
+		logs: []string{
+			`10.244.1.12 - - "GET / HTTP/1.1" 200 615 "-" "Mozilla/5.0" %dms`,
+			`10.244.2.31 - - "GET /static/app.js HTTP/1.1" 200 48211 "-" "Mozilla/5.0" %dms`,
+			`10.244.3.7 - - "GET /healthz HTTP/1.1" 200 2 "-" "kube-probe/1.31" %dms`,
+		},
+	},
+	{
+		namespace: "default", name: "api", kind: "ReplicaSet", replicas: 3,
+		image: "ghcr.io/example/api:2.4.1", cpu: 180, memory: 220,
+		states: []podState{stateCrashLoop},
+		logs: []string{
+			`level=info msg="request handled" method=GET path=/v1/orders status=200 duration=%dms`,
+			`level=info msg="request handled" method=POST path=/v1/orders status=201 duration=%dms`,
+			`level=warn msg="slow query" table=orders duration=%dms`,
+		},
+	},
+	{
+		namespace: "default", name: "worker", kind: "ReplicaSet", replicas: 2,
+		image: "ghcr.io/example/worker:2.4.1", cpu: 350, memory: 640,
+		states: []podState{statePending},
+		logs: []string{
+			`level=info msg="job processed" queue=emails attempts=1 duration=%dms`,
+			`level=info msg="job processed" queue=thumbnails attempts=1 duration=%dms`,
+		},
+	},
+	{
+		namespace: "default", name: "db-migrate", kind: "Job", replicas: 1,
+		image: "ghcr.io/example/api:2.4.1", cpu: 10, memory: 40,
+		states: []podState{stateCompleted},
+		logs: []string{
+			`applying migration %d_add_order_index.sql ... ok`,
+		},
+	},
+	{
+		namespace: "monitoring", name: "prometheus", kind: "StatefulSet", replicas: 1,
+		image: "quay.io/prometheus/prometheus:v2.54.1", cpu: 240, memory: 1450, memoryLimit: 1536,
+		logs: []string{
+			`ts=2024-10-18 caller=head.go:1300 level=info component=tsdb msg="Head GC completed" duration=%dms`,
+			`ts=2024-10-18 caller=compact.go:576 level=info component=tsdb msg="write block" duration=%dms`,
+		},
+	},
+	{
+		namespace: "monitoring", name: "grafana", kind: "ReplicaSet", replicas: 1,
+		image: "grafana/grafana:11.2.2", cpu: 15, memory: 180,
+		logs: []string{
+			`logger=context t=2024-10-18 level=info msg="Request Completed" method=GET path=/api/dashboards/uid/cluster status=200 duration=%dms`,
+		},
+	},
+	{
+		namespace: "monitoring", name: "node-exporter", kind: "DaemonSet", replicas: 4,
+		image: "quay.io/prometheus/node-exporter:v1.8.2", cpu: 3, memory: 16,
+		logs: []string{
+			`ts=2024-10-18 caller=node_exporter.go:118 level=info collector=filesystem scrape_duration=%dms`,
+		},
+	},
+	{
+		namespace: "payments", name: "payments-api", kind: "ReplicaSet", replicas: 2,
+		image: "ghcr.io/example/payments:1.9.0", cpu: 95, memory: 310,
+		logs: []string{
+			`{"level":"info","msg":"charge authorized","provider":"stripe","latency_ms":%d}`,
+			`{"level":"info","msg":"refund issued","provider":"stripe","latency_ms":%d}`,
+		},
+	},
+	{
+		namespace: "payments", name: "ledger", kind: "StatefulSet", replicas: 2,
+		image: "ghcr.io/example/ledger:1.9.1-rc1", cpu: 60, memory: 512,
+		states: []podState{stateRunning, stateImagePull},
+		logs: []string{
+			`{"level":"info","msg":"entries committed","batch":%d}`,
+		},
+	},
+	{
+		namespace: "payments", name: "reconcile", kind: "Job", replicas: 1,
+		image: "ghcr.io/example/payments:1.9.0", cpu: 20, memory: 64,
+		states: []podState{stateFailed},
+		logs: []string{
+			`{"level":"error","msg":"reconciliation failed","mismatched_entries":%d}`,
+		},
+	},
+	{
+		namespace: "frontend", name: "storefront", kind: "ReplicaSet", replicas: 4,
+		image: "ghcr.io/example/storefront:5.2.0", cpu: 70, memory: 260,
+		states: []podState{stateCreating},
+		logs: []string{
+			`GET /products 200 in %dms`,
+			`GET /cart 200 in %dms`,
+			`POST /checkout 303 in %dms`,
+		},
+	},
+}
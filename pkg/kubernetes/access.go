@@ -0,0 +1,202 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rdmnl/kubepulse/utils"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// accessCacheTTL bounds how long permission checks are cached, so changes to
+// the user's roles show up without restarting.
+const accessCacheTTL = time.Minute
+
+type accessCache struct {
+	mu      sync.Mutex
+	rules   map[string]cachedRules
+	reviews map[string]cachedReview
+	// pending holds the checks CachedCanI is running in the background.
+	pending map[string]bool
+}
+
+type cachedRules struct {
+	status  authorizationv1.SubjectRulesReviewStatus
+	expires time.Time
+}
+
+type cachedReview struct {
+	allowed bool
+	expires time.Time
+}
+
+// CanI reports whether the current user may perform verb on resource (and
+// subresource, if set) in namespace. An empty namespace checks cluster-wide
+// access. The namespace's SelfSubjectRulesReview answers most checks, falling
+// back to a SelfSubjectAccessReview when it cannot. Checks that fail are
+// reported as allowed and left to the API server to enforce.
+func (c *Client) CanI(verb string, resource APIResource, subresource string, namespace string) bool {
+	if !resource.Namespaced {
+		namespace = ""
+	}
+	key := accessKey(verb, resource, subresource, namespace)
+	now := time.Now()
+
+	c.access.mu.Lock()
+	review, ok := c.access.reviews[key]
+	c.access.mu.Unlock()
+	if ok && now.Before(review.expires) {
+		return review.allowed
+	}
+
+	allowed, err := c.checkAccess(verb, resource, subresource, namespace)
+	if err != nil {
+		utils.Warn(fmt.Sprintf("Error checking access to %s %s: %v", verb, resource, err))
+		return true
+	}
+
+	c.access.mu.Lock()
+	if c.access.reviews == nil {
+		c.access.reviews = make(map[string]cachedReview)
+	}
+	c.access.reviews[key] = cachedReview{allowed: allowed, expires: now.Add(accessCacheTTL)}
+	c.access.mu.Unlock()
+	return allowed
+}
+
+// CachedCanI answers CanI from the cache without calling the API server, so
+// it can run on the UI goroutine. A missing or expired answer is checked in
+// the background, calling done once it is cached. Until then the last answer
+// is reported, or allowed if there is none.
+func (c *Client) CachedCanI(verb string, resource APIResource, subresource string, namespace string, done func()) bool {
+	if !resource.Namespaced {
+		namespace = ""
+	}
+	key := accessKey(verb, resource, subresource, namespace)
+
+	c.access.mu.Lock()
+	defer c.access.mu.Unlock()
+	review, ok := c.access.reviews[key]
+	if ok && time.Now().Before(review.expires) {
+		return review.allowed
+	}
+	if !c.access.pending[key] {
+		if c.access.pending == nil {
+			c.access.pending = make(map[string]bool)
+		}
+		c.access.pending[key] = true
+		go func() {
+			c.CanI(verb, resource, subresource, namespace)
+			c.access.mu.Lock()
+			delete(c.access.pending, key)
+			c.access.mu.Unlock()
+			if done != nil {
+				done()
+			}
+		}()
+	}
+	return !ok || review.allowed
+}
+
+func accessKey(verb string, resource APIResource, subresource string, namespace string) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s", verb, resource.Group, resource.Name, subresource, namespace)
+}
+
+func (c *Client) checkAccess(verb string, resource APIResource, subresource string, namespace string) (bool, error) {
+	if namespace != "" {
+		status, err := c.namespaceRules(namespace)
+		if err != nil {
+			return false, err
+		}
+		if rulesAllow(status.ResourceRules, verb, resource.Group, resource.Name, subresource) {
+			return true, nil
+		}
+		if !status.Incomplete {
+			return false, nil
+		}
+	}
+
+	review, err := c.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(context.TODO(), &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        verb,
+				Group:       resource.Group,
+				Resource:    resource.Name,
+				Subresource: subresource,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+func (c *Client) namespaceRules(namespace string) (authorizationv1.SubjectRulesReviewStatus, error) {
+	now := time.Now()
+
+	c.access.mu.Lock()
+	rules, ok := c.access.rules[namespace]
+	c.access.mu.Unlock()
+	if ok && now.Before(rules.expires) {
+		return rules.status, nil
+	}
+
+	review, err := c.clientset.AuthorizationV1().SelfSubjectRulesReviews().Create(context.TODO(), &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return authorizationv1.SubjectRulesReviewStatus{}, err
+	}
+
+	c.access.mu.Lock()
+	if c.access.rules == nil {
+		c.access.rules = make(map[string]cachedRules)
+	}
+	c.access.rules[namespace] = cachedRules{status: review.Status, expires: now.Add(accessCacheTTL)}
+	c.access.mu.Unlock()
+	return review.Status, nil
+}
+
+// rulesAllow reports whether one of rules grants verb on every object of the
+// resource. Rules limited to resource names do not count.
+func rulesAllow(rules []authorizationv1.ResourceRule, verb string, group string, resource string, subresource string) bool {
+	name := resource
+	if subresource != "" {
+		name = resource + "/" + subresource
+	}
+	for _, rule := range rules {
+		if len(rule.ResourceNames) > 0 {
+			continue
+		}
+		if matchesRule(rule.Verbs, verb) && matchesRule(rule.APIGroups, group) &&
+			(matchesRule(rule.Resources, name) || (subresource != "" && (containsString(rule.Resources, resource+"/*") || containsString(rule.Resources, "*/"+subresource)))) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesRule(values []string, value string) bool {
+	return containsString(values, "*") || containsString(values, value)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
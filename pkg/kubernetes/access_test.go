@@ -0,0 +1,96 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRulesAllow(t *testing.T) {
+	rules := []authorizationv1.ResourceRule{
+		{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}},
+		{Verbs: []string{"*"}, APIGroups: []string{"apps"}, Resources: []string{"deployments", "deployments/*"}},
+		{Verbs: []string{"create"}, APIGroups: []string{"*"}, Resources: []string{"*/eviction"}},
+		{Verbs: []string{"delete"}, APIGroups: []string{""}, Resources: []string{"pods"}, ResourceNames: []string{"web"}},
+		{Verbs: []string{"patch"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+	}
+	tests := []struct {
+		verb, group, resource, subresource string
+		want                               bool
+	}{
+		{"get", "", "pods", "", true},
+		{"get", "", "pods", "log", true},
+		{"get", "", "pods", "exec", false},
+		{"watch", "", "pods", "", false},
+		{"update", "apps", "deployments", "", true},
+		{"patch", "apps", "deployments", "scale", true},
+		{"update", "apps", "statefulsets", "", false},
+		{"create", "", "pods", "eviction", true},
+		{"create", "", "pods", "exec", false},
+		// Rules limited to some names do not grant access to every object.
+		{"delete", "", "pods", "", false},
+		{"patch", "", "nodes", "", true},
+		{"patch", "", "nodes", "status", true},
+	}
+	for _, test := range tests {
+		if got := rulesAllow(rules, test.verb, test.group, test.resource, test.subresource); got != test.want {
+			t.Errorf("rulesAllow(%s %s %s/%s) = %v, want %v", test.verb, test.group, test.resource, test.subresource, got, test.want)
+		}
+	}
+}
+
+func TestCachedCanIChecksInTheBackground(t *testing.T) {
+	client, clientset, _ := newTestClient(t)
+	var reviews atomic.Int32
+	clientset.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		reviews.Add(1)
+		return true, &authorizationv1.SelfSubjectRulesReview{Status: authorizationv1.SubjectRulesReviewStatus{
+			ResourceRules: []authorizationv1.ResourceRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}}},
+		}}, nil
+	})
+
+	done := make(chan struct{}, 2)
+	notify := func() { done <- struct{}{} }
+	// Unknown answers are allowed until the check comes back.
+	if !client.CachedCanI("delete", PodResource, "", "default", notify) {
+		t.Error("CachedCanI before the check = false, want true")
+	}
+	client.CachedCanI("delete", PodResource, "", "default", notify)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the background check never finished")
+	}
+	if client.CachedCanI("delete", PodResource, "", "default", notify) {
+		t.Error("CachedCanI after the check = true, want false")
+	}
+	if !client.CachedCanI("get", PodResource, "", "default", notify) {
+		t.Error("CachedCanI(get pods) = false, want true")
+	}
+	<-done
+	if !client.CachedCanI("get", PodResource, "", "default", nil) {
+		t.Error("CachedCanI(get pods) after the check = false, want true")
+	}
+
+	// The rules of the namespace are cached, and the second CachedCanI while
+	// the first check ran did not start another one.
+	if n := reviews.Load(); n != 1 {
+		t.Errorf("rules reviews = %d, want 1", n)
+	}
+	select {
+	case <-done:
+		t.Error("done was called for a check that was already running")
+	default:
+	}
+}
@@ -10,9 +10,15 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/rdmnl/kubepulse/pkg/audit"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -21,38 +27,110 @@ import (
 
 type KubernetesClient interface {
 	GetNodes() ([]string, error)
+	ListNodes(filter Filter) ([]Node, error)
 	GetNodeMetrics(nodeName string) (cpuUsage string, memoryUsage string, err error)
 	GetPods() ([]Pod, error)
 	GetPodsByNode(nodeName string) ([]Pod, error)
+	ListPods(namespace string, filter Filter) ([]Pod, error)
 	GetPodMetrics(pod Pod) (cpuUsage string, memoryUsage string, err error)
+	ListNodeUsage() ([]NodeUsage, error)
+	ListPodUsage() ([]PodUsage, error)
 	GetPodDetails(pod Pod) (string, error)
 	GetPodLogs(pod Pod) (string, error)
 	SetNamespace(namespace string)
+	Namespace() string
 	ListNamespaces() ([]string, error)
+	ListNamespaceSummaries() ([]NamespaceSummary, error)
+	GetClusterSummary() (ClusterSummary, error)
+	ListAPIResources() ([]APIResource, error)
+	FindAPIResource(name string) (APIResource, error)
+	GetResourceTable(resource APIResource) (*ResourceTable, error)
+	GetResourceYAML(ref ObjectRef, showManagedFields bool) (string, error)
+	DiffResource(ref ObjectRef, manifest string) (string, error)
+	UpdateResource(ref ObjectRef, manifest string) error
+	GetPodContainers(pod Pod) ([]string, error)
+	ExecShell(pod Pod, container string, stdin io.Reader, stdout io.Writer, sizeQueue TerminalSizeQueue) error
+	ListTargetPorts(target PortForwardTarget) ([]int, error)
+	StartPortForward(target PortForwardTarget, localPort int) (PortForward, error)
+	ListPortForwards() []PortForward
+	StopPortForward(id int) error
+	DeletePod(pod Pod, force bool) error
+	EvictPod(pod Pod) error
+	GetReplicas(ref ObjectRef) (int32, error)
+	ScaleWorkload(ref ObjectRef, replicas int32) error
+	RestartWorkload(ref ObjectRef) error
+	SetRolloutPaused(ref ObjectRef, paused bool) error
+	GetRolloutHistory(ref ObjectRef) ([]Revision, error)
+	UndoRollout(ref ObjectRef, revision int64) error
+	GetRolloutStatus(ref ObjectRef) (RolloutStatus, error)
+	CordonNode(nodeName string, cordon bool) error
+	DrainNode(ctx context.Context, nodeName string, options DrainOptions, progress func(DrainEvent)) error
+	AnalyzeDrain(nodeName string, options DrainOptions) (*DrainReport, error)
+	ContextName() string
+	ReadOnly() bool
+	AuditEntries(limit int) ([]audit.Entry, error)
+	CanI(verb string, resource APIResource, subresource string, namespace string) bool
+	CachedCanI(verb string, resource APIResource, subresource string, namespace string, done func()) bool
 }
 
 type Pod struct {
 	Name      string
 	Namespace string
 	NodeName  string
+	// Status is the status kubectl shows, such as Running or CrashLoopBackOff.
+	Status   string
+	Restarts int32
+	IP       string
+	Created  time.Time
+}
+
+// Node is a node with the columns of the node table.
+type Node struct {
+	Name string
+	// Status is Ready or NotReady, followed by SchedulingDisabled when the
+	// node is cordoned.
+	Status     string
+	Roles      string
+	Version    string
+	InternalIP string
+	Created    time.Time
 }
 
 type Client struct {
-	clientset     *kubernetes.Clientset
-	metricsClient *metricsclient.Clientset
+	clientset     kubernetes.Interface
+	metricsClient metricsclient.Interface
+	dynamicClient dynamic.Interface
+	config        *rest.Config
 	namespace     string
+	contextName   string
+	userName      string
+	readOnly      bool
+	auditLog      *audit.Log
+	access        accessCache
+	podSpecs      podSpecCache
+	portForwards  portForwardManager
 }
 
 // NewClient initializes a new Kubernetes client
 func NewClient(kubeconfigPath string, namespace string) (*Client, error) {
 	var config *rest.Config
 	var err error
+	contextName := "in-cluster"
+	userName := "serviceaccount"
 
 	if kubeconfigPath != "" {
 		config, err = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load kubeconfig from %s: %v", kubeconfigPath, err)
 		}
+		rawConfig, err := clientcmd.LoadFromFile(kubeconfigPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kubeconfig from %s: %v", kubeconfigPath, err)
+		}
+		contextName = rawConfig.CurrentContext
+		if kubeContext, ok := rawConfig.Contexts[contextName]; ok {
+			userName = kubeContext.AuthInfo
+		}
 	} else {
 		config, err = rest.InClusterConfig()
 		if err != nil {
@@ -70,13 +148,43 @@ func NewClient(kubeconfigPath string, namespace string) (*Client, error) {
 		return nil, fmt.Errorf("failed to create Kubernetes metrics client: %v", err)
 	}
 
-	return &Client{clientset: clientset, metricsClient: metricsClient, namespace: namespace}, nil
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes dynamic client: %v", err)
+	}
+
+	client := NewClientFromInterfaces(clientset, metricsClient, dynamicClient, namespace)
+	client.config = config
+	client.contextName = contextName
+	client.userName = userName
+	return client, nil
+}
+
+// NewClientFromInterfaces builds a client on top of existing clientsets, such as
+// the fake clientsets used in tests. Exec and port-forwarding need a REST config
+// and are only available on clients created by NewClient.
+func NewClientFromInterfaces(clientset kubernetes.Interface, metricsClient metricsclient.Interface, dynamicClient dynamic.Interface, namespace string) *Client {
+	return &Client{clientset: clientset, metricsClient: metricsClient, dynamicClient: dynamicClient, namespace: namespace}
+}
+
+// Clientset returns the typed clientset the client talks to.
+func (c *Client) Clientset() kubernetes.Interface {
+	return c.clientset
+}
+
+// MetricsClient returns the metrics clientset the client talks to.
+func (c *Client) MetricsClient() metricsclient.Interface {
+	return c.metricsClient
 }
 
 func (c *Client) SetNamespace(namespace string) {
 	c.namespace = namespace
 }
 
+func (c *Client) Namespace() string {
+	return c.namespace
+}
+
 func (c *Client) GetNodes() ([]string, error) {
 	nodes, err := c.clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
@@ -91,6 +199,65 @@ func (c *Client) GetNodes() ([]string, error) {
 	return nodeNames, nil
 }
 
+// ListNodes lists the nodes passing filter with their status, roles, version
+// and address.
+func (c *Client) ListNodes(filter Filter) ([]Node, error) {
+	nodes, err := c.clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: filter.Labels,
+		FieldSelector: filter.Fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var nodeList []Node
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if !filter.Matches(node.Name, node.Labels, nodeFields(node)) {
+			continue
+		}
+		status := "NotReady"
+		if isNodeReady(node) {
+			status = "Ready"
+		}
+		if node.Spec.Unschedulable {
+			status += ",SchedulingDisabled"
+		}
+		var internalIP string
+		for _, address := range node.Status.Addresses {
+			if address.Type == v1.NodeInternalIP {
+				internalIP = address.Address
+				break
+			}
+		}
+		nodeList = append(nodeList, Node{
+			Name:       node.Name,
+			Status:     status,
+			Roles:      nodeRoles(node),
+			Version:    node.Status.NodeInfo.KubeletVersion,
+			InternalIP: internalIP,
+			Created:    node.CreationTimestamp.Time,
+		})
+	}
+	return nodeList, nil
+}
+
+// nodeRoles lists the roles of node from its node-role.kubernetes.io labels,
+// or "<none>" like kubectl.
+func nodeRoles(node *v1.Node) string {
+	var roles []string
+	for label := range node.Labels {
+		if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok && role != "" {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		return "<none>"
+	}
+	sort.Strings(roles)
+	return strings.Join(roles, ",")
+}
+
 func (c *Client) GetNodeMetrics(nodeName string) (cpuUsage string, memoryUsage string, err error) {
 	nodeMetrics, err := c.metricsClient.MetricsV1beta1().NodeMetricses().Get(context.TODO(), nodeName, metav1.GetOptions{})
 	if err != nil {
@@ -103,43 +270,91 @@ func (c *Client) GetNodeMetrics(nodeName string) (cpuUsage string, memoryUsage s
 	totalCPU := cpuQuantity.MilliValue()
 	totalMemory := memoryQuantity.Value()
 
-	return fmt.Sprintf("%dm", totalCPU), fmt.Sprintf("%dMi", totalMemory/(1024*1024)), nil
+	return FormatCPU(totalCPU), FormatMemory(totalMemory), nil
 }
 
 func (c *Client) GetPods() ([]Pod, error) {
-	pods, err := c.clientset.CoreV1().Pods(c.namespace).List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
-	var podList []Pod
-	for _, pod := range pods.Items {
-		podList = append(podList, Pod{
-			Name:      pod.Name,
-			Namespace: pod.Namespace,
-			NodeName:  pod.Spec.NodeName,
-		})
-	}
-	return podList, nil
+	return c.ListPods(c.namespace, Filter{})
 }
 
 func (c *Client) GetPodsByNode(nodeName string) ([]Pod, error) {
-	pods, err := c.clientset.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{
-		FieldSelector: fmt.Sprintf("spec.nodeName=%s", nodeName),
+	return c.ListPods("", Filter{}.OnNode(nodeName))
+}
+
+// ListPods lists the pods of namespace, or of every namespace when it is
+// empty, that pass filter.
+func (c *Client) ListPods(namespace string, filter Filter) ([]Pod, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: filter.Labels,
+		FieldSelector: filter.Fields,
 	})
 	if err != nil {
 		return nil, err
 	}
 	var podList []Pod
-	for _, pod := range pods.Items {
-		podList = append(podList, Pod{
-			Name:      pod.Name,
-			Namespace: pod.Namespace,
-			NodeName:  pod.Spec.NodeName,
-		})
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if filter.Matches(pod.Name, pod.Labels, PodFieldSet(pod)) {
+			podList = append(podList, toPod(pod))
+		}
 	}
 	return podList, nil
 }
 
+// toPod converts pod to the fields the pod table shows.
+func toPod(pod *v1.Pod) Pod {
+	var restarts int32
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+	}
+	return Pod{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		NodeName:  pod.Spec.NodeName,
+		Status:    podStatus(pod),
+		Restarts:  restarts,
+		IP:        pod.Status.PodIP,
+		Created:   pod.CreationTimestamp.Time,
+	}
+}
+
+// podStatus summarizes the state of pod the way kubectl get pods does: the
+// reason a container is waiting or terminated wins over the phase.
+func podStatus(pod *v1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	status := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		status = pod.Status.Reason
+	}
+	// Sidecars keep running as init containers once the pod has started.
+	if pod.Status.Phase == v1.PodPending {
+		for i, container := range pod.Status.InitContainerStatuses {
+			switch {
+			case container.State.Waiting != nil && container.State.Waiting.Reason != "" && container.State.Waiting.Reason != "PodInitializing":
+				return "Init:" + container.State.Waiting.Reason
+			case container.State.Terminated != nil && container.State.Terminated.ExitCode != 0:
+				return "Init:Error"
+			case container.State.Running != nil:
+				return fmt.Sprintf("Init:%d/%d", i, len(pod.Spec.InitContainers))
+			}
+		}
+	}
+	for _, container := range pod.Status.ContainerStatuses {
+		switch {
+		case container.State.Waiting != nil && container.State.Waiting.Reason != "":
+			status = container.State.Waiting.Reason
+		case container.State.Terminated != nil && container.State.Terminated.Reason != "":
+			status = container.State.Terminated.Reason
+		}
+	}
+	if status == "" {
+		return "Unknown"
+	}
+	return status
+}
+
 func (c *Client) GetPodMetrics(pod Pod) (cpuUsage string, memoryUsage string, err error) {
 	podMetrics, err := c.metricsClient.MetricsV1beta1().PodMetricses(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
 	if err != nil {
@@ -154,7 +369,7 @@ func (c *Client) GetPodMetrics(pod Pod) (cpuUsage string, memoryUsage string, er
 		totalMemory += memoryQuantity.Value()
 	}
 
-	return fmt.Sprintf("%dm", totalCPU), fmt.Sprintf("%dMi", totalMemory/(1024*1024)), nil
+	return FormatCPU(totalCPU), FormatMemory(totalMemory), nil
 }
 
 func (c *Client) GetPodDetails(pod Pod) (string, error) {
@@ -169,39 +384,8 @@ func (c *Client) GetPodDetails(pod Pod) (string, error) {
 	details += fmt.Sprintf("[lightcyan]Status:[-] %s\n", podObj.Status.Phase)
 	details += fmt.Sprintf("[lightcyan]Node:[-] %s\n\n", podObj.Spec.NodeName)
 
-	details += "[yellow::b]Containers[-::-]\n"
-	for _, container := range podObj.Spec.Containers {
-		details += fmt.Sprintf("  [green]Container:[-] %s\n", container.Name)
-
-		details += "    [lightgreen::b]Requests[-::-]\n"
-		if req, ok := container.Resources.Requests[v1.ResourceCPU]; ok {
-			details += fmt.Sprintf("      [lightcyan]CPU:[-] %s\n", req.String())
-		} else {
-			details += "      [lightcyan]CPU:[-] Not Set\n"
-		}
-
-		if req, ok := container.Resources.Requests[v1.ResourceMemory]; ok {
-			details += fmt.Sprintf("      [lightcyan]Memory:[-] %s\n", req.String())
-		} else {
-			details += "      [lightcyan]Memory:[-] Not Set\n"
-		}
-
-		details += "    [lightgreen::b]Limits[-::-]\n"
-		if limit, ok := container.Resources.Limits[v1.ResourceCPU]; ok {
-			details += fmt.Sprintf("      [lightcyan]CPU:[-] %s\n", limit.String())
-		} else {
-			details += "      [lightcyan]CPU:[-] Not Set\n"
-		}
-
-		if limit, ok := container.Resources.Limits[v1.ResourceMemory]; ok {
-			details += fmt.Sprintf("      [lightcyan]Memory:[-] %s\n", limit.String())
-		} else {
-			details += "      [lightcyan]Memory:[-] Not Set\n"
-		}
-
-		details += "\n"
-	}
-
+	details += c.describeContainers(podObj)
+	details += c.describePodEvents(podObj)
 	return details, nil
 }
 
@@ -0,0 +1,923 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/audit"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+var (
+	nodeMetricsResource = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}
+	podMetricsResource  = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+)
+
+// newTestClient returns a client in namespace "default" backed by fake
+// clientsets holding objects.
+func newTestClient(t *testing.T, objects ...runtime.Object) (*Client, *fake.Clientset, *metricsfake.Clientset) {
+	t.Helper()
+	clientset := fake.NewSimpleClientset(objects...)
+	metricsClient := metricsfake.NewSimpleClientset()
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	return NewClientFromInterfaces(clientset, metricsClient, dynamicClient, "default"), clientset, metricsClient
+}
+
+func testPod(namespace string, name string, nodeName string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       v1.PodSpec{NodeName: nodeName},
+	}
+}
+
+func usage(cpu string, memory string) v1.ResourceList {
+	return v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse(cpu),
+		v1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+func forbidden(resource string) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: resource}, "", errors.New("denied"))
+	}
+}
+
+func TestGetNodes(t *testing.T) {
+	client, _, _ := newTestClient(t,
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}},
+	)
+
+	nodes, err := client.GetNodes()
+	if err != nil {
+		t.Fatalf("GetNodes: %v", err)
+	}
+	if want := []string{"node-a", "node-b"}; !reflect.DeepEqual(nodes, want) {
+		t.Errorf("GetNodes = %v, want %v", nodes, want)
+	}
+}
+
+func TestListNodes(t *testing.T) {
+	created := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	client, _, _ := newTestClient(t,
+		&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "node-a",
+				CreationTimestamp: metav1.NewTime(created),
+				Labels:            map[string]string{"node-role.kubernetes.io/control-plane": "", "node-role.kubernetes.io/etcd": ""},
+			},
+			Status: v1.NodeStatus{
+				Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+				Addresses:  []v1.NodeAddress{{Type: v1.NodeHostName, Address: "node-a"}, {Type: v1.NodeInternalIP, Address: "10.0.0.1"}},
+				NodeInfo:   v1.NodeSystemInfo{KubeletVersion: "v1.31.1"},
+			},
+		},
+		&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-b"},
+			Spec:       v1.NodeSpec{Unschedulable: true},
+		},
+	)
+
+	nodes, err := client.ListNodes(Filter{})
+	if err != nil {
+		t.Fatalf("ListNodes: %v", err)
+	}
+	want := []Node{
+		{Name: "node-a", Status: "Ready", Roles: "control-plane,etcd", Version: "v1.31.1", InternalIP: "10.0.0.1", Created: created},
+		{Name: "node-b", Status: "NotReady,SchedulingDisabled", Roles: "<none>"},
+	}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Errorf("ListNodes = %+v, want %+v", nodes, want)
+	}
+}
+
+func TestPodStatus(t *testing.T) {
+	waiting := func(reason string) v1.ContainerState {
+		return v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: reason}}
+	}
+	now := metav1.Now()
+	for _, test := range []struct {
+		name string
+		pod  v1.Pod
+		want string
+	}{
+		{"running", v1.Pod{Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{{State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}}}}}, "Running"},
+		{"crash loop", v1.Pod{Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{{State: waiting("CrashLoopBackOff")}}}}, "CrashLoopBackOff"},
+		{"evicted", v1.Pod{Status: v1.PodStatus{Phase: v1.PodFailed, Reason: "Evicted"}}, "Evicted"},
+		{"terminating", v1.Pod{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}, Status: v1.PodStatus{Phase: v1.PodRunning}}, "Terminating"},
+		{"init", v1.Pod{
+			Spec:   v1.PodSpec{InitContainers: []v1.Container{{Name: "migrate"}, {Name: "seed"}}},
+			Status: v1.PodStatus{Phase: v1.PodPending, InitContainerStatuses: []v1.ContainerStatus{{State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}}}},
+		}, "Init:0/2"},
+		{"init image", v1.Pod{Status: v1.PodStatus{Phase: v1.PodPending, InitContainerStatuses: []v1.ContainerStatus{{State: waiting("ImagePullBackOff")}}}}, "Init:ImagePullBackOff"},
+	} {
+		if got := podStatus(&test.pod); got != test.want {
+			t.Errorf("%s: podStatus = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestGetPodsUsesNamespace(t *testing.T) {
+	client, _, _ := newTestClient(t,
+		testPod("default", "web", "node-a"),
+		testPod("kube-system", "dns", "node-b"),
+	)
+
+	pods, err := client.GetPods()
+	if err != nil {
+		t.Fatalf("GetPods: %v", err)
+	}
+	if want := []Pod{{Name: "web", Namespace: "default", NodeName: "node-a", Status: "Unknown"}}; !reflect.DeepEqual(pods, want) {
+		t.Errorf("GetPods = %v, want %v", pods, want)
+	}
+
+	client.SetNamespace("")
+	pods, err = client.GetPods()
+	if err != nil {
+		t.Fatalf("GetPods in all namespaces: %v", err)
+	}
+	if len(pods) != 2 {
+		t.Errorf("GetPods in all namespaces returned %d pods, want 2", len(pods))
+	}
+}
+
+func TestGetPodsByNodeFiltersOnNodeName(t *testing.T) {
+	client, clientset, _ := newTestClient(t)
+
+	var selector string
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		list := action.(k8stesting.ListAction)
+		selector = list.GetListRestrictions().Fields.String()
+		if list.GetNamespace() != "" {
+			t.Errorf("GetPodsByNode listed namespace %q, want all namespaces", list.GetNamespace())
+		}
+		return true, &v1.PodList{Items: []v1.Pod{*testPod("kube-system", "proxy", "node-a")}}, nil
+	})
+
+	pods, err := client.GetPodsByNode("node-a")
+	if err != nil {
+		t.Fatalf("GetPodsByNode: %v", err)
+	}
+	if selector != "spec.nodeName=node-a" {
+		t.Errorf("field selector = %q, want spec.nodeName=node-a", selector)
+	}
+	if want := []Pod{{Name: "proxy", Namespace: "kube-system", NodeName: "node-a", Status: "Unknown"}}; !reflect.DeepEqual(pods, want) {
+		t.Errorf("GetPodsByNode = %v, want %v", pods, want)
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	for _, test := range []struct {
+		text     string
+		resource APIResource
+		want     Filter
+	}{
+		{"", PodResource, Filter{}},
+		{"web !canary", PodResource, Filter{Names: []string{"web"}, ExcludedNames: []string{"canary"}}},
+		{"app=web !tier=db app.kubernetes.io/part-of!=shop", PodResource, Filter{Labels: "app=web,tier!=db,app.kubernetes.io/part-of!=shop"}},
+		{"status.phase!=Running !spec.nodeName==node-a", PodResource, Filter{Fields: "status.phase!=Running,spec.nodeName!=node-a"}},
+		// Labels may look like fields, only the fields supported for the
+		// resource are sent as field selectors.
+		{"status.example.com/tier=web spec.unschedulable=true", NodeResource, Filter{Labels: "status.example.com/tier=web", Fields: "spec.unschedulable=true"}},
+		{"status.phase!=Running spec.nodeName=node-a", NodeResource, Filter{Labels: "status.phase!=Running,spec.nodeName=node-a"}},
+		{"spec.unschedulable=true", PodResource, Filter{Labels: "spec.unschedulable=true"}},
+	} {
+		got, err := ParseFilter(test.text, test.resource)
+		if err != nil {
+			t.Errorf("ParseFilter(%q, %s): %v", test.text, test.resource.Name, err)
+			continue
+		}
+		// The parsed selectors are checked by TestFilterMatches.
+		got.labelSelector, got.fieldSelector = nil, nil
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseFilter(%q, %s) = %+v, want %+v", test.text, test.resource.Name, got, test.want)
+		}
+	}
+
+	for _, text := range []string{"!", "=web", "app=-web", "app=(web)"} {
+		if _, err := ParseFilter(text, PodResource); err == nil {
+			t.Errorf("ParseFilter(%q) succeeded, want an error", text)
+		}
+	}
+	if _, err := ParseFilter("web", ReplicaSetResource); err == nil {
+		t.Errorf("ParseFilter for replicasets succeeded, want an error")
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	filter, err := ParseFilter("app=web status.phase!=Pending !canary", PodResource)
+	if err != nil {
+		t.Fatalf("ParseFilter: %v", err)
+	}
+	onNode := filter.OnNode("node-a")
+	built := Filter{Labels: "app=web", Fields: "status.phase!=Pending,spec.nodeName=node-a", ExcludedNames: []string{"canary"}}
+	for _, test := range []struct {
+		name   string
+		labels map[string]string
+		fields map[string]string
+		want   bool // by filter
+		onNode bool // by onNode and built
+	}{
+		{"web-7d4b9", map[string]string{"app": "web"}, map[string]string{"status.phase": "Running", "spec.nodeName": "node-a"}, true, true},
+		{"web-7d4b9", map[string]string{"app": "web"}, map[string]string{"status.phase": "Running", "spec.nodeName": "node-b"}, true, false},
+		{"web-canary-x2", map[string]string{"app": "web"}, map[string]string{"status.phase": "Running", "spec.nodeName": "node-a"}, false, false},
+		{"web-9f8e7", map[string]string{"app": "web"}, map[string]string{"status.phase": "Pending", "spec.nodeName": "node-a"}, false, false},
+		{"api-5c8f2", map[string]string{"app": "api"}, map[string]string{"status.phase": "Running", "spec.nodeName": "node-a"}, false, false},
+	} {
+		if got := filter.Matches(test.name, test.labels, test.fields); got != test.want {
+			t.Errorf("Matches(%s, %v, %v) = %v, want %v", test.name, test.labels, test.fields, got, test.want)
+		}
+		if got := onNode.Matches(test.name, test.labels, test.fields); got != test.onNode {
+			t.Errorf("Matches on node-a (%s, %v, %v) = %v, want %v", test.name, test.labels, test.fields, got, test.onNode)
+		}
+		// A filter that was not parsed matches the same.
+		if got := built.Matches(test.name, test.labels, test.fields); got != test.onNode {
+			t.Errorf("Matches of a built filter (%s, %v, %v) = %v, want %v", test.name, test.labels, test.fields, got, test.onNode)
+		}
+	}
+}
+
+func TestListPodsAppliesFilter(t *testing.T) {
+	labeled := func(name string, app string, phase v1.PodPhase) *v1.Pod {
+		pod := testPod("default", name, "node-a")
+		pod.Labels = map[string]string{"app": app}
+		pod.Status.Phase = phase
+		return pod
+	}
+	client, clientset, _ := newTestClient(t,
+		labeled("web-7d4b9", "web", v1.PodRunning),
+		labeled("web-canary-x2", "web", v1.PodRunning),
+		labeled("web-9f8e7", "web", v1.PodPending),
+		labeled("api-5c8f2", "api", v1.PodPending),
+	)
+	var options metav1.ListOptions
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		restrictions := action.(k8stesting.ListAction).GetListRestrictions()
+		options = metav1.ListOptions{LabelSelector: restrictions.Labels.String(), FieldSelector: restrictions.Fields.String()}
+		return false, nil, nil
+	})
+
+	filter, err := ParseFilter("app=web status.phase!=Pending !canary", PodResource)
+	if err != nil {
+		t.Fatalf("ParseFilter: %v", err)
+	}
+	pods, err := client.ListPods("default", filter)
+	if err != nil {
+		t.Fatalf("ListPods: %v", err)
+	}
+	if options.LabelSelector != "app=web" || options.FieldSelector != "status.phase!=Pending" {
+		t.Errorf("ListPods sent label selector %q and field selector %q", options.LabelSelector, options.FieldSelector)
+	}
+	if len(pods) != 1 || pods[0].Name != "web-7d4b9" {
+		t.Errorf("ListPods = %v, want only web-7d4b9", pods)
+	}
+}
+
+func TestListNamespaces(t *testing.T) {
+	client, _, _ := newTestClient(t,
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+	)
+
+	namespaces, err := client.ListNamespaces()
+	if err != nil {
+		t.Fatalf("ListNamespaces: %v", err)
+	}
+	if want := []string{"default", "kube-system"}; !reflect.DeepEqual(namespaces, want) {
+		t.Errorf("ListNamespaces = %v, want %v", namespaces, want)
+	}
+}
+
+func TestListNamespaceSummaries(t *testing.T) {
+	pod := func(namespace string, name string, phase v1.PodPhase, restarts int32, requests v1.ResourceList) *v1.Pod {
+		p := testPod(namespace, name, "node-a")
+		p.Spec.Containers = []v1.Container{{Name: "app", Resources: v1.ResourceRequirements{Requests: requests}}}
+		p.Status = v1.PodStatus{Phase: phase, ContainerStatuses: []v1.ContainerStatus{{Name: "app", RestartCount: restarts}}}
+		return p
+	}
+	warning := func(name string, age time.Duration) *v1.Event {
+		return &v1.Event{
+			ObjectMeta:    metav1.ObjectMeta{Namespace: "shop", Name: name},
+			Type:          v1.EventTypeWarning,
+			LastTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		}
+	}
+	client, _, metricsClient := newTestClient(t,
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "empty"}},
+		pod("shop", "web", v1.PodRunning, 2, usage("200m", "256Mi")),
+		pod("shop", "api", v1.PodPending, 0, usage("300m", "256Mi")),
+		pod("shop", "job", v1.PodSucceeded, 1, usage("1", "1Gi")),
+		&v1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "compute"},
+			Spec:       v1.ResourceQuotaSpec{Hard: v1.ResourceList{v1.ResourceRequestsCPU: resource.MustParse("2"), v1.ResourceRequestsMemory: resource.MustParse("1Gi")}},
+		},
+		&v1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "tight"},
+			Spec:       v1.ResourceQuotaSpec{Hard: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}},
+		},
+		warning("web.1", 10*time.Minute),
+		warning("web.2", 2*time.Hour),
+	)
+	podMetrics := &metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web"},
+		Containers: []metricsv1beta1.ContainerMetrics{{Name: "app", Usage: usage("150m", "200Mi")}},
+	}
+	if err := metricsClient.Tracker().Create(podMetricsResource, podMetrics, "shop"); err != nil {
+		t.Fatalf("creating metrics: %v", err)
+	}
+
+	summaries, err := client.ListNamespaceSummaries()
+	if err != nil {
+		t.Fatalf("ListNamespaceSummaries: %v", err)
+	}
+	// The completed job neither counts towards requests nor the quota, and
+	// the older warning is left out.
+	want := []NamespaceSummary{
+		{Name: "empty", UsageAvailable: true},
+		{
+			Name: "shop", PodCounts: PodCounts{Running: 1, Pending: 1, Succeeded: 1}, Restarts: 3,
+			Usage:          Resources{CPU: 150, Memory: 200 << 20},
+			UsageAvailable: true,
+			Requests:       Resources{CPU: 500, Memory: 512 << 20},
+			Quota:          Resources{CPU: 1000, Memory: 1 << 30},
+			Warnings:       1,
+		},
+	}
+	if !reflect.DeepEqual(summaries, want) {
+		t.Errorf("ListNamespaceSummaries = %+v, want %+v", summaries, want)
+	}
+
+	// Without metrics the usage is unknown, not zero.
+	metricsClient.PrependReactor("list", "pods", forbidden("pods.metrics.k8s.io"))
+	summaries, err = client.ListNamespaceSummaries()
+	if err != nil {
+		t.Fatalf("ListNamespaceSummaries without metrics: %v", err)
+	}
+	for _, summary := range summaries {
+		if summary.UsageAvailable {
+			t.Errorf("usage of %s is available without metrics", summary.Name)
+		}
+	}
+}
+
+func TestListNamespaceSummariesInOneNamespace(t *testing.T) {
+	client, clientset, _ := newTestClient(t,
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "empty"}},
+		testPod("shop", "web", "node-a"),
+		testPod("other", "db", "node-a"),
+	)
+	clientset.PrependReactor("list", "namespaces", forbidden("namespaces"))
+
+	// The namespaces are found from the pods.
+	summaries, err := client.ListNamespaceSummaries()
+	if err != nil {
+		t.Fatalf("ListNamespaceSummaries: %v", err)
+	}
+	if len(summaries) != 2 || summaries[0].Name != "other" || summaries[1].Name != "shop" {
+		t.Errorf("ListNamespaceSummaries = %+v, want other and shop", summaries)
+	}
+
+	// The user may only list pods in their namespace.
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "" {
+			return forbidden("pods")(action)
+		}
+		return false, nil, nil
+	})
+	client.SetNamespace("shop")
+	summaries, err = client.ListNamespaceSummaries()
+	if err != nil {
+		t.Fatalf("ListNamespaceSummaries in one namespace: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].Name != "shop" || summaries[0].Pods() != 1 {
+		t.Errorf("ListNamespaceSummaries in one namespace = %+v, want shop with one pod", summaries)
+	}
+
+	client.SetNamespace("")
+	if _, err := client.ListNamespaceSummaries(); !apierrors.IsForbidden(err) {
+		t.Errorf("ListNamespaceSummaries in all namespaces error = %v, want forbidden", err)
+	}
+}
+
+func TestFindAPIResource(t *testing.T) {
+	client, clientset, _ := newTestClient(t)
+	verbs := metav1.Verbs{"get", "list"}
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "endpoints", SingularName: "endpoints", Kind: "Endpoints", Namespaced: true, Verbs: verbs},
+		}},
+		{GroupVersion: "networking.k8s.io/v1", APIResources: []metav1.APIResource{
+			{Name: "ingresses", SingularName: "ingress", Kind: "Ingress", ShortNames: []string{"ing"}, Namespaced: true, Verbs: verbs},
+			{Name: "networkpolicies", SingularName: "networkpolicy", Kind: "NetworkPolicy", ShortNames: []string{"netpol"}, Namespaced: true, Verbs: verbs},
+		}},
+		// Without a singular name, the RESTMapper resolves it from the kind.
+		{GroupVersion: "cert-manager.io/v1", APIResources: []metav1.APIResource{
+			{Name: "clusterissuers", Kind: "ClusterIssuer", Verbs: verbs},
+		}},
+	}
+
+	tests := map[string]string{
+		"endpoints":                        "endpoints",
+		"ingress":                          "ingresses.networking.k8s.io",
+		"ing":                              "ingresses.networking.k8s.io",
+		"networkpolicy":                    "networkpolicies.networking.k8s.io",
+		"networkpolicy.networking.k8s.io":  "networkpolicies.networking.k8s.io",
+		"NetworkPolicies":                  "networkpolicies.networking.k8s.io",
+		"clusterissuer":                    "clusterissuers.cert-manager.io",
+		"clusterissuer.v1.cert-manager.io": "clusterissuers.cert-manager.io",
+		"clusterissuers.cert-manager.io":   "clusterissuers.cert-manager.io",
+	}
+	for name, want := range tests {
+		resource, err := client.FindAPIResource(name)
+		if err != nil {
+			t.Errorf("FindAPIResource(%q): %v", name, err)
+			continue
+		}
+		if resource.String() != want {
+			t.Errorf("FindAPIResource(%q) = %s, want %s", name, resource, want)
+		}
+	}
+	for _, name := range []string{"ingresse", "endpoint", "networkpolicie"} {
+		if resource, err := client.FindAPIResource(name); err == nil {
+			t.Errorf("FindAPIResource(%q) = %s, want an error", name, resource)
+		}
+	}
+}
+
+func TestGetClusterSummary(t *testing.T) {
+	node := func(name string, ready v1.ConditionStatus) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1.NodeStatus{
+				Allocatable: usage("4", "16Gi"),
+				Conditions:  []v1.NodeCondition{{Type: v1.NodeReady, Status: ready}},
+			},
+		}
+	}
+	running := testPod("default", "web", "node-a")
+	running.Status.Phase = v1.PodRunning
+	failed := testPod("default", "job", "node-b")
+	failed.Status.Phase = v1.PodFailed
+	client, clientset, metricsClient := newTestClient(t,
+		node("node-a", v1.ConditionTrue), node("node-b", v1.ConditionFalse), running, failed,
+		&v1.Event{
+			ObjectMeta:    metav1.ObjectMeta{Namespace: "default", Name: "job.1"},
+			Type:          v1.EventTypeWarning,
+			LastTimestamp: metav1.NewTime(time.Now().Add(-time.Minute)),
+		},
+	)
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.31.1"}
+	nodeMetrics := &metricsv1beta1.NodeMetrics{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}, Usage: usage("1500m", "2Gi")}
+	if err := metricsClient.Tracker().Create(nodeMetricsResource, nodeMetrics, ""); err != nil {
+		t.Fatalf("creating metrics: %v", err)
+	}
+
+	summary, err := client.GetClusterSummary()
+	if err != nil {
+		t.Fatalf("GetClusterSummary: %v", err)
+	}
+	want := ClusterSummary{
+		ServerVersion:  "v1.31.1",
+		Nodes:          2,
+		ReadyNodes:     1,
+		PodCounts:      PodCounts{Running: 1, Failed: 1},
+		Usage:          Resources{CPU: 1500, Memory: 2 << 30},
+		UsageAvailable: true,
+		Allocatable:    Resources{CPU: 8000, Memory: 32 << 30},
+		Warnings:       1,
+	}
+	if summary != want {
+		t.Errorf("GetClusterSummary = %+v, want %+v", summary, want)
+	}
+
+	metricsClient.PrependReactor("list", "nodes", forbidden("nodes.metrics.k8s.io"))
+	if summary, err := client.GetClusterSummary(); err != nil || summary.UsageAvailable || summary.Usage != (Resources{}) {
+		t.Errorf("GetClusterSummary without metrics = %+v, %v, want the usage unavailable", summary, err)
+	}
+}
+
+func TestGetNodeMetrics(t *testing.T) {
+	client, _, metricsClient := newTestClient(t)
+	err := metricsClient.Tracker().Create(nodeMetricsResource, &metricsv1beta1.NodeMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Usage:      usage("1500m", "2Gi"),
+	}, "")
+	if err != nil {
+		t.Fatalf("creating node metrics: %v", err)
+	}
+
+	cpu, memory, err := client.GetNodeMetrics("node-a")
+	if err != nil {
+		t.Fatalf("GetNodeMetrics: %v", err)
+	}
+	if cpu != "1500m" || memory != "2048Mi" {
+		t.Errorf("GetNodeMetrics = %s, %s, want 1500m, 2048Mi", cpu, memory)
+	}
+}
+
+func TestGetPodMetricsSumsContainers(t *testing.T) {
+	client, _, metricsClient := newTestClient(t)
+	err := metricsClient.Tracker().Create(podMetricsResource, &metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Containers: []metricsv1beta1.ContainerMetrics{
+			{Name: "app", Usage: usage("250m", "100Mi")},
+			{Name: "sidecar", Usage: usage("5m", "28Mi")},
+		},
+	}, "default")
+	if err != nil {
+		t.Fatalf("creating pod metrics: %v", err)
+	}
+
+	cpu, memory, err := client.GetPodMetrics(Pod{Name: "web", Namespace: "default"})
+	if err != nil {
+		t.Fatalf("GetPodMetrics: %v", err)
+	}
+	if cpu != "255m" || memory != "128Mi" {
+		t.Errorf("GetPodMetrics = %s, %s, want 255m, 128Mi", cpu, memory)
+	}
+}
+
+func TestListUsage(t *testing.T) {
+	always := v1.ContainerRestartPolicyAlways
+	web := testPod("default", "web", "node-a")
+	web.Spec.Containers = []v1.Container{{Name: "app", Resources: v1.ResourceRequirements{Requests: usage("200m", "64Mi"), Limits: usage("500m", "128Mi")}}}
+	web.Spec.InitContainers = []v1.Container{
+		{Name: "migrate", Resources: v1.ResourceRequirements{Requests: usage("1", "1Gi")}},
+		{Name: "sidecar", RestartPolicy: &always, Resources: v1.ResourceRequirements{Requests: usage("10m", "16Mi")}},
+	}
+	client, _, metricsClient := newTestClient(t, web)
+	scraped := metav1.NewTime(time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC))
+	for _, obj := range []struct {
+		resource  schema.GroupVersionResource
+		object    runtime.Object
+		namespace string
+	}{
+		{nodeMetricsResource, &metricsv1beta1.NodeMetrics{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}, Timestamp: scraped, Usage: usage("1500m", "2Gi")}, ""},
+		{podMetricsResource, &metricsv1beta1.PodMetrics{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+			Timestamp:  scraped,
+			Containers: []metricsv1beta1.ContainerMetrics{
+				{Name: "app", Usage: usage("250m", "100Mi")},
+				{Name: "sidecar", Usage: usage("5m", "28Mi")},
+			},
+		}, "default"},
+	} {
+		if err := metricsClient.Tracker().Create(obj.resource, obj.object, obj.namespace); err != nil {
+			t.Fatalf("creating metrics: %v", err)
+		}
+	}
+
+	nodes, err := client.ListNodeUsage()
+	if err != nil {
+		t.Fatalf("ListNodeUsage: %v", err)
+	}
+	want := []NodeUsage{{Name: "node-a", Usage: Usage{Time: scraped.Time, CPU: 1500, Memory: 2 << 30}}}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Errorf("ListNodeUsage = %+v, want %+v", nodes, want)
+	}
+
+	pods, err := client.ListPodUsage()
+	if err != nil {
+		t.Fatalf("ListPodUsage: %v", err)
+	}
+	if len(pods) != 1 || pods[0].CPU != 255 || pods[0].Memory != 128<<20 || len(pods[0].Containers) != 2 {
+		t.Fatalf("ListPodUsage = %+v, want web using 255m and 128Mi in two containers", pods)
+	}
+	if sidecar := pods[0].Containers[1]; sidecar.Name != "sidecar" || sidecar.CPU != 5 || !sidecar.Time.Equal(scraped.Time) {
+		t.Errorf("sidecar usage = %+v", sidecar)
+	}
+	// The sidecar has no limits, so neither has the pod. The init container
+	// that ran before does not count.
+	if got, want := pods[0].Requests, (Resources{CPU: 210, Memory: 80 << 20}); got != want {
+		t.Errorf("pod requests = %+v, want %+v", got, want)
+	}
+	if got := pods[0].Limits; got != (Resources{}) {
+		t.Errorf("pod limits = %+v, want none", got)
+	}
+	if app := pods[0].Containers[0]; app.Limits != (Resources{CPU: 500, Memory: 128 << 20}) {
+		t.Errorf("app limits = %+v", app.Limits)
+	}
+	if pods[0].Workload != "Pod/web" {
+		t.Errorf("workload = %q, want Pod/web", pods[0].Workload)
+	}
+}
+
+func TestListPodUsageInOneNamespace(t *testing.T) {
+	web := testPod("default", "web", "node-a")
+	web.Spec.Containers = []v1.Container{{Name: "app", Resources: v1.ResourceRequirements{Requests: usage("200m", "64Mi")}}}
+	client, clientset, metricsClient := newTestClient(t, web, testPod("other", "db", "node-a"))
+	for _, obj := range []*metricsv1beta1.PodMetrics{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"}, Containers: []metricsv1beta1.ContainerMetrics{{Name: "app", Usage: usage("250m", "100Mi")}}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "db"}, Containers: []metricsv1beta1.ContainerMetrics{{Name: "db", Usage: usage("10m", "10Mi")}}},
+	} {
+		if err := metricsClient.Tracker().Create(podMetricsResource, obj, obj.Namespace); err != nil {
+			t.Fatalf("creating metrics: %v", err)
+		}
+	}
+	// The user may only list pods and their metrics in their namespace.
+	onlyInNamespace := func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "" {
+			return forbidden(action.GetResource().Resource)(action)
+		}
+		return false, nil, nil
+	}
+	clientset.PrependReactor("list", "pods", onlyInNamespace)
+	metricsClient.PrependReactor("list", "pods", onlyInNamespace)
+
+	pods, err := client.ListPodUsage()
+	if err != nil {
+		t.Fatalf("ListPodUsage: %v", err)
+	}
+	if len(pods) != 1 || pods[0].Pod.Name != "web" || pods[0].CPU != 250 {
+		t.Fatalf("ListPodUsage = %+v, want web using 250m", pods)
+	}
+	if got, want := pods[0].Requests, (Resources{CPU: 200, Memory: 64 << 20}); got != want {
+		t.Errorf("pod requests = %+v, want %+v", got, want)
+	}
+
+	client.SetNamespace("")
+	if _, err := client.ListPodUsage(); !apierrors.IsForbidden(err) {
+		t.Errorf("ListPodUsage in all namespaces error = %v, want forbidden", err)
+	}
+}
+
+func TestWorkloadOf(t *testing.T) {
+	controller := true
+	owned := func(kind, name string, labels map[string]string) *v1.Pod {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-5f6c8d-x2k4q", Labels: labels}}
+		if kind != "" {
+			pod.OwnerReferences = []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+		}
+		return pod
+	}
+	for _, tc := range []struct {
+		pod  *v1.Pod
+		want string
+	}{
+		{owned("ReplicaSet", "web-5f6c8d", map[string]string{"pod-template-hash": "5f6c8d"}), "Deployment/web"},
+		{owned("ReplicaSet", "web-5f6c8d", nil), "ReplicaSet/web-5f6c8d"},
+		{owned("StatefulSet", "db", nil), "StatefulSet/db"},
+		{owned("Node", "node-a", nil), "Pod/web-5f6c8d-x2k4q"},
+		{owned("", "", nil), "Pod/web-5f6c8d-x2k4q"},
+	} {
+		if got := WorkloadOf(tc.pod); got != tc.want {
+			t.Errorf("WorkloadOf(%v) = %q, want %q", tc.pod.OwnerReferences, got, tc.want)
+		}
+	}
+}
+
+func TestGetMetricsNotFound(t *testing.T) {
+	client, _, _ := newTestClient(t)
+
+	if _, _, err := client.GetNodeMetrics("missing"); !apierrors.IsNotFound(err) {
+		t.Errorf("GetNodeMetrics error = %v, want not found", err)
+	}
+	if _, _, err := client.GetPodMetrics(Pod{Name: "missing", Namespace: "default"}); !apierrors.IsNotFound(err) {
+		t.Errorf("GetPodMetrics error = %v, want not found", err)
+	}
+}
+
+func TestGetPodDetails(t *testing.T) {
+	pod := testPod("default", "web", "node-a")
+	pod.Status.Phase = v1.PodRunning
+	pod.Spec.Containers = []v1.Container{{
+		Name: "app",
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+		},
+	}}
+	client, _, _ := newTestClient(t, pod)
+
+	details, err := client.GetPodDetails(Pod{Name: "web", Namespace: "default"})
+	if err != nil {
+		t.Fatalf("GetPodDetails: %v", err)
+	}
+	for _, want := range []string{
+		"[lightcyan]Pod Name:[-] web",
+		"[lightcyan]Status:[-] Running",
+		"[lightcyan]Node:[-] node-a",
+		"[green]Container:[-] app",
+		"[lightcyan]CPU:[-] 100m",
+		"[lightcyan]Memory:[-] Not Set",
+	} {
+		if !strings.Contains(details, want) {
+			t.Errorf("GetPodDetails is missing %q:\n%s", want, details)
+		}
+	}
+}
+
+func TestGetPodDetailsShowsContainers(t *testing.T) {
+	always := v1.ContainerRestartPolicyAlways
+	pod := testPod("default", "web", "node-a")
+	pod.Spec.InitContainers = []v1.Container{
+		{Name: "migrate", Image: "migrate:1"},
+		{Name: "proxy", Image: "envoy:1", RestartPolicy: &always, Resources: v1.ResourceRequirements{Limits: usage("100m", "64Mi")}},
+	}
+	pod.Spec.Containers = []v1.Container{{Name: "app", Image: "web:2", Resources: v1.ResourceRequirements{Requests: usage("200m", "256Mi")}}}
+	pod.Status.InitContainerStatuses = []v1.ContainerStatus{
+		{Name: "migrate", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}}},
+		{Name: "proxy", Ready: true, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+	}
+	pod.Status.ContainerStatuses = []v1.ContainerStatus{{
+		Name:                 "app",
+		RestartCount:         4,
+		State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+	}}
+	client, _, metricsClient := newTestClient(t, pod)
+	err := metricsClient.Tracker().Create(podMetricsResource, &metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Containers: []metricsv1beta1.ContainerMetrics{
+			{Name: "app", Usage: usage("300m", "128Mi")},
+			{Name: "proxy", Usage: usage("5m", "60Mi")},
+		},
+	}, "default")
+	if err != nil {
+		t.Fatalf("creating pod metrics: %v", err)
+	}
+
+	details, err := client.GetPodDetails(Pod{Name: "web", Namespace: "default"})
+	if err != nil {
+		t.Fatalf("GetPodDetails: %v", err)
+	}
+	for _, want := range []string{
+		"[yellow::b]Init Containers[-::-]\n  [green]Container:[-] migrate\n",
+		"[lightcyan]State:[-] Terminated, [green]Completed[-], exit code 0\n",
+		"[green]Container:[-] proxy [gray](sidecar)[-]",
+		"[lightcyan]Memory:[-] 60Mi [gray]([red]93% of limit[gray])[-]",
+		"[yellow::b]Containers[-::-]\n  [green]Container:[-] app\n",
+		"[lightcyan]State:[-] [orange]Waiting[-] (CrashLoopBackOff)",
+		"[lightcyan]Restarts:[-] [orange]4[-]",
+		"[lightcyan]Last Termination:[-] [red]OOMKilled[-], exit code 137",
+		"[lightcyan]CPU:[-] 300m [gray](150% of request)[-]",
+	} {
+		if !strings.Contains(details, want) {
+			t.Errorf("GetPodDetails is missing %q:\n%s", want, details)
+		}
+	}
+	if strings.Contains(details, "Usage[-::-]\n      [lightcyan]CPU:[-] 0m") {
+		t.Errorf("GetPodDetails shows usage for the completed init container:\n%s", details)
+	}
+}
+
+func TestGetPodDetailsShowsEvents(t *testing.T) {
+	event := func(name string, pod string, reason string, minute int) *v1.Event {
+		return &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: name},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "default", Name: pod},
+			Reason:         reason,
+			Type:           v1.EventTypeNormal,
+			LastTimestamp:  metav1.NewTime(time.Date(2024, 10, 18, 12, minute, 0, 0, time.UTC)),
+		}
+	}
+	client, _, _ := newTestClient(t,
+		testPod("default", "web", "node-a"),
+		event("web.2", "web", "Started", 2),
+		event("web.1", "web", "Pulled", 1),
+		event("api.1", "api", "BackOff", 3),
+	)
+
+	details, err := client.GetPodDetails(Pod{Name: "web", Namespace: "default"})
+	if err != nil {
+		t.Fatalf("GetPodDetails: %v", err)
+	}
+	pulled, started := strings.Index(details, "Pulled"), strings.Index(details, "Started")
+	if pulled < 0 || started < pulled {
+		t.Errorf("GetPodDetails does not list the events oldest first:\n%s", details)
+	}
+	if strings.Contains(details, "BackOff") {
+		t.Errorf("GetPodDetails lists events of another pod:\n%s", details)
+	}
+}
+
+func TestGetPodLogs(t *testing.T) {
+	client, _, _ := newTestClient(t, testPod("default", "web", "node-a"))
+
+	// The fake clientset answers every log request with "fake logs".
+	logs, err := client.GetPodLogs(Pod{Name: "web", Namespace: "default"})
+	if err != nil {
+		t.Fatalf("GetPodLogs: %v", err)
+	}
+	if logs != "fake logs" {
+		t.Errorf("GetPodLogs = %q, want %q", logs, "fake logs")
+	}
+}
+
+func TestListErrors(t *testing.T) {
+	client, clientset, _ := newTestClient(t)
+	clientset.PrependReactor("list", "nodes", forbidden("nodes"))
+	clientset.PrependReactor("list", "pods", forbidden("pods"))
+	clientset.PrependReactor("list", "namespaces", forbidden("namespaces"))
+	clientset.PrependReactor("get", "pods", forbidden("pods"))
+
+	if nodes, err := client.GetNodes(); !apierrors.IsForbidden(err) || nodes != nil {
+		t.Errorf("GetNodes = %v, %v, want forbidden", nodes, err)
+	}
+	if pods, err := client.GetPods(); !apierrors.IsForbidden(err) || pods != nil {
+		t.Errorf("GetPods = %v, %v, want forbidden", pods, err)
+	}
+	if pods, err := client.GetPodsByNode("node-a"); !apierrors.IsForbidden(err) || pods != nil {
+		t.Errorf("GetPodsByNode = %v, %v, want forbidden", pods, err)
+	}
+	if namespaces, err := client.ListNamespaces(); !apierrors.IsForbidden(err) || namespaces != nil {
+		t.Errorf("ListNamespaces = %v, %v, want forbidden", namespaces, err)
+	}
+	if _, err := client.GetPodDetails(Pod{Name: "web", Namespace: "default"}); !apierrors.IsForbidden(err) {
+		t.Errorf("GetPodDetails error = %v, want forbidden", err)
+	}
+}
+
+func TestDeletePod(t *testing.T) {
+	client, clientset, _ := newTestClient(t, testPod("default", "web", "node-a"))
+
+	if err := client.DeletePod(Pod{Name: "web", Namespace: "default"}, false); err != nil {
+		t.Fatalf("DeletePod: %v", err)
+	}
+	if pods, _ := client.GetPods(); len(pods) != 0 {
+		t.Errorf("pods after DeletePod = %v, want none", pods)
+	}
+
+	if err := client.DeletePod(Pod{Name: "web", Namespace: "default"}, true); !apierrors.IsNotFound(err) {
+		t.Errorf("DeletePod of a missing pod = %v, want not found", err)
+	}
+
+	var gracePeriod *int64
+	clientset.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gracePeriod = action.(k8stesting.DeleteAction).GetDeleteOptions().GracePeriodSeconds
+		return true, nil, nil
+	})
+	if err := client.DeletePod(Pod{Name: "db", Namespace: "default"}, true); err != nil {
+		t.Fatalf("force DeletePod: %v", err)
+	}
+	if gracePeriod == nil || *gracePeriod != 0 {
+		t.Errorf("force DeletePod grace period = %v, want 0", gracePeriod)
+	}
+}
+
+func TestReadOnlyRefusesWrites(t *testing.T) {
+	client, _, _ := newTestClient(t, testPod("default", "web", "node-a"))
+	client.SetReadOnly(true)
+
+	if err := client.DeletePod(Pod{Name: "web", Namespace: "default"}, false); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("DeletePod error = %v, want ErrReadOnly", err)
+	}
+	if err := client.CordonNode("node-a", true); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("CordonNode error = %v, want ErrReadOnly", err)
+	}
+	if pods, _ := client.GetPods(); len(pods) != 1 {
+		t.Errorf("pods after refused DeletePod = %v, want web", pods)
+	}
+}
+
+func TestBeginAuditsBeforeTheSessionEnds(t *testing.T) {
+	client, _, _ := newTestClient(t)
+	auditLog := audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	client.SetAuditLog(auditLog)
+
+	end, err := client.begin("exec", "default/pod/web (container app)")
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	entries, _ := auditLog.Entries(0)
+	if len(entries) != 1 || entries[0].Result != audit.ResultStarted {
+		t.Fatalf("entries while the session runs = %+v, want one started entry", entries)
+	}
+	end(errors.New("connection lost"))
+	entries, _ = auditLog.Entries(0)
+	if len(entries) != 2 || entries[0].Result != audit.ResultFailure || entries[0].Error != "connection lost" {
+		t.Errorf("entries after the session = %+v, want a failure entry first", entries)
+	}
+
+	client.SetReadOnly(true)
+	if _, err := client.begin("exec", "default/pod/web (container app)"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("read-only begin error = %v, want ErrReadOnly", err)
+	}
+	if entries, _ = auditLog.Entries(1); len(entries) != 1 || entries[0].Result != audit.ResultDenied {
+		t.Errorf("latest entry after a read-only begin = %+v, want denied", entries)
+	}
+}
@@ -0,0 +1,75 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterSummary is the health of the whole cluster at a glance.
+type ClusterSummary struct {
+	// ServerVersion is the Kubernetes version of the API server, empty when
+	// it cannot be read.
+	ServerVersion string
+	Nodes         int
+	ReadyNodes    int
+	PodCounts
+	// Usage sums the usage of the nodes. It is only set when UsageAvailable,
+	// since metrics may not be available.
+	Usage          Resources
+	UsageAvailable bool
+	Allocatable    Resources
+	// Warnings counts the warning events of the last hour.
+	Warnings int
+}
+
+// GetClusterSummary summarizes the nodes, pods, usage and recent warnings of
+// the cluster. Like ListNamespaceSummaries it only fails when nodes or pods
+// cannot be listed.
+func (c *Client) GetClusterSummary() (ClusterSummary, error) {
+	var summary ClusterSummary
+	nodes, err := c.clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return summary, err
+	}
+	pods, err := c.clientset.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return summary, err
+	}
+
+	if info, err := c.clientset.Discovery().ServerVersion(); err == nil {
+		summary.ServerVersion = info.GitVersion
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		summary.Nodes++
+		if isNodeReady(node) {
+			summary.ReadyNodes++
+		}
+		summary.Allocatable.CPU += node.Status.Allocatable.Cpu().MilliValue()
+		summary.Allocatable.Memory += node.Status.Allocatable.Memory().Value()
+	}
+	for _, pod := range pods.Items {
+		summary.Add(pod.Status.Phase)
+	}
+	if usages, err := c.ListNodeUsage(); err == nil {
+		summary.UsageAvailable = true
+		for _, usage := range usages {
+			summary.Usage.CPU += usage.CPU
+			summary.Usage.Memory += usage.Memory
+		}
+	}
+	if warnings, err := c.recentWarnings(); err == nil {
+		for _, count := range warnings {
+			summary.Warnings += count
+		}
+	}
+	return summary, nil
+}
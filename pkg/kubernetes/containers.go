@@ -0,0 +1,164 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// describeContainers formats the init containers and containers of pod, with
+// the state, restarts, last termination, usage, requests and limits of each,
+// so a sidecar using too much memory or a crash-looping container stands out.
+// Usage is left out when metrics are not available.
+func (c *Client) describeContainers(pod *v1.Pod) string {
+	usages := make(map[string]Usage)
+	if podMetrics, err := c.metricsClient.MetricsV1beta1().PodMetricses(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{}); err == nil {
+		for _, container := range podMetrics.Containers {
+			usages[container.Name] = toUsage(podMetrics.Timestamp.Time, container.Usage)
+		}
+	}
+
+	details := ""
+	if len(pod.Spec.InitContainers) > 0 {
+		details += "[yellow::b]Init Containers[-::-]\n"
+		for _, container := range pod.Spec.InitContainers {
+			details += describeContainer(container, findContainerStatus(pod.Status.InitContainerStatuses, container.Name), usages)
+		}
+	}
+	details += "[yellow::b]Containers[-::-]\n"
+	for _, container := range pod.Spec.Containers {
+		details += describeContainer(container, findContainerStatus(pod.Status.ContainerStatuses, container.Name), usages)
+	}
+	return details
+}
+
+func describeContainer(container v1.Container, status *v1.ContainerStatus, usages map[string]Usage) string {
+	title := container.Name
+	if container.RestartPolicy != nil && *container.RestartPolicy == v1.ContainerRestartPolicyAlways {
+		title += " [gray](sidecar)[-]"
+	}
+	details := fmt.Sprintf("  [green]Container:[-] %s\n", title)
+	details += fmt.Sprintf("    [lightcyan]Image:[-] %s\n", container.Image)
+
+	if status != nil {
+		details += fmt.Sprintf("    [lightcyan]State:[-] %s\n", describeContainerState(status.State, status.Ready))
+		restartColor := "-"
+		if status.RestartCount > 0 {
+			restartColor = "orange"
+		}
+		details += fmt.Sprintf("    [lightcyan]Restarts:[-] [%s]%d[-]\n", restartColor, status.RestartCount)
+		if last := status.LastTerminationState.Terminated; last != nil {
+			details += fmt.Sprintf("    [lightcyan]Last Termination:[-] %s\n", describeTermination(last))
+		}
+	}
+
+	requests := toResources(container.Resources.Requests)
+	limits := toResources(container.Resources.Limits)
+	if usage, ok := usages[container.Name]; ok {
+		details += "    [lightgreen::b]Usage[-::-]\n"
+		details += fmt.Sprintf("      [lightcyan]CPU:[-] %s%s\n", FormatCPU(usage.CPU), describeShare(usage.CPU, requests.CPU, limits.CPU))
+		details += fmt.Sprintf("      [lightcyan]Memory:[-] %s%s\n", FormatMemory(usage.Memory), describeShare(usage.Memory, requests.Memory, limits.Memory))
+	}
+
+	details += "    [lightgreen::b]Requests[-::-]\n"
+	details += describeQuantity("CPU", container.Resources.Requests, v1.ResourceCPU)
+	details += describeQuantity("Memory", container.Resources.Requests, v1.ResourceMemory)
+	details += "    [lightgreen::b]Limits[-::-]\n"
+	details += describeQuantity("CPU", container.Resources.Limits, v1.ResourceCPU)
+	details += describeQuantity("Memory", container.Resources.Limits, v1.ResourceMemory)
+	return details + "\n"
+}
+
+func describeQuantity(label string, resources v1.ResourceList, name v1.ResourceName) string {
+	if quantity, ok := resources[name]; ok {
+		return fmt.Sprintf("      [lightcyan]%s:[-] %s\n", label, quantity.String())
+	}
+	return fmt.Sprintf("      [lightcyan]%s:[-] Not Set\n", label)
+}
+
+// describeShare describes usage as a percentage of request and limit, leaving
+// out those that are unset.
+func describeShare(usage int64, request int64, limit int64) string {
+	var parts []string
+	if percent, ok := Percent(usage, request); ok {
+		parts = append(parts, fmt.Sprintf("%d%% of request", percent))
+	}
+	if percent, ok := Percent(usage, limit); ok {
+		color := "gray"
+		if percent >= 90 {
+			color = "red"
+		}
+		parts = append(parts, fmt.Sprintf("[%s]%d%% of limit[gray]", color, percent))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " [gray](" + strings.Join(parts, ", ") + ")[-]"
+}
+
+func describeContainerState(state v1.ContainerState, ready bool) string {
+	switch {
+	case state.Running != nil:
+		readiness := "[orange]not ready[-]"
+		if ready {
+			readiness = "ready"
+		}
+		return fmt.Sprintf("[green]Running[-], %s, since %s", readiness, state.Running.StartedAt.Local().Format(detailsTimeFormat))
+	case state.Waiting != nil:
+		text := "[orange]Waiting[-]"
+		if state.Waiting.Reason != "" {
+			text += fmt.Sprintf(" (%s)", state.Waiting.Reason)
+		}
+		if state.Waiting.Message != "" {
+			text += ": " + state.Waiting.Message
+		}
+		return text
+	case state.Terminated != nil:
+		return "Terminated, " + describeTermination(state.Terminated)
+	default:
+		return "[gray]Unknown[-]"
+	}
+}
+
+// describeTermination gives the reason, exit code and time a container
+// stopped, in red unless it completed successfully.
+func describeTermination(terminated *v1.ContainerStateTerminated) string {
+	color := "red"
+	if terminated.ExitCode == 0 {
+		color = "green"
+	}
+	reason := terminated.Reason
+	if reason == "" {
+		reason = "Error"
+		if terminated.ExitCode == 0 {
+			reason = "Completed"
+		}
+	}
+	text := fmt.Sprintf("[%s]%s[-], exit code %d", color, reason, terminated.ExitCode)
+	if terminated.Signal != 0 {
+		text += fmt.Sprintf(", signal %d", terminated.Signal)
+	}
+	if !terminated.FinishedAt.IsZero() {
+		text += ", at " + terminated.FinishedAt.Local().Format(detailsTimeFormat)
+	}
+	return text
+}
+
+func findContainerStatus(statuses []v1.ContainerStatus, name string) *v1.ContainerStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+	drainPollInterval = 2 * time.Second
+)
+
+// drainRetryInterval is the wait before retrying an eviction refused by a
+// PodDisruptionBudget.
+var drainRetryInterval = 5 * time.Second
+
+const (
+	DrainPending   = "Pending"
+	DrainSkipped   = "Skipped"
+	DrainEvicting  = "Evicting"
+	DrainBlocked   = "Blocked by PDB"
+	DrainWaiting   = "Terminating"
+	DrainEvicted   = "Evicted"
+	DrainFailed    = "Failed"
+	DrainCancelled = "Cancelled"
+)
+
+// DrainOptions mirrors the options of kubectl drain.
+type DrainOptions struct {
+	IgnoreDaemonSets   bool
+	DeleteEmptyDirData bool
+	// Force also evicts pods that are not managed by a controller. They are
+	// not recreated anywhere.
+	Force bool
+	// Timeout bounds the whole drain, zero waits forever.
+	Timeout time.Duration
+	// GracePeriodSeconds overrides the termination grace period of the pods
+	// when it is set.
+	GracePeriodSeconds *int64
+}
+
+// DrainEvent reports the progress of a single pod during a drain.
+type DrainEvent struct {
+	Pod     Pod
+	Status  string
+	Message string
+}
+
+type drainAction int
+
+const (
+	drainEvict drainAction = iota
+	drainSkip
+	drainRefuse
+)
+
+func (c *Client) CordonNode(nodeName string, cordon bool) error {
+	verb := "cordon"
+	if !cordon {
+		verb = "uncordon"
+	}
+	patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, cordon)
+	return c.write(verb, nodeRef(nodeName).String(), func() error {
+		_, err := c.clientset.CoreV1().Nodes().Patch(context.TODO(), nodeName, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{FieldManager: fieldManager})
+		return err
+	})
+}
+
+// DrainNode cordons nodeName and evicts its pods, reporting the progress of
+// every pod to progress. Evictions refused by a PodDisruptionBudget are retried
+// until they succeed, the timeout expires or ctx is cancelled. Nothing is
+// evicted if a pod cannot be drained with the given options. The drain is
+// audited when it starts and ends, the cordon and the final outcome of every
+// eviction in between.
+func (c *Client) DrainNode(ctx context.Context, nodeName string, options DrainOptions, progress func(DrainEvent)) error {
+	end, err := c.begin("drain", nodeRef(nodeName).String())
+	if err != nil {
+		return err
+	}
+	err = c.drainNode(ctx, nodeName, options, progress)
+	end(err)
+	return err
+}
+
+func (c *Client) drainNode(ctx context.Context, nodeName string, options DrainOptions, progress func(DrainEvent)) error {
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	if err := c.CordonNode(nodeName, true); err != nil {
+		return fmt.Errorf("failed to cordon node %s: %v", nodeName, err)
+	}
+
+	pods, err := c.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("spec.nodeName=%s", nodeName),
+	})
+	if err != nil {
+		return err
+	}
+
+	var toEvict []v1.Pod
+	var refused []string
+	for _, pod := range pods.Items {
+		action, reason := classifyDrainPod(&pod, options)
+		switch action {
+		case drainSkip:
+			progress(DrainEvent{Pod: podRef(&pod), Status: DrainSkipped, Message: reason})
+		case drainRefuse:
+			refused = append(refused, fmt.Sprintf("%s/%s (%s)", pod.Namespace, pod.Name, reason))
+			progress(DrainEvent{Pod: podRef(&pod), Status: DrainFailed, Message: reason})
+		default:
+			toEvict = append(toEvict, pod)
+			progress(DrainEvent{Pod: podRef(&pod), Status: DrainPending})
+		}
+	}
+	if len(refused) > 0 {
+		return fmt.Errorf("cannot drain node %s, these pods need more options: %s", nodeName, strings.Join(refused, ", "))
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(toEvict))
+	for i := range toEvict {
+		wg.Add(1)
+		go func(pod *v1.Pod) {
+			defer wg.Done()
+			if err := c.evictAndWait(ctx, pod, options, progress); err != nil {
+				errs <- err
+			}
+		}(&toEvict[i])
+	}
+	wg.Wait()
+	close(errs)
+
+	if failed := len(errs); failed > 0 {
+		return fmt.Errorf("%d of %d pods were not evicted from node %s: %v", failed, len(toEvict), nodeName, <-errs)
+	}
+	return nil
+}
+
+// evictAndWait evicts pod, retrying while a PodDisruptionBudget blocks it, and
+// waits for the pod to be gone from the node. Only the final outcome of the
+// eviction is audited, not every refused attempt.
+func (c *Client) evictAndWait(ctx context.Context, pod *v1.Pod, options DrainOptions, progress func(DrainEvent)) error {
+	ref := podRef(pod)
+	err := c.evict(ctx, pod, options, progress)
+	c.record("evict", podObjectRef(ref).String(), outcome(err), err)
+	if err != nil {
+		return err
+	}
+
+	progress(DrainEvent{Pod: ref, Status: DrainWaiting})
+	for {
+		current, err := c.clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) || (err == nil && current.UID != pod.UID) {
+			progress(DrainEvent{Pod: ref, Status: DrainEvicted})
+			return nil
+		}
+		if !sleepContext(ctx, drainPollInterval) {
+			progress(DrainEvent{Pod: ref, Status: DrainCancelled, Message: "pod is still terminating"})
+			return fmt.Errorf("%s/%s: %v", pod.Namespace, pod.Name, ctx.Err())
+		}
+	}
+}
+
+// evict requests the eviction of pod until it is accepted or refused for
+// another reason than a PodDisruptionBudget.
+func (c *Client) evict(ctx context.Context, pod *v1.Pod, options DrainOptions, progress func(DrainEvent)) error {
+	ref := podRef(pod)
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+	}
+	if options.GracePeriodSeconds != nil {
+		eviction.DeleteOptions = &metav1.DeleteOptions{GracePeriodSeconds: options.GracePeriodSeconds}
+	}
+
+	for {
+		progress(DrainEvent{Pod: ref, Status: DrainEvicting})
+		err := c.clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+		if err == nil || apierrors.IsNotFound(err) {
+			return nil
+		}
+		if !apierrors.IsTooManyRequests(err) {
+			progress(DrainEvent{Pod: ref, Status: DrainFailed, Message: DescribeEvictionError(err)})
+			return fmt.Errorf("%s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+
+		progress(DrainEvent{Pod: ref, Status: DrainBlocked, Message: err.Error()})
+		if !sleepContext(ctx, drainRetryInterval) {
+			progress(DrainEvent{Pod: ref, Status: DrainCancelled, Message: "still blocked by a PodDisruptionBudget"})
+			return fmt.Errorf("%s/%s: %v", pod.Namespace, pod.Name, ctx.Err())
+		}
+	}
+}
+
+// classifyDrainPod decides what a drain does with pod, following the rules of
+// kubectl drain.
+func classifyDrainPod(pod *v1.Pod, options DrainOptions) (drainAction, string) {
+	if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+		return drainSkip, "static pod managed by the kubelet"
+	}
+	if isPodCompleted(pod) {
+		return drainEvict, "completed"
+	}
+
+	controller := metav1.GetControllerOf(pod)
+	if controller != nil && controller.Kind == "DaemonSet" {
+		if options.IgnoreDaemonSets {
+			return drainSkip, "managed by DaemonSet " + controller.Name
+		}
+		return drainRefuse, "managed by DaemonSet " + controller.Name
+	}
+	if controller == nil && !options.Force {
+		return drainRefuse, "not managed by a controller"
+	}
+	if usesEmptyDir(pod) && !options.DeleteEmptyDirData {
+		return drainRefuse, "uses emptyDir local storage"
+	}
+	return drainEvict, ""
+}
+
+func usesEmptyDir(pod *v1.Pod) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func podRef(pod *v1.Pod) Pod {
+	return Pod{Name: pod.Name, Namespace: pod.Namespace, NodeName: pod.Spec.NodeName}
+}
+
+func podObjectRef(pod Pod) ObjectRef {
+	return ObjectRef{Resource: PodResource, Namespace: pod.Namespace, Name: pod.Name}
+}
+
+func nodeRef(nodeName string) ObjectRef {
+	return ObjectRef{Resource: NodeResource, Name: nodeName}
+}
+
+// sleepContext waits for d and reports false if ctx ended first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
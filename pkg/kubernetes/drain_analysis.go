@@ -0,0 +1,191 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// DrainReport is the result of a drain dry-run for a node.
+type DrainReport struct {
+	Node string
+	Pods []DrainPodImpact
+	// CPURequests and MemoryRequests sum the requests of the evicted pods that
+	// need to be rescheduled, in millicores and bytes.
+	CPURequests    int64
+	MemoryRequests int64
+	Nodes          []NodeCapacity
+	// Fits reports whether the free allocatable resources of the remaining
+	// nodes add up to the evicted requests. Scheduling constraints are not
+	// taken into account.
+	Fits bool
+}
+
+// DrainPodImpact describes what a drain would do with a single pod.
+type DrainPodImpact struct {
+	Pod    Pod
+	Status string
+	Reason string
+	// Unmanaged pods have no controller and are not recreated once evicted.
+	Unmanaged    bool
+	LocalStorage bool
+	// BlockingPDB names the PodDisruptionBudget that currently refuses the
+	// eviction, if any.
+	BlockingPDB   string
+	CPURequest    int64
+	MemoryRequest int64
+}
+
+// NodeCapacity is the allocatable CPU and memory left on a schedulable node.
+type NodeCapacity struct {
+	Name       string
+	FreeCPU    int64
+	FreeMemory int64
+}
+
+// AnalyzeDrain reports what draining nodeName with options would do without
+// changing anything in the cluster.
+func (c *Client) AnalyzeDrain(nodeName string, options DrainOptions) (*DrainReport, error) {
+	pods, err := c.clientset.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := c.clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DrainReport{Node: nodeName}
+	pdbs := make(map[string][]policyv1.PodDisruptionBudget)
+	// disruptions tracks how many evictions each budget still allows, so pods
+	// sharing a budget are only reported as blocked once it is used up.
+	disruptions := make(map[string]int32)
+	requested := make(map[string][2]int64)
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != nodeName {
+			if pod.Spec.NodeName != "" && !isPodCompleted(pod) {
+				cpu, memory := podRequests(pod)
+				total := requested[pod.Spec.NodeName]
+				requested[pod.Spec.NodeName] = [2]int64{total[0] + cpu, total[1] + memory}
+			}
+			continue
+		}
+
+		action, reason := classifyDrainPod(pod, options)
+		impact := DrainPodImpact{
+			Pod:          podRef(pod),
+			Reason:       reason,
+			Unmanaged:    metav1.GetControllerOf(pod) == nil,
+			LocalStorage: usesEmptyDir(pod),
+		}
+		switch action {
+		case drainSkip:
+			impact.Status = DrainSkipped
+		case drainRefuse:
+			impact.Status = DrainFailed
+		default:
+			impact.Status = DrainEvicted
+		}
+
+		if action == drainEvict && !isPodCompleted(pod) {
+			if _, ok := pdbs[pod.Namespace]; !ok {
+				list, err := c.clientset.PolicyV1().PodDisruptionBudgets(pod.Namespace).List(context.TODO(), metav1.ListOptions{})
+				if err != nil {
+					return nil, fmt.Errorf("failed to list PodDisruptionBudgets in %s: %v", pod.Namespace, err)
+				}
+				pdbs[pod.Namespace] = list.Items
+				for _, pdb := range list.Items {
+					disruptions[pdb.Namespace+"/"+pdb.Name] = pdb.Status.DisruptionsAllowed
+				}
+			}
+			for _, pdb := range pdbs[pod.Namespace] {
+				selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+				if err != nil || selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+					continue
+				}
+				key := pdb.Namespace + "/" + pdb.Name
+				if disruptions[key] <= 0 {
+					impact.Status = DrainBlocked
+					impact.BlockingPDB = pdb.Name
+				}
+				disruptions[key]--
+			}
+
+			if !impact.Unmanaged {
+				impact.CPURequest, impact.MemoryRequest = podRequests(pod)
+				report.CPURequests += impact.CPURequest
+				report.MemoryRequests += impact.MemoryRequest
+			}
+		}
+
+		report.Pods = append(report.Pods, impact)
+	}
+
+	var freeCPU, freeMemory int64
+	for _, node := range nodes.Items {
+		if node.Name == nodeName || node.Spec.Unschedulable || !isNodeReady(&node) {
+			continue
+		}
+		total := requested[node.Name]
+		capacity := NodeCapacity{
+			Name:       node.Name,
+			FreeCPU:    node.Status.Allocatable.Cpu().MilliValue() - total[0],
+			FreeMemory: node.Status.Allocatable.Memory().Value() - total[1],
+		}
+		report.Nodes = append(report.Nodes, capacity)
+		freeCPU += max(capacity.FreeCPU, 0)
+		freeMemory += max(capacity.FreeMemory, 0)
+	}
+	report.Fits = freeCPU >= report.CPURequests && freeMemory >= report.MemoryRequests
+
+	sort.Slice(report.Pods, func(i, j int) bool {
+		if report.Pods[i].Pod.Namespace != report.Pods[j].Pod.Namespace {
+			return report.Pods[i].Pod.Namespace < report.Pods[j].Pod.Namespace
+		}
+		return report.Pods[i].Pod.Name < report.Pods[j].Pod.Name
+	})
+	return report, nil
+}
+
+// podRequests returns the effective CPU (millicores) and memory (bytes)
+// requests of pod: the larger of the sum over its containers and the largest
+// init container.
+func podRequests(pod *v1.Pod) (int64, int64) {
+	var cpu, memory int64
+	for _, container := range pod.Spec.Containers {
+		cpu += container.Resources.Requests.Cpu().MilliValue()
+		memory += container.Resources.Requests.Memory().Value()
+	}
+	for _, container := range pod.Spec.InitContainers {
+		cpu = max(cpu, container.Resources.Requests.Cpu().MilliValue())
+		memory = max(memory, container.Resources.Requests.Memory().Value())
+	}
+	return cpu, memory
+}
+
+func isPodCompleted(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed
+}
+
+func isNodeReady(node *v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
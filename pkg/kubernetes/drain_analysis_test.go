@@ -0,0 +1,131 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func readyNode(name string, cpu string, memory string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1.NodeStatus{
+			Allocatable: usage(cpu, memory),
+			Conditions:  []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+		},
+	}
+}
+
+func withRequests(pod *v1.Pod, cpu string, memory string) *v1.Pod {
+	pod.Spec.Containers = []v1.Container{{Name: "main", Resources: v1.ResourceRequirements{Requests: usage(cpu, memory)}}}
+	return pod
+}
+
+func budget(name string, app string, allowed int32) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}}},
+		Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: allowed},
+	}
+}
+
+func TestAnalyzeDrain(t *testing.T) {
+	webA := withRequests(ownedPod("web-a", "ReplicaSet", "web-1"), "500m", "1Gi")
+	webB := withRequests(ownedPod("web-b", "ReplicaSet", "web-1"), "500m", "1Gi")
+	webA.Labels["app"], webB.Labels["app"] = "web", "web"
+	cordoned := readyNode("node-c", "8", "32Gi")
+	cordoned.Spec.Unschedulable = true
+	elsewhere := withRequests(ownedPod("busy", "ReplicaSet", "busy-1"), "1500m", "2Gi")
+	elsewhere.Spec.NodeName = "node-b"
+
+	client, _, _ := newTestClient(t,
+		readyNode("node-a", "4", "16Gi"), readyNode("node-b", "2", "4Gi"), cordoned,
+		webA, webB, elsewhere,
+		ownedPod("agent", "DaemonSet", "agent"),
+		withEmptyDir(withRequests(ownedPod("cache", "ReplicaSet", "cache-1"), "100m", "128Mi")),
+		withRequests(testPod("default", "debug", "node-a"), "1", "1Gi"),
+		budget("web", "web", 1),
+	)
+
+	report, err := client.AnalyzeDrain("node-a", DrainOptions{IgnoreDaemonSets: true, DeleteEmptyDirData: true, Force: true})
+	if err != nil {
+		t.Fatalf("AnalyzeDrain: %v", err)
+	}
+
+	impacts := make(map[string]DrainPodImpact)
+	for _, impact := range report.Pods {
+		impacts[impact.Pod.Name] = impact
+	}
+	if len(impacts) != 5 {
+		t.Errorf("AnalyzeDrain reported %d pods, want the 5 on node-a: %+v", len(impacts), report.Pods)
+	}
+	// The budget allows one disruption, so the second web pod is blocked.
+	if impact := impacts["web-a"]; impact.Status != DrainEvicted || impact.BlockingPDB != "" {
+		t.Errorf("web-a = %+v, want evicted", impact)
+	}
+	if impact := impacts["web-b"]; impact.Status != DrainBlocked || impact.BlockingPDB != "web" {
+		t.Errorf("web-b = %+v, want blocked by web", impact)
+	}
+	if impact := impacts["agent"]; impact.Status != DrainSkipped || impact.Reason != "managed by DaemonSet agent" {
+		t.Errorf("agent = %+v, want skipped as a DaemonSet pod", impact)
+	}
+	if impact := impacts["cache"]; impact.Status != DrainEvicted || !impact.LocalStorage {
+		t.Errorf("cache = %+v, want evicted with local storage", impact)
+	}
+	if impact := impacts["debug"]; impact.Status != DrainEvicted || !impact.Unmanaged || impact.CPURequest != 0 {
+		t.Errorf("debug = %+v, want evicted as unmanaged without requests to reschedule", impact)
+	}
+
+	// Unmanaged pods are not recreated, so only web-a, web-b and cache need
+	// room. node-b has 500m and 2Gi free, and node-c is cordoned.
+	if report.CPURequests != 1100 || report.MemoryRequests != 2<<30+128<<20 {
+		t.Errorf("requests to reschedule = %dm %d, want 1100m 2Gi+128Mi", report.CPURequests, report.MemoryRequests)
+	}
+	if len(report.Nodes) != 1 || report.Nodes[0] != (NodeCapacity{Name: "node-b", FreeCPU: 500, FreeMemory: 2 << 30}) {
+		t.Errorf("remaining nodes = %+v, want node-b with 500m and 2Gi free", report.Nodes)
+	}
+	if report.Fits {
+		t.Error("report fits, want the remaining nodes too small")
+	}
+}
+
+func TestAnalyzeDrainRefusals(t *testing.T) {
+	client, _, _ := newTestClient(t,
+		readyNode("node-a", "4", "16Gi"), readyNode("node-b", "4", "16Gi"),
+		ownedPod("agent", "DaemonSet", "agent"),
+		withEmptyDir(ownedPod("cache", "ReplicaSet", "cache-1")),
+		testPod("default", "debug", "node-a"),
+		withRequests(ownedPod("web", "ReplicaSet", "web-1"), "1", "1Gi"),
+	)
+
+	report, err := client.AnalyzeDrain("node-a", DrainOptions{})
+	if err != nil {
+		t.Fatalf("AnalyzeDrain: %v", err)
+	}
+	want := map[string]string{
+		"agent": "managed by DaemonSet agent",
+		"cache": "uses emptyDir local storage",
+		"debug": "not managed by a controller",
+	}
+	for _, impact := range report.Pods {
+		reason, refused := want[impact.Pod.Name]
+		switch {
+		case refused && (impact.Status != DrainFailed || impact.Reason != reason):
+			t.Errorf("%s = %+v, want refused because %s", impact.Pod.Name, impact, reason)
+		case !refused && impact.Status != DrainEvicted:
+			t.Errorf("%s = %+v, want evicted", impact.Pod.Name, impact)
+		}
+	}
+	if !report.Fits || report.CPURequests != 1000 {
+		t.Errorf("report = %+v, want web's 1 CPU to fit on node-b", report)
+	}
+}
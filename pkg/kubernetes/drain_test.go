@@ -0,0 +1,271 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/audit"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// ownedPod returns a pod on node-a controlled by a kind called owner.
+func ownedPod(name string, kind string, owner string) *v1.Pod {
+	pod := testPod("default", name, "node-a")
+	pod.Labels = map[string]string{"app": name}
+	pod.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "apps/v1",
+		Kind:       kind,
+		Name:       owner,
+		UID:        types.UID("uid-" + owner),
+		Controller: func() *bool { controller := true; return &controller }(),
+	}}
+	pod.Status.Phase = v1.PodRunning
+	return pod
+}
+
+func withEmptyDir(pod *v1.Pod) *v1.Pod {
+	pod.Spec.Volumes = []v1.Volume{{Name: "cache", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}}}
+	return pod
+}
+
+func TestClassifyDrainPod(t *testing.T) {
+	mirror := testPod("kube-system", "etcd-node-a", "node-a")
+	mirror.Annotations = map[string]string{mirrorPodAnnotation: "hash"}
+	completed := testPod("default", "job", "node-a")
+	completed.Status.Phase = v1.PodSucceeded
+	bare := testPod("default", "debug", "node-a")
+
+	tests := []struct {
+		name    string
+		pod     *v1.Pod
+		options DrainOptions
+		action  drainAction
+		reason  string
+	}{
+		{"mirror pod", mirror, DrainOptions{}, drainSkip, "static pod managed by the kubelet"},
+		{"completed unmanaged pod", completed, DrainOptions{}, drainEvict, "completed"},
+		{"daemon set pod", ownedPod("agent", "DaemonSet", "agent"), DrainOptions{}, drainRefuse, "managed by DaemonSet agent"},
+		{"ignored daemon set pod", ownedPod("agent", "DaemonSet", "agent"), DrainOptions{IgnoreDaemonSets: true}, drainSkip, "managed by DaemonSet agent"},
+		{"unmanaged pod", bare, DrainOptions{}, drainRefuse, "not managed by a controller"},
+		{"forced unmanaged pod", bare, DrainOptions{Force: true}, drainEvict, ""},
+		{"emptyDir pod", withEmptyDir(ownedPod("web", "ReplicaSet", "web-1")), DrainOptions{}, drainRefuse, "uses emptyDir local storage"},
+		{"deleted emptyDir pod", withEmptyDir(ownedPod("web", "ReplicaSet", "web-1")), DrainOptions{DeleteEmptyDirData: true}, drainEvict, ""},
+		{"replica set pod", ownedPod("web", "ReplicaSet", "web-1"), DrainOptions{}, drainEvict, ""},
+	}
+	for _, test := range tests {
+		action, reason := classifyDrainPod(test.pod, test.options)
+		if action != test.action || reason != test.reason {
+			t.Errorf("%s: classifyDrainPod = %v %q, want %v %q", test.name, action, reason, test.action, test.reason)
+		}
+	}
+}
+
+// evictionRecorder evicts pods from the tracker of clientset and remembers
+// the options of every eviction.
+type evictionRecorder struct {
+	mu        sync.Mutex
+	evictions map[string]*metav1.DeleteOptions
+}
+
+func recordEvictions(clientset *fake.Clientset) *evictionRecorder {
+	recorder := &evictionRecorder{evictions: make(map[string]*metav1.DeleteOptions)}
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+		recorder.mu.Lock()
+		recorder.evictions[eviction.Name] = eviction.DeleteOptions
+		recorder.mu.Unlock()
+		return true, nil, clientset.Tracker().Delete(action.GetResource(), eviction.Namespace, eviction.Name)
+	})
+	return recorder
+}
+
+func (r *evictionRecorder) names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var names []string
+	for name := range r.evictions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// drainEvents collects the last status of every pod of a drain.
+type drainEvents struct {
+	mu     sync.Mutex
+	status map[string]string
+}
+
+func (e *drainEvents) progress(event DrainEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.status == nil {
+		e.status = make(map[string]string)
+	}
+	e.status[event.Pod.Name] = event.Status
+}
+
+func TestDrainNode(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}
+	client, clientset, _ := newTestClient(t, node,
+		ownedPod("web", "ReplicaSet", "web-1"),
+		ownedPod("db", "StatefulSet", "db"),
+		ownedPod("agent", "DaemonSet", "agent"),
+	)
+	auditLog := audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	client.SetAuditLog(auditLog)
+	recorder := recordEvictions(clientset)
+
+	var events drainEvents
+	if err := client.DrainNode(context.Background(), "node-a", DrainOptions{IgnoreDaemonSets: true}, events.progress); err != nil {
+		t.Fatalf("DrainNode: %v", err)
+	}
+
+	cordoned, _ := clientset.CoreV1().Nodes().Get(context.TODO(), "node-a", metav1.GetOptions{})
+	if !cordoned.Spec.Unschedulable {
+		t.Error("node-a is schedulable after DrainNode")
+	}
+	if got, want := recorder.names(), []string{"db", "web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("evicted pods = %v, want %v", got, want)
+	}
+	// Without a grace period the pods keep their own.
+	if options := recorder.evictions["web"]; options != nil {
+		t.Errorf("eviction options = %+v, want none", options)
+	}
+	if want := map[string]string{"web": DrainEvicted, "db": DrainEvicted, "agent": DrainSkipped}; !reflect.DeepEqual(events.status, want) {
+		t.Errorf("drain events = %v, want %v", events.status, want)
+	}
+
+	// The drain is audited around the cordon and one entry per eviction.
+	if got, want := auditedDrain(t, auditLog), []string{"drain started", "cordon success", "evict success", "evict success", "drain success"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("audit entries = %v, want %v", got, want)
+	}
+}
+
+// auditedDrain returns the verb and result of the entries in auditLog, oldest
+// first, with the entries between the start and the end of the drain sorted.
+func auditedDrain(t *testing.T, auditLog *audit.Log) []string {
+	t.Helper()
+	entries, err := auditLog.Entries(0)
+	if err != nil {
+		t.Fatalf("reading the audit log: %v", err)
+	}
+	var got []string
+	for i := len(entries) - 1; i >= 0; i-- {
+		got = append(got, entries[i].Verb+" "+entries[i].Result)
+	}
+	if len(got) > 2 {
+		sort.Strings(got[1 : len(got)-1])
+	}
+	return got
+}
+
+func TestDrainNodeGracePeriod(t *testing.T) {
+	client, clientset, _ := newTestClient(t, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}, ownedPod("web", "ReplicaSet", "web-1"))
+	recorder := recordEvictions(clientset)
+
+	gracePeriod := int64(0)
+	if err := client.DrainNode(context.Background(), "node-a", DrainOptions{GracePeriodSeconds: &gracePeriod}, func(DrainEvent) {}); err != nil {
+		t.Fatalf("DrainNode: %v", err)
+	}
+	if options := recorder.evictions["web"]; options == nil || options.GracePeriodSeconds == nil || *options.GracePeriodSeconds != 0 {
+		t.Errorf("eviction options = %+v, want a grace period of 0", options)
+	}
+}
+
+func TestDrainNodeRefusesPodsNeedingOptions(t *testing.T) {
+	client, clientset, _ := newTestClient(t, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+		ownedPod("web", "ReplicaSet", "web-1"),
+		testPod("default", "debug", "node-a"),
+		withEmptyDir(ownedPod("cache", "ReplicaSet", "cache-1")),
+	)
+	recorder := recordEvictions(clientset)
+
+	var events drainEvents
+	err := client.DrainNode(context.Background(), "node-a", DrainOptions{}, events.progress)
+	if err == nil || !strings.Contains(err.Error(), "default/debug (not managed by a controller)") || !strings.Contains(err.Error(), "default/cache (uses emptyDir local storage)") {
+		t.Errorf("DrainNode error = %v, want the unmanaged and emptyDir pods listed", err)
+	}
+	if names := recorder.names(); len(names) != 0 {
+		t.Errorf("evicted pods = %v, want none", names)
+	}
+	if events.status["web"] != DrainPending || events.status["debug"] != DrainFailed {
+		t.Errorf("drain events = %v, want web pending and debug failed", events.status)
+	}
+}
+
+func TestDrainNodeBlockedByDisruptionBudget(t *testing.T) {
+	client, clientset, _ := newTestClient(t, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}, ownedPod("web", "ReplicaSet", "web-1"))
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+	})
+
+	var events drainEvents
+	err := client.DrainNode(context.Background(), "node-a", DrainOptions{Timeout: 100 * time.Millisecond}, events.progress)
+	if err == nil || !strings.Contains(err.Error(), "1 of 1 pods were not evicted") {
+		t.Errorf("DrainNode error = %v, want the pod not evicted", err)
+	}
+	if events.status["web"] != DrainCancelled {
+		t.Errorf("status of web = %q, want %q", events.status["web"], DrainCancelled)
+	}
+}
+
+func TestDrainNodeAuditsRetriedEvictionOnce(t *testing.T) {
+	previous := drainRetryInterval
+	drainRetryInterval = 10 * time.Millisecond
+	t.Cleanup(func() { drainRetryInterval = previous })
+
+	client, clientset, _ := newTestClient(t, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}, ownedPod("web", "ReplicaSet", "web-1"))
+	auditLog := audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	client.SetAuditLog(auditLog)
+	recorder := recordEvictions(clientset)
+	attempts := 0
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if attempts++; attempts <= 2 {
+			return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+		}
+		return false, nil, nil
+	})
+
+	if err := client.DrainNode(context.Background(), "node-a", DrainOptions{}, func(DrainEvent) {}); err != nil {
+		t.Fatalf("DrainNode: %v", err)
+	}
+	if names := recorder.names(); !reflect.DeepEqual(names, []string{"web"}) {
+		t.Errorf("evicted pods = %v, want [web]", names)
+	}
+	if got, want := auditedDrain(t, auditLog), []string{"drain started", "cordon success", "evict success", "drain success"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("audit entries = %v, want %v", got, want)
+	}
+}
+
+func TestDrainNodeReadOnly(t *testing.T) {
+	client, _, _ := newTestClient(t, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}, ownedPod("web", "ReplicaSet", "web-1"))
+	client.SetReadOnly(true)
+	if err := client.DrainNode(context.Background(), "node-a", DrainOptions{}, func(DrainEvent) {}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("read-only DrainNode error = %v, want ErrReadOnly", err)
+	}
+}
@@ -0,0 +1,128 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rdmnl/kubepulse/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const fieldManager = "kubepulse"
+
+// DiffResource sends manifest to the API server as a dry-run update of ref and
+// returns a unified diff between the live object and the result the server
+// would persist. An empty diff means the edit changes nothing.
+func (c *Client) DiffResource(ref ObjectRef, manifest string) (string, error) {
+	edited, err := parseEditedObject(ref, manifest)
+	if err != nil {
+		return "", err
+	}
+
+	live, err := c.getUnstructured(ref)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := c.updateUnstructured(ref, edited, true)
+	if err != nil {
+		return "", err
+	}
+
+	liveYAML, err := cleanYAML(live)
+	if err != nil {
+		return "", err
+	}
+	resultYAML, err := cleanYAML(result)
+	if err != nil {
+		return "", err
+	}
+
+	return utils.UnifiedDiff("live/"+ref.String(), "edited/"+ref.String(), liveYAML, resultYAML), nil
+}
+
+// UpdateResource replaces ref with manifest. The manifest keeps the
+// resourceVersion it was read with, so concurrent changes surface as conflicts.
+func (c *Client) UpdateResource(ref ObjectRef, manifest string) error {
+	edited, err := parseEditedObject(ref, manifest)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.updateUnstructured(ref, edited, false)
+	return err
+}
+
+func (c *Client) updateUnstructured(ref ObjectRef, obj *unstructured.Unstructured, dryRun bool) (*unstructured.Unstructured, error) {
+	options := metav1.UpdateOptions{FieldManager: fieldManager}
+	if dryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+
+	var result *unstructured.Unstructured
+	update := func() error {
+		var err error
+		resourceClient := c.dynamicClient.Resource(ref.Resource.GroupVersionResource())
+		if ref.Resource.Namespaced {
+			result, err = resourceClient.Namespace(ref.Namespace).Update(context.TODO(), obj, options)
+		} else {
+			result, err = resourceClient.Update(context.TODO(), obj, options)
+		}
+		return err
+	}
+
+	var err error
+	if dryRun {
+		if err = c.checkWritable(); err == nil {
+			err = update()
+		}
+	} else {
+		err = c.write("edit", ref.String(), update)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func parseEditedObject(ref ObjectRef, manifest string) (*unstructured.Unstructured, error) {
+	var content map[string]interface{}
+	if err := yaml.Unmarshal([]byte(manifest), &content); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %v", err)
+	}
+	if len(content) == 0 {
+		return nil, fmt.Errorf("edited manifest is empty")
+	}
+
+	obj := &unstructured.Unstructured{Object: content}
+	if obj.GetKind() != ref.Resource.Kind {
+		return nil, fmt.Errorf("kind cannot be changed from %s to %s", ref.Resource.Kind, obj.GetKind())
+	}
+	if obj.GetName() != ref.Name {
+		return nil, fmt.Errorf("name cannot be changed from %s to %s", ref.Name, obj.GetName())
+	}
+	if ref.Resource.Namespaced && obj.GetNamespace() != ref.Namespace {
+		return nil, fmt.Errorf("namespace cannot be changed from %s to %s", ref.Namespace, obj.GetNamespace())
+	}
+	return obj, nil
+}
+
+func cleanYAML(obj *unstructured.Unstructured) (string, error) {
+	obj = obj.DeepCopy()
+	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+
+	out, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s/%s as YAML: %v", obj.GetKind(), obj.GetName(), err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,109 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+var configMapResource = APIResource{Version: "v1", Name: "configmaps", SingularName: "configmap", Kind: "ConfigMap", Namespaced: true}
+
+// newEditClient returns a client whose dynamic client holds the config map
+// default/settings with data mode=fast.
+func newEditClient(t *testing.T) (*Client, *dynamicfake.FakeDynamicClient, ObjectRef) {
+	t.Helper()
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "settings", "namespace": "default"},
+		"data":       map[string]interface{}{"mode": "fast"},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), configMap)
+	client := NewClientFromInterfaces(fake.NewSimpleClientset(), metricsfake.NewSimpleClientset(), dynamicClient, "default")
+	return client, dynamicClient, ObjectRef{Resource: configMapResource, Namespace: "default", Name: "settings"}
+}
+
+const editedSettings = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: default
+data:
+  mode: safe
+`
+
+func TestDiffResource(t *testing.T) {
+	client, dynamicClient, ref := newEditClient(t)
+	// The fake dynamic client drops the dry-run option, so answer the update
+	// the way the server would without storing it.
+	dynamicClient.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, action.(k8stesting.UpdateAction).GetObject(), nil
+	})
+
+	diff, err := client.DiffResource(ref, editedSettings)
+	if err != nil {
+		t.Fatalf("DiffResource: %v", err)
+	}
+	if !strings.Contains(diff, "-  mode: fast\n+  mode: safe\n") {
+		t.Errorf("DiffResource =\n%s\nwant mode changed from fast to safe", diff)
+	}
+
+	live, err := client.GetResourceYAML(ref, false)
+	if err != nil || !strings.Contains(live, "mode: fast") {
+		t.Errorf("config map after DiffResource = %q, %v, want it unchanged", live, err)
+	}
+}
+
+func TestDiffResourceRejectsInvalidEdits(t *testing.T) {
+	client, _, ref := newEditClient(t)
+	tests := map[string]string{
+		"":        "empty",
+		"data: [": "invalid YAML",
+		strings.Replace(editedSettings, "ConfigMap", "Secret", 1):                   "kind cannot be changed",
+		strings.Replace(editedSettings, "name: settings", "name: other", 1):         "name cannot be changed",
+		strings.Replace(editedSettings, "namespace: default", "namespace: prod", 1): "namespace cannot be changed",
+	}
+	for manifest, want := range tests {
+		if _, err := client.DiffResource(ref, manifest); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("DiffResource(%q) error = %v, want %q", manifest, err, want)
+		}
+	}
+}
+
+func TestUpdateResource(t *testing.T) {
+	client, dynamicClient, ref := newEditClient(t)
+	if err := client.UpdateResource(ref, editedSettings); err != nil {
+		t.Fatalf("UpdateResource: %v", err)
+	}
+	updated, err := dynamicClient.Resource(configMapResource.GroupVersionResource()).Namespace("default").Get(context.TODO(), "settings", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("getting the config map: %v", err)
+	}
+	if mode, _, _ := unstructured.NestedString(updated.Object, "data", "mode"); mode != "safe" {
+		t.Errorf("mode after UpdateResource = %q, want safe", mode)
+	}
+
+	client.SetReadOnly(true)
+	if err := client.UpdateResource(ref, editedSettings); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("read-only UpdateResource error = %v, want ErrReadOnly", err)
+	}
+	if _, err := client.DiffResource(ref, editedSettings); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("read-only DiffResource error = %v, want ErrReadOnly", err)
+	}
+}
@@ -0,0 +1,83 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// DescribeError turns an API error into a readable message, listing every
+// field validation failure and explaining conflicts.
+func DescribeError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	switch {
+	case apierrors.IsConflict(err):
+		return fmt.Sprintf("Conflict: the object was modified since it was read. Reload it and try again.\n\n%v", err)
+	case apierrors.IsInvalid(err):
+		var status apierrors.APIStatus
+		if !errors.As(err, &status) || status.Status().Details == nil {
+			return fmt.Sprintf("Validation failed: %v", err)
+		}
+		var causes []string
+		for _, cause := range status.Status().Details.Causes {
+			causes = append(causes, fmt.Sprintf("- %s: %s", cause.Field, cause.Message))
+		}
+		return fmt.Sprintf("Validation failed for %s:\n%s", status.Status().Details.Name, strings.Join(causes, "\n"))
+	case apierrors.IsTooManyRequests(err):
+		return fmt.Sprintf("Throttled by the API server, try again shortly: %v", err)
+	case apierrors.IsNotFound(err):
+		return fmt.Sprintf("Not found: %v", err)
+	case apierrors.IsForbidden(err):
+		return fmt.Sprintf("Forbidden: %v", err)
+	default:
+		return err.Error()
+	}
+}
+
+// DescribeEvictionError is DescribeError for evictions, which the API server
+// refuses with 429 Too Many Requests while a PodDisruptionBudget blocks them.
+func DescribeEvictionError(err error) string {
+	if apierrors.IsTooManyRequests(err) {
+		return fmt.Sprintf("Blocked by a PodDisruptionBudget: %v", err)
+	}
+	return DescribeError(err)
+}
+
+// AccessDeniedMessage explains that the current user may not perform verb on
+// resource (or its subresource) in namespace, for example "forbidden: cannot
+// list pods in namespace default".
+func AccessDeniedMessage(verb string, resource APIResource, subresource string, namespace string) string {
+	name := resource.String()
+	if subresource != "" {
+		name += "/" + subresource
+	}
+	scope := "at the cluster scope"
+	if resource.Namespaced {
+		scope = "in all namespaces"
+		if namespace != "" {
+			scope = "in namespace " + namespace
+		}
+	}
+	return fmt.Sprintf("forbidden: cannot %s %s %s", verb, name, scope)
+}
+
+// DescribeAccessError returns AccessDeniedMessage for forbidden errors and the
+// error text otherwise.
+func DescribeAccessError(err error, verb string, resource APIResource, subresource string, namespace string) string {
+	if apierrors.IsForbidden(err) {
+		return AccessDeniedMessage(verb, resource, subresource, namespace)
+	}
+	return err.Error()
+}
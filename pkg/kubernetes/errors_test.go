@@ -0,0 +1,89 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestDescribeError(t *testing.T) {
+	podsResource := schema.GroupResource{Resource: "pods"}
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{"nil", nil, []string{""}},
+		{"conflict", apierrors.NewConflict(podsResource, "web", errors.New("stale")), []string{"Conflict: the object was modified"}},
+		{"invalid", apierrors.NewInvalid(schema.GroupKind{Kind: "Pod"}, "web", field.ErrorList{
+			field.Required(field.NewPath("spec", "containers"), "must have a container"),
+		}), []string{"Validation failed for web:", "- spec.containers: Required value: must have a container"}},
+		{"too many requests", apierrors.NewTooManyRequests("too many requests", 1), []string{"Throttled by the API server"}},
+		{"not found", apierrors.NewNotFound(podsResource, "web"), []string{"Not found:", `pods "web" not found`}},
+		{"forbidden", apierrors.NewForbidden(podsResource, "web", errors.New("denied")), []string{"Forbidden:"}},
+		{"other", errors.New("connection refused"), []string{"connection refused"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := DescribeError(test.err)
+			for _, want := range test.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("DescribeError = %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDescribeEvictionError(t *testing.T) {
+	if got := DescribeEvictionError(apierrors.NewTooManyRequests("disruption budget", 0)); !strings.HasPrefix(got, "Blocked by a PodDisruptionBudget") {
+		t.Errorf("DescribeEvictionError(429) = %q, want it blamed on a PodDisruptionBudget", got)
+	}
+	if got := DescribeEvictionError(apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "web")); !strings.HasPrefix(got, "Not found:") {
+		t.Errorf("DescribeEvictionError(404) = %q, want the DescribeError text", got)
+	}
+}
+
+func TestAccessDeniedMessage(t *testing.T) {
+	tests := []struct {
+		verb        string
+		resource    APIResource
+		subresource string
+		namespace   string
+		want        string
+	}{
+		{"list", PodResource, "", "default", "forbidden: cannot list pods in namespace default"},
+		{"list", PodResource, "", "", "forbidden: cannot list pods in all namespaces"},
+		{"get", PodResource, "log", "web", "forbidden: cannot get pods/log in namespace web"},
+		{"patch", NodeResource, "", "default", "forbidden: cannot patch nodes at the cluster scope"},
+		{"list", ReplicaSetResource, "", "default", "forbidden: cannot list replicasets.apps in namespace default"},
+	}
+
+	for _, test := range tests {
+		if got := AccessDeniedMessage(test.verb, test.resource, test.subresource, test.namespace); got != test.want {
+			t.Errorf("AccessDeniedMessage(%s, %s, %q, %q) = %q, want %q", test.verb, test.resource, test.subresource, test.namespace, got, test.want)
+		}
+	}
+}
+
+func TestDescribeAccessError(t *testing.T) {
+	err := apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("denied"))
+	if got, want := DescribeAccessError(err, "list", PodResource, "", "default"), "forbidden: cannot list pods in namespace default"; got != want {
+		t.Errorf("DescribeAccessError = %q, want %q", got, want)
+	}
+	if got := DescribeAccessError(errors.New("timeout"), "list", PodResource, "", "default"); got != "timeout" {
+		t.Errorf("DescribeAccessError = %q, want the error text", got)
+	}
+}
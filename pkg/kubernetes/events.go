@@ -0,0 +1,83 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// maxPodEvents is the number of most recent events shown in the pod details.
+const maxPodEvents = 10
+
+// detailsTimeFormat is how the pod details show absolute times.
+const detailsTimeFormat = "2006-01-02 15:04:05"
+
+// describePodEvents formats the most recent events of pod, like the Events
+// section of kubectl describe. Timestamps are absolute, so the section reads
+// the same in a replayed snapshot.
+func (c *Client) describePodEvents(pod *v1.Pod) string {
+	details := "[yellow::b]Events[-::-]\n"
+	selector := fields.Set{
+		"involvedObject.kind": "Pod",
+		"involvedObject.name": pod.Name,
+	}.AsSelector().String()
+	events, err := c.clientset.CoreV1().Events(pod.Namespace).List(context.TODO(), metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return details + fmt.Sprintf("  [gray]Unable to list events: %s[-]\n", DescribeError(err))
+	}
+
+	// Not every client honors field selectors, so filter again.
+	var matching []v1.Event
+	for _, event := range events.Items {
+		if event.InvolvedObject.Kind == "Pod" && event.InvolvedObject.Name == pod.Name {
+			matching = append(matching, event)
+		}
+	}
+	if len(matching) == 0 {
+		return details + "  [gray]No events[-]\n"
+	}
+
+	sort.Slice(matching, func(i, j int) bool {
+		return eventTime(matching[i]).Before(eventTime(matching[j]))
+	})
+	if len(matching) > maxPodEvents {
+		matching = matching[len(matching)-maxPodEvents:]
+	}
+	for _, event := range matching {
+		color := "green"
+		if event.Type == v1.EventTypeWarning {
+			color = "orange"
+		}
+		count := ""
+		if event.Count > 1 {
+			count = fmt.Sprintf(" (x%d)", event.Count)
+		}
+		details += fmt.Sprintf("  %s [%s]%s[-]%s: %s\n",
+			eventTime(event).Local().Format(detailsTimeFormat), color, event.Reason, count, event.Message)
+	}
+	return details
+}
+
+// eventTime returns when event last occurred.
+func eventTime(event v1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
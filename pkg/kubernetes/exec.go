@@ -0,0 +1,91 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// shellCommand starts bash if the image has it and falls back to sh.
+var shellCommand = []string{"/bin/sh", "-c", "command -v bash >/dev/null 2>&1 && exec bash || exec sh"}
+
+type (
+	TerminalSize      = remotecommand.TerminalSize
+	TerminalSizeQueue = remotecommand.TerminalSizeQueue
+)
+
+func (c *Client) GetPodContainers(pod Pod) ([]string, error) {
+	podObj, err := c.clientset.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []string
+	for _, container := range podObj.Spec.Containers {
+		containers = append(containers, container.Name)
+	}
+	return containers, nil
+}
+
+// ExecShell runs an interactive shell with a TTY in container and streams it
+// to stdin and stdout until the shell exits. The WebSocket protocol is tried
+// first, falling back to SPDY for API servers that do not support it.
+func (c *Client) ExecShell(pod Pod, container string, stdin io.Reader, stdout io.Writer, sizeQueue TerminalSizeQueue) error {
+	if c.config == nil {
+		return fmt.Errorf("exec is not supported by this client")
+	}
+
+	req := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: container,
+			Command:   shellCommand,
+			Stdin:     true,
+			Stdout:    true,
+			TTY:       true,
+		}, scheme.ParameterCodec)
+
+	websocketExecutor, err := remotecommand.NewWebSocketExecutor(c.config, "GET", req.URL().String())
+	if err != nil {
+		return fmt.Errorf("failed to create WebSocket executor: %v", err)
+	}
+	spdyExecutor, err := remotecommand.NewSPDYExecutor(c.config, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create SPDY executor: %v", err)
+	}
+	executor, err := remotecommand.NewFallbackExecutor(websocketExecutor, spdyExecutor, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create executor: %v", err)
+	}
+
+	end, err := c.begin("exec", fmt.Sprintf("%s (container %s)", podObjectRef(pod), container))
+	if err != nil {
+		return err
+	}
+	err = executor.StreamWithContext(context.TODO(), remotecommand.StreamOptions{
+		Stdin:             stdin,
+		Stdout:            stdout,
+		Tty:               true,
+		TerminalSizeQueue: sizeQueue,
+	})
+	end(err)
+	return err
+}
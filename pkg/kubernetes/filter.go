@@ -0,0 +1,254 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// fieldKeys are, by resource name, the keys of the fields the API server
+// supports in field selectors of pods and nodes. Other keys are labels, which
+// may look alike, such as status.example.com/tier.
+var fieldKeys = map[string]map[string]bool{
+	PodResource.Name:  keysOf(PodFieldSet(&v1.Pod{})),
+	NodeResource.Name: keysOf(nodeFields(&v1.Node{})),
+}
+
+func keysOf(fields map[string]string) map[string]bool {
+	keys := make(map[string]bool, len(fields))
+	for key := range fields {
+		keys[key] = true
+	}
+	return keys
+}
+
+// Filter narrows a list of pods or nodes. Labels and Fields are sent to the
+// API server as selectors, while names are matched fuzzily by the client.
+type Filter struct {
+	// Labels is a label selector such as "app=web,tier!=db".
+	Labels string
+	// Fields is a field selector such as "status.phase!=Running".
+	Fields string
+	// Names are patterns a name must match, and ExcludedNames patterns it
+	// must not match, as in FuzzyMatch.
+	Names         []string
+	ExcludedNames []string
+
+	// labelSelector and fieldSelector are parsed from Labels and Fields by
+	// ParseFilter, so Matches does not parse them for every row. They are nil
+	// in filters built otherwise.
+	labelSelector labels.Selector
+	fieldSelector fields.Selector
+}
+
+// ParseFilter parses terms separated by spaces into a filter of resource,
+// pods or nodes. The terms must all match:
+//
+//	web                    the name fuzzily matches web
+//	app=web, app!=web      label selectors
+//	status.phase!=Running  field selectors, for the fields the API server
+//	                       supports for resource, such as spec.nodeName and
+//	                       status.phase of pods or spec.unschedulable of nodes
+//	!term                  negates a term
+func ParseFilter(text string, resource APIResource) (Filter, error) {
+	supported, ok := fieldKeys[resource.Name]
+	if !ok || resource.Group != "" {
+		return Filter{}, fmt.Errorf("filters are only supported for pods and nodes, not %s", resource.Name)
+	}
+
+	var filter Filter
+	var labelTerms, fieldTerms []string
+	for _, term := range strings.Fields(text) {
+		negated := strings.HasPrefix(term, "!")
+		body := strings.TrimPrefix(term, "!")
+		if body == "" {
+			return Filter{}, fmt.Errorf("%q negates nothing", term)
+		}
+
+		key, value, operator := splitRequirement(body)
+		if operator == "" {
+			if negated {
+				filter.ExcludedNames = append(filter.ExcludedNames, body)
+			} else {
+				filter.Names = append(filter.Names, body)
+			}
+			continue
+		}
+		if key == "" {
+			return Filter{}, fmt.Errorf("%q has no key", term)
+		}
+		if negated {
+			if operator == "=" {
+				operator = "!="
+			} else {
+				operator = "="
+			}
+		}
+		requirement := key + operator + value
+		if supported[key] {
+			fieldTerms = append(fieldTerms, requirement)
+		} else {
+			labelTerms = append(labelTerms, requirement)
+		}
+	}
+
+	filter.Labels = strings.Join(labelTerms, ",")
+	filter.Fields = strings.Join(fieldTerms, ",")
+	var err error
+	if filter.labelSelector, filter.fieldSelector, err = filter.selectors(); err != nil {
+		return Filter{}, err
+	}
+	return filter, nil
+}
+
+// splitRequirement splits "key=value", "key==value" or "key!=value" into
+// its key, value and operator, "=" or "!=". The operator is empty when term
+// is a plain name.
+func splitRequirement(term string) (string, string, string) {
+	if key, value, ok := strings.Cut(term, "!="); ok {
+		return key, value, "!="
+	}
+	if key, value, ok := strings.Cut(term, "=="); ok {
+		return key, value, "="
+	}
+	if key, value, ok := strings.Cut(term, "="); ok {
+		return key, value, "="
+	}
+	return "", "", ""
+}
+
+// IsEmpty reports whether the filter lets everything through.
+func (f Filter) IsEmpty() bool {
+	return f.Labels == "" && f.Fields == "" && len(f.Names) == 0 && len(f.ExcludedNames) == 0
+}
+
+// OnNode narrows the filter to the pods scheduled on nodeName.
+func (f Filter) OnNode(nodeName string) Filter {
+	term := "spec.nodeName=" + nodeName
+	if f.Fields != "" {
+		term = f.Fields + "," + term
+	}
+	f.Fields = term
+	if f.fieldSelector != nil {
+		f.fieldSelector = fields.AndSelectors(f.fieldSelector, fields.OneTermEqualSelector("spec.nodeName", nodeName))
+	}
+	return f
+}
+
+// selectors parses the label and field selectors of the filter.
+func (f Filter) selectors() (labels.Selector, fields.Selector, error) {
+	labelSelector, err := labels.Parse(f.Labels)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid label selector %q: %v", f.Labels, err)
+	}
+	fieldSelector, err := fields.ParseSelector(f.Fields)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid field selector %q: %v", f.Fields, err)
+	}
+	return labelSelector, fieldSelector, nil
+}
+
+// Matches reports whether an object with name, labels and fields passes the
+// filter. The API server applies the selectors already, but not every client
+// does, so they are checked again.
+func (f Filter) Matches(name string, objectLabels map[string]string, objectFields map[string]string) bool {
+	labelSelector, fieldSelector := f.labelSelector, f.fieldSelector
+	if labelSelector == nil || fieldSelector == nil {
+		var err error
+		if labelSelector, fieldSelector, err = f.selectors(); err != nil {
+			return false
+		}
+	}
+	if !labelSelector.Matches(labels.Set(objectLabels)) || !fieldSelector.Matches(fields.Set(objectFields)) {
+		return false
+	}
+	for _, pattern := range f.Names {
+		if !FuzzyMatch(pattern, name) {
+			return false
+		}
+	}
+	for _, pattern := range f.ExcludedNames {
+		if FuzzyMatch(pattern, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// FuzzyMatch reports whether the characters of pattern appear in name in the
+// same order, ignoring case, so "wb7" matches "web-7d4b9".
+func FuzzyMatch(pattern string, name string) bool {
+	remaining := []rune(strings.ToLower(pattern))
+	for _, r := range strings.ToLower(name) {
+		if len(remaining) == 0 {
+			break
+		}
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
+
+// PodFieldSet returns the fields of pod the API server supports in field
+// selectors, for fake clientsets to apply the selectors they ignore.
+func PodFieldSet(pod *v1.Pod) fields.Set {
+	return fields.Set{
+		"metadata.name":            pod.Name,
+		"metadata.namespace":       pod.Namespace,
+		"spec.nodeName":            pod.Spec.NodeName,
+		"spec.restartPolicy":       string(pod.Spec.RestartPolicy),
+		"spec.schedulerName":       pod.Spec.SchedulerName,
+		"spec.serviceAccountName":  pod.Spec.ServiceAccountName,
+		"spec.hostNetwork":         strconv.FormatBool(pod.Spec.HostNetwork),
+		"status.phase":             string(pod.Status.Phase),
+		"status.podIP":             pod.Status.PodIP,
+		"status.nominatedNodeName": pod.Status.NominatedNodeName,
+	}
+}
+
+// ListPodsReactor lists the pods of tracker for a fake clientset, applying the
+// label and field selectors the fake clientset ignores.
+func ListPodsReactor(tracker k8stesting.ObjectTracker) k8stesting.ReactionFunc {
+	podsResource := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	podsKind := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		restrictions := action.(k8stesting.ListAction).GetListRestrictions()
+		object, err := tracker.List(podsResource, podsKind, action.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+
+		list := object.(*v1.PodList)
+		filtered := &v1.PodList{ListMeta: list.ListMeta}
+		for _, item := range list.Items {
+			if restrictions.Labels.Matches(labels.Set(item.Labels)) && restrictions.Fields.Matches(PodFieldSet(&item)) {
+				filtered.Items = append(filtered.Items, item)
+			}
+		}
+		return true, filtered, nil
+	}
+}
+
+// nodeFields are the fields of node the API server supports in field
+// selectors.
+func nodeFields(node *v1.Node) map[string]string {
+	return map[string]string{
+		"metadata.name":      node.Name,
+		"spec.unschedulable": strconv.FormatBool(node.Spec.Unschedulable),
+	}
+}
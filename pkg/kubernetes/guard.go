@@ -0,0 +1,123 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/audit"
+	"github.com/rdmnl/kubepulse/utils"
+)
+
+// ErrReadOnly is returned by every operation that would change the cluster
+// while the client is read-only.
+var ErrReadOnly = errors.New("kubepulse is in read-only mode, changes to the cluster are disabled")
+
+func (c *Client) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
+
+func (c *Client) ReadOnly() bool {
+	return c.readOnly
+}
+
+// SetIdentity sets the context and user name shown in the header and recorded
+// in audit entries, for clients not created from a kubeconfig.
+func (c *Client) SetIdentity(contextName string, userName string) {
+	c.contextName = contextName
+	c.userName = userName
+}
+
+func (c *Client) ContextName() string {
+	return c.contextName
+}
+
+func (c *Client) UserName() string {
+	return c.userName
+}
+
+// SetAuditLog records every change made through the client in auditLog.
+func (c *Client) SetAuditLog(auditLog *audit.Log) {
+	c.auditLog = auditLog
+}
+
+// AuditEntries returns up to limit of the most recent audit entries, newest
+// first.
+func (c *Client) AuditEntries(limit int) ([]audit.Entry, error) {
+	if c.auditLog == nil {
+		return nil, fmt.Errorf("audit log is disabled")
+	}
+	return c.auditLog.Entries(limit)
+}
+
+// write runs fn, which performs verb on resource, unless the client is
+// read-only, and records the outcome in the audit log. Every write path of the
+// client goes through it.
+func (c *Client) write(verb string, resource string, fn func() error) error {
+	if c.readOnly {
+		c.record(verb, resource, audit.ResultDenied, ErrReadOnly)
+		return ErrReadOnly
+	}
+	err := fn()
+	c.record(verb, resource, outcome(err), err)
+	return err
+}
+
+// begin records that verb on resource started and returns a function that
+// records how it ended. Long sessions such as exec use it instead of write,
+// so they are audited even when kubepulse does not live to see them end.
+func (c *Client) begin(verb string, resource string) (func(err error), error) {
+	if c.readOnly {
+		c.record(verb, resource, audit.ResultDenied, ErrReadOnly)
+		return nil, ErrReadOnly
+	}
+	c.record(verb, resource, audit.ResultStarted, nil)
+	return func(err error) {
+		c.record(verb, resource, outcome(err), err)
+	}, nil
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return audit.ResultFailure
+	}
+	return audit.ResultSuccess
+}
+
+// record appends an entry for verb on resource to the audit log, if there is
+// one.
+func (c *Client) record(verb string, resource string, result string, err error) {
+	if c.auditLog == nil {
+		return
+	}
+	entry := audit.Entry{
+		Timestamp: time.Now(),
+		User:      c.userName,
+		Context:   c.contextName,
+		Verb:      verb,
+		Resource:  resource,
+		Result:    result,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if auditErr := c.auditLog.Append(entry); auditErr != nil {
+		utils.Error(fmt.Sprintf("Error writing audit entry for %s %s: %v", verb, resource, auditErr))
+	}
+}
+
+// checkWritable fails with ErrReadOnly for operations that need write access
+// without changing anything, such as server-side dry-runs.
+func (c *Client) checkWritable() error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
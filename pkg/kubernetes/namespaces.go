@@ -0,0 +1,211 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// warningWindow is how far back warning events are counted in the namespace
+// and cluster summaries.
+const warningWindow = time.Hour
+
+// PodCounts counts pods by phase.
+type PodCounts struct {
+	Running   int
+	Pending   int
+	Succeeded int
+	Failed    int
+	Unknown   int
+}
+
+// Add counts a pod in phase.
+func (counts *PodCounts) Add(phase v1.PodPhase) {
+	switch phase {
+	case v1.PodRunning:
+		counts.Running++
+	case v1.PodPending:
+		counts.Pending++
+	case v1.PodSucceeded:
+		counts.Succeeded++
+	case v1.PodFailed:
+		counts.Failed++
+	default:
+		counts.Unknown++
+	}
+}
+
+// Pods returns the number of pods counted.
+func (counts PodCounts) Pods() int {
+	return counts.Running + counts.Pending + counts.Succeeded + counts.Failed + counts.Unknown
+}
+
+// NamespaceSummary is the overview of the pods, resources and recent problems
+// of a namespace.
+type NamespaceSummary struct {
+	Name string
+	PodCounts
+	// Restarts sums the container restarts of the pods.
+	Restarts int32
+	// Usage is only set when UsageAvailable, since metrics may not be
+	// available.
+	Usage          Resources
+	UsageAvailable bool
+	// Requests sums the effective requests of the pods that have not
+	// completed, the way ResourceQuotas count them.
+	Requests Resources
+	// Quota is the most restrictive ResourceQuota on requests, zero when
+	// there is none.
+	Quota Resources
+	// Warnings counts the warning events of the last hour.
+	Warnings int
+}
+
+// ListNamespaceSummaries summarizes every namespace. Usage, quotas and
+// warnings are left out when they cannot be listed, so a missing metrics-server
+// or permission does not hide the rest. Without permission to list pods in
+// every namespace, only the current namespace is summarized.
+func (c *Client) ListNamespaceSummaries() ([]NamespaceSummary, error) {
+	var pods *v1.PodList
+	scope := ""
+	err := c.listAcrossNamespaces(func(namespace string) (err error) {
+		scope = namespace
+		pods, err = c.clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*NamespaceSummary)
+	summary := func(name string) *NamespaceSummary {
+		if byName[name] == nil {
+			byName[name] = &NamespaceSummary{Name: name}
+		}
+		return byName[name]
+	}
+	if scope != "" {
+		summary(scope)
+	} else {
+		// Namespaces without pods are only known with permission to list
+		// namespaces.
+		namespaces, err := c.clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+		if err != nil && !apierrors.IsForbidden(err) {
+			return nil, err
+		}
+		if err == nil {
+			for _, namespace := range namespaces.Items {
+				summary(namespace.Name)
+			}
+		}
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		s := summary(pod.Namespace)
+		s.Add(pod.Status.Phase)
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			s.Restarts += status.RestartCount
+		}
+		if !isPodCompleted(pod) {
+			cpu, memory := podRequests(pod)
+			s.Requests.CPU += cpu
+			s.Requests.Memory += memory
+		}
+	}
+
+	podMetrics, err := c.metricsClient.MetricsV1beta1().PodMetricses(scope).List(context.TODO(), metav1.ListOptions{})
+	usageAvailable := err == nil
+	if usageAvailable {
+		for _, item := range podMetrics.Items {
+			s := summary(item.Namespace)
+			for _, container := range item.Containers {
+				usage := toUsage(item.Timestamp.Time, container.Usage)
+				s.Usage.CPU += usage.CPU
+				s.Usage.Memory += usage.Memory
+			}
+		}
+	}
+
+	if quotas, err := c.clientset.CoreV1().ResourceQuotas(scope).List(context.TODO(), metav1.ListOptions{}); err == nil {
+		for _, quota := range quotas.Items {
+			hard := quota.Status.Hard
+			if len(hard) == 0 {
+				hard = quota.Spec.Hard
+			}
+			s := summary(quota.Namespace)
+			s.Quota.CPU = tighterQuota(s.Quota.CPU, quotaLimit(hard, v1.ResourceRequestsCPU, v1.ResourceCPU, true))
+			s.Quota.Memory = tighterQuota(s.Quota.Memory, quotaLimit(hard, v1.ResourceRequestsMemory, v1.ResourceMemory, false))
+		}
+	}
+
+	if warnings, err := c.recentWarnings(); err == nil {
+		for namespace, count := range warnings {
+			if scope == "" || namespace == scope {
+				summary(namespace).Warnings = count
+			}
+		}
+	}
+
+	summaries := make([]NamespaceSummary, 0, len(byName))
+	for _, s := range byName {
+		s.UsageAvailable = usageAvailable
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries, nil
+}
+
+// recentWarnings counts the warning events of the last warningWindow by
+// namespace.
+func (c *Client) recentWarnings() (map[string]int, error) {
+	selector := fields.OneTermEqualSelector("type", v1.EventTypeWarning).String()
+	events, err := c.clientset.CoreV1().Events("").List(context.TODO(), metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	since := time.Now().Add(-warningWindow)
+	warnings := make(map[string]int)
+	for _, event := range events.Items {
+		// Not every client honors field selectors, so filter again.
+		if event.Type == v1.EventTypeWarning && eventTime(event).After(since) {
+			warnings[event.Namespace]++
+		}
+	}
+	return warnings, nil
+}
+
+// quotaLimit returns the hard limit on requests of a resource, in millicores
+// for CPU and bytes otherwise, or zero when there is none.
+func quotaLimit(hard v1.ResourceList, requests v1.ResourceName, plain v1.ResourceName, milli bool) int64 {
+	quantity, ok := hard[requests]
+	if !ok {
+		if quantity, ok = hard[plain]; !ok {
+			return 0
+		}
+	}
+	if milli {
+		return quantity.MilliValue()
+	}
+	return quantity.Value()
+}
+
+// tighterQuota returns the smaller of two quota limits, zero meaning none.
+func tighterQuota(current int64, limit int64) int64 {
+	if current == 0 || (limit > 0 && limit < current) {
+		return limit
+	}
+	return current
+}
@@ -0,0 +1,43 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeletePod deletes pod with its configured grace period, or immediately with a
+// grace period of 0 when force is set.
+func (c *Client) DeletePod(pod Pod, force bool) error {
+	verb := "delete"
+	options := metav1.DeleteOptions{}
+	if force {
+		verb = "force delete"
+		gracePeriod := int64(0)
+		options.GracePeriodSeconds = &gracePeriod
+	}
+	return c.write(verb, podObjectRef(pod).String(), func() error {
+		return c.clientset.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, options)
+	})
+}
+
+// EvictPod evicts pod through the Eviction API, which refuses with
+// 429 Too Many Requests if a PodDisruptionBudget would be violated.
+func (c *Client) EvictPod(pod Pod) error {
+	return c.write("evict", podObjectRef(pod).String(), func() error {
+		return c.clientset.PolicyV1().Evictions(pod.Namespace).Evict(context.TODO(), &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+			},
+		})
+	})
+}
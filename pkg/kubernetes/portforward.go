@@ -0,0 +1,583 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rdmnl/kubepulse/utils"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+const (
+	PortForwardStarting     = "Starting"
+	PortForwardActive       = "Active"
+	PortForwardReconnecting = "Reconnecting"
+	PortForwardStopped      = "Stopped"
+
+	portForwardRetryInterval = 2 * time.Second
+	portForwardPodCheck      = 5 * time.Second
+	// portForwardConnectWait is how long a connection waits for the tunnel to
+	// come back while reconnecting.
+	portForwardConnectWait = 30 * time.Second
+)
+
+// Labels added by controllers to tell revisions apart. They are ignored when
+// looking for a replacement pod, which belongs to a newer revision.
+var revisionLabels = []string{"pod-template-hash", "controller-revision-hash", "statefulset.kubernetes.io/pod-name"}
+
+// PortForwardTarget is a pod or service to forward a local port to.
+type PortForwardTarget struct {
+	Service    bool
+	Namespace  string
+	Name       string
+	RemotePort int
+}
+
+// PortForward is a snapshot of a running port-forward.
+type PortForward struct {
+	ID        int
+	LocalPort int
+	Target    PortForwardTarget
+	Pod       string
+	BytesIn   int64
+	BytesOut  int64
+	Status    string
+	Error     string
+}
+
+func (t PortForwardTarget) String() string {
+	kind := "pod"
+	if t.Service {
+		kind = "svc"
+	}
+	return fmt.Sprintf("%s/%s/%s:%d", t.Namespace, kind, t.Name, t.RemotePort)
+}
+
+// tunnel forwards an ephemeral local port to a pod until its stop channel is
+// closed.
+type tunnel interface {
+	ForwardPorts() error
+	GetPorts() ([]portforward.ForwardedPort, error)
+}
+
+// openTunnel creates a tunnel to remotePort of pod. Tests replace it.
+var openTunnel = func(c *Client, pod *v1.Pod, remotePort int, stop chan struct{}, ready chan struct{}) (tunnel, error) {
+	dialer, err := c.portForwardDialer(pod)
+	if err != nil {
+		return nil, err
+	}
+	return portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", remotePort)}, stop, ready, io.Discard, io.Discard)
+}
+
+type portForwardManager struct {
+	mu       sync.Mutex
+	nextID   int
+	forwards map[int]*portForwardSession
+}
+
+// portForwardSession owns the local listener. Connections are proxied to a
+// client-go port-forward on an ephemeral port, which is re-established against
+// a new pod whenever the backing pod goes away.
+type portForwardSession struct {
+	client   *Client
+	id       int
+	target   PortForwardTarget
+	selector labels.Selector
+	listener net.Listener
+	stop     chan struct{}
+	bytesIn  atomic.Int64
+	bytesOut atomic.Int64
+
+	mu         sync.Mutex
+	pod        string
+	tunnelPort int
+	status     string
+	lastError  string
+	// activated is closed while the tunnel is active.
+	activated chan struct{}
+}
+
+func (c *Client) StartPortForward(target PortForwardTarget, localPort int) (PortForward, error) {
+	if c.config == nil {
+		return PortForward{}, fmt.Errorf("port-forwarding is not supported by this client")
+	}
+
+	selector, err := c.portForwardSelector(target)
+	if err != nil {
+		return PortForward{}, err
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort)))
+	if err != nil {
+		return PortForward{}, fmt.Errorf("unable to listen on local port %d: %v", localPort, err)
+	}
+
+	c.portForwards.mu.Lock()
+	if c.portForwards.forwards == nil {
+		c.portForwards.forwards = make(map[int]*portForwardSession)
+	}
+	c.portForwards.nextID++
+	session := &portForwardSession{
+		client:    c,
+		id:        c.portForwards.nextID,
+		target:    target,
+		selector:  selector,
+		listener:  listener,
+		stop:      make(chan struct{}),
+		status:    PortForwardStarting,
+		activated: make(chan struct{}),
+	}
+	c.portForwards.forwards[session.id] = session
+	c.portForwards.mu.Unlock()
+
+	go session.maintainTunnel()
+	go session.acceptConnections()
+
+	utils.Info(fmt.Sprintf("Started port-forward %d: 127.0.0.1:%d -> %s", session.id, session.localPort(), target))
+	return session.snapshot(), nil
+}
+
+// ListTargetPorts returns the ports declared by a pod's containers or a service.
+func (c *Client) ListTargetPorts(target PortForwardTarget) ([]int, error) {
+	var ports []int
+	if target.Service {
+		service, err := c.clientset.CoreV1().Services(target.Namespace).Get(context.TODO(), target.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, port := range service.Spec.Ports {
+			ports = append(ports, int(port.Port))
+		}
+		return ports, nil
+	}
+
+	pod, err := c.clientset.CoreV1().Pods(target.Namespace).Get(context.TODO(), target.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			ports = append(ports, int(port.ContainerPort))
+		}
+	}
+	return ports, nil
+}
+
+func (c *Client) ListPortForwards() []PortForward {
+	c.portForwards.mu.Lock()
+	defer c.portForwards.mu.Unlock()
+
+	var forwards []PortForward
+	for _, session := range c.portForwards.forwards {
+		forwards = append(forwards, session.snapshot())
+	}
+	sort.Slice(forwards, func(i, j int) bool {
+		return forwards[i].ID < forwards[j].ID
+	})
+	return forwards
+}
+
+func (c *Client) StopPortForward(id int) error {
+	c.portForwards.mu.Lock()
+	session, ok := c.portForwards.forwards[id]
+	delete(c.portForwards.forwards, id)
+	c.portForwards.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("port-forward %d not found", id)
+	}
+
+	close(session.stop)
+	session.listener.Close()
+	session.setStatus(PortForwardStopped, "")
+	utils.Info(fmt.Sprintf("Stopped port-forward %d to %s", id, session.target))
+	return nil
+}
+
+// portForwardSelector returns the labels used to find a pod for target: the
+// service selector, or the labels of the pod without its revision labels.
+func (c *Client) portForwardSelector(target PortForwardTarget) (labels.Selector, error) {
+	if target.Service {
+		service, err := c.clientset.CoreV1().Services(target.Namespace).Get(context.TODO(), target.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if len(service.Spec.Selector) == 0 {
+			return nil, fmt.Errorf("service %s/%s has no selector", target.Namespace, target.Name)
+		}
+		return labels.SelectorFromSet(service.Spec.Selector), nil
+	}
+
+	pod, err := c.clientset.CoreV1().Pods(target.Namespace).Get(context.TODO(), target.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	podLabels := labels.Set{}
+	for key, value := range pod.Labels {
+		podLabels[key] = value
+	}
+	for _, key := range revisionLabels {
+		delete(podLabels, key)
+	}
+	if len(podLabels) == 0 || len(pod.OwnerReferences) == 0 {
+		// A bare pod cannot be replaced, so only ever forward to it.
+		return nil, nil
+	}
+	return labels.SelectorFromSet(podLabels), nil
+}
+
+func (session *portForwardSession) maintainTunnel() {
+	for {
+		err := session.runTunnel()
+		select {
+		case <-session.stop:
+			return
+		default:
+		}
+
+		message := ""
+		if err != nil {
+			message = err.Error()
+			utils.Warn(fmt.Sprintf("Port-forward %d to %s interrupted: %v", session.id, session.target, err))
+		}
+		session.setStatus(PortForwardReconnecting, message)
+
+		select {
+		case <-session.stop:
+			return
+		case <-time.After(portForwardRetryInterval):
+		}
+	}
+}
+
+// runTunnel forwards to one backing pod and returns when the connection to it
+// is lost, the pod is gone, or the session is stopped.
+func (session *portForwardSession) runTunnel() error {
+	pod, err := session.resolvePod()
+	if err != nil {
+		return err
+	}
+	remotePort, err := session.resolvePort(pod)
+	if err != nil {
+		return err
+	}
+
+	tunnelStop := make(chan struct{})
+	ready := make(chan struct{})
+	forwarder, err := openTunnel(session.client, pod, remotePort, tunnelStop, ready)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- forwarder.ForwardPorts()
+	}()
+	// closeTunnel stops the forwarder and waits for it to release its
+	// listener.
+	closeTunnel := func() {
+		close(tunnelStop)
+		<-done
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-session.stop:
+		closeTunnel()
+		return nil
+	case <-ready:
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		closeTunnel()
+		return fmt.Errorf("port-forward to %s did not report a local port", pod.Name)
+	}
+	session.activate(pod.Name, int(ports[0].Local))
+
+	ticker := time.NewTicker(portForwardPodCheck)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-session.stop:
+			closeTunnel()
+			return nil
+		case <-ticker.C:
+			if !session.podAlive(pod.Name) {
+				closeTunnel()
+				return fmt.Errorf("pod %s is gone", pod.Name)
+			}
+		}
+	}
+}
+
+func (session *portForwardSession) resolvePod() (*v1.Pod, error) {
+	pods := session.client.clientset.CoreV1().Pods(session.target.Namespace)
+
+	if session.selector == nil {
+		pod, err := pods.Get(context.TODO(), session.target.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if pod.Status.Phase != v1.PodRunning {
+			return nil, fmt.Errorf("pod %s is %s", pod.Name, pod.Status.Phase)
+		}
+		return pod, nil
+	}
+
+	// Prefer the original pod while it is still running.
+	if !session.target.Service {
+		if pod, err := pods.Get(context.TODO(), session.target.Name, metav1.GetOptions{}); err == nil && isPodReady(pod) {
+			return pod, nil
+		}
+	}
+
+	list, err := pods.List(context.TODO(), metav1.ListOptions{LabelSelector: session.selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		if isPodReady(&list.Items[i]) {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no ready pod matches %s", session.selector)
+}
+
+// resolvePort maps a service port to the target port of the pod, resolving
+// named ports against the pod's container ports.
+func (session *portForwardSession) resolvePort(pod *v1.Pod) (int, error) {
+	if !session.target.Service {
+		return session.target.RemotePort, nil
+	}
+
+	service, err := session.client.clientset.CoreV1().Services(session.target.Namespace).Get(context.TODO(), session.target.Name, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+	for _, port := range service.Spec.Ports {
+		if int(port.Port) != session.target.RemotePort {
+			continue
+		}
+		if port.TargetPort.Type == intstr.Int {
+			if port.TargetPort.IntVal == 0 {
+				return int(port.Port), nil
+			}
+			return int(port.TargetPort.IntVal), nil
+		}
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == port.TargetPort.StrVal {
+					return int(containerPort.ContainerPort), nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("pod %s has no port named %s", pod.Name, port.TargetPort.StrVal)
+	}
+	return 0, fmt.Errorf("service %s/%s has no port %d", session.target.Namespace, session.target.Name, session.target.RemotePort)
+}
+
+func (session *portForwardSession) podAlive(name string) bool {
+	pod, err := session.client.clientset.CoreV1().Pods(session.target.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false
+	}
+	if err != nil {
+		// Transient API errors should not tear down a working tunnel.
+		return true
+	}
+	return pod.DeletionTimestamp == nil && pod.Status.Phase == v1.PodRunning
+}
+
+func (session *portForwardSession) acceptConnections() {
+	for {
+		conn, err := session.listener.Accept()
+		if err != nil {
+			return
+		}
+		go session.proxy(conn)
+	}
+}
+
+func (session *portForwardSession) proxy(conn net.Conn) {
+	defer conn.Close()
+
+	tunnelPort, ok := session.waitForTunnel()
+	if !ok {
+		return
+	}
+
+	upstream, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(tunnelPort)))
+	if err != nil {
+		utils.Warn(fmt.Sprintf("Port-forward %d: unable to reach tunnel: %v", session.id, err))
+		return
+	}
+	defer upstream.Close()
+
+	// Each direction is closed on its own once it ends, so a client that
+	// stops sending after its request still gets the response.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	copyHalf := func(dst net.Conn, src net.Conn, count *atomic.Int64) {
+		defer wg.Done()
+		if _, err := io.Copy(&countingWriter{writer: dst, count: count}, src); err != nil {
+			conn.Close()
+			upstream.Close()
+			return
+		}
+		closeWrite(dst)
+	}
+	go copyHalf(upstream, conn, &session.bytesOut)
+	go copyHalf(conn, upstream, &session.bytesIn)
+	wg.Wait()
+}
+
+// closeWrite shuts down the sending side of conn, or closes it when it cannot
+// be half-closed.
+func closeWrite(conn net.Conn) {
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.CloseWrite()
+		return
+	}
+	conn.Close()
+}
+
+func (session *portForwardSession) localPort() int {
+	return session.listener.Addr().(*net.TCPAddr).Port
+}
+
+// waitForTunnel returns the local port of the tunnel once it is active, or
+// false when the session stops or the tunnel does not come back in time.
+func (session *portForwardSession) waitForTunnel() (int, bool) {
+	timeout := time.NewTimer(portForwardConnectWait)
+	defer timeout.Stop()
+	for {
+		session.mu.Lock()
+		if session.status == PortForwardActive {
+			tunnelPort := session.tunnelPort
+			session.mu.Unlock()
+			return tunnelPort, true
+		}
+		activated := session.activated
+		session.mu.Unlock()
+
+		select {
+		case <-activated:
+		case <-session.stop:
+			return 0, false
+		case <-timeout.C:
+			utils.Warn(fmt.Sprintf("Port-forward %d: dropping a connection, the tunnel did not come back", session.id))
+			return 0, false
+		}
+	}
+}
+
+// activate marks the tunnel to pod on tunnelPort active and releases the
+// connections waiting for it.
+func (session *portForwardSession) activate(pod string, tunnelPort int) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.pod = pod
+	session.tunnelPort = tunnelPort
+	if session.status != PortForwardActive {
+		close(session.activated)
+	}
+	session.status = PortForwardActive
+	session.lastError = ""
+}
+
+func (session *portForwardSession) setStatus(status string, message string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.status == PortForwardActive && status != PortForwardActive {
+		session.activated = make(chan struct{})
+	}
+	session.status = status
+	session.lastError = message
+}
+
+func (session *portForwardSession) snapshot() PortForward {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return PortForward{
+		ID:        session.id,
+		LocalPort: session.localPort(),
+		Target:    session.target,
+		Pod:       session.pod,
+		BytesIn:   session.bytesIn.Load(),
+		BytesOut:  session.bytesOut.Load(),
+		Status:    session.status,
+		Error:     session.lastError,
+	}
+}
+
+func (c *Client) portForwardDialer(pod *v1.Pod) (httpstream.Dialer, error) {
+	url := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+
+	transport, upgrader, err := spdy.RoundTripperFor(c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SPDY round tripper: %v", err)
+	}
+	spdyDialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	websocketDialer, err := portforward.NewSPDYOverWebsocketDialer(url, c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create WebSocket dialer: %v", err)
+	}
+	return portforward.NewFallbackDialer(websocketDialer, spdyDialer, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	}), nil
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+type countingWriter struct {
+	writer io.Writer
+	count  *atomic.Int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.count.Add(int64(n))
+	return n, err
+}
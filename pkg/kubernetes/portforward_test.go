@@ -0,0 +1,198 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+)
+
+// echoTunnel stands in for a port-forward with a local echo server. Once a
+// client stops sending, the server writes the farewell and closes.
+type echoTunnel struct {
+	stop     chan struct{}
+	ready    chan struct{}
+	farewell string
+	listener net.Listener
+	returned chan struct{}
+}
+
+func (e *echoTunnel) ForwardPorts() error {
+	defer close(e.returned)
+	go func() {
+		for {
+			conn, err := e.listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if _, err := io.Copy(conn, conn); err == nil {
+					io.WriteString(conn, e.farewell)
+				}
+			}()
+		}
+	}()
+	close(e.ready)
+	<-e.stop
+	return e.listener.Close()
+}
+
+func (e *echoTunnel) GetPorts() ([]portforward.ForwardedPort, error) {
+	return []portforward.ForwardedPort{{Local: uint16(e.listener.Addr().(*net.TCPAddr).Port)}}, nil
+}
+
+// useEchoTunnels replaces the port-forwards of the test with echo servers
+// saying farewell and returns the tunnels as they are opened.
+func useEchoTunnels(t *testing.T, farewell string) <-chan *echoTunnel {
+	t.Helper()
+	tunnels := make(chan *echoTunnel, 10)
+	previous := openTunnel
+	openTunnel = func(c *Client, pod *v1.Pod, remotePort int, stop chan struct{}, ready chan struct{}) (tunnel, error) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, err
+		}
+		echo := &echoTunnel{stop: stop, ready: ready, farewell: farewell, listener: listener, returned: make(chan struct{})}
+		tunnels <- echo
+		return echo, nil
+	}
+	t.Cleanup(func() { openTunnel = previous })
+	return tunnels
+}
+
+func roundTrip(t *testing.T, port int, message string) {
+	t.Helper()
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		t.Fatalf("dialing the port-forward: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte(message)); err != nil {
+		t.Fatalf("writing to the port-forward: %v", err)
+	}
+	reply := make([]byte, len(message))
+	if _, err := io.ReadFull(conn, reply); err != nil || string(reply) != message {
+		t.Fatalf("reply through the port-forward = %q, %v, want %q", reply, err, message)
+	}
+}
+
+func TestPortForwardStopsItsTunnel(t *testing.T) {
+	tunnels := useEchoTunnels(t, "")
+	pod := testPod("default", "web", "node-a")
+	pod.Status.Phase = v1.PodRunning
+	client, _, _ := newTestClient(t, pod)
+	client.config = &rest.Config{}
+
+	forward, err := client.StartPortForward(PortForwardTarget{Namespace: "default", Name: "web", RemotePort: 8080}, 0)
+	if err != nil {
+		t.Fatalf("StartPortForward: %v", err)
+	}
+	echo := <-tunnels
+
+	roundTrip(t, forward.LocalPort, "ping")
+	if forwards := client.ListPortForwards(); len(forwards) != 1 || forwards[0].Status != PortForwardActive || forwards[0].Pod != "web" || forwards[0].BytesIn != 4 || forwards[0].BytesOut != 4 {
+		t.Errorf("port-forwards = %+v, want an active one to web with 4 bytes each way", forwards)
+	}
+
+	if err := client.StopPortForward(forward.ID); err != nil {
+		t.Fatalf("StopPortForward: %v", err)
+	}
+	select {
+	case <-echo.returned:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the tunnel is still running after StopPortForward")
+	}
+	if len(client.ListPortForwards()) != 0 {
+		t.Errorf("port-forwards after StopPortForward = %+v, want none", client.ListPortForwards())
+	}
+}
+
+func TestPortForwardAnswersHalfClosedConnections(t *testing.T) {
+	useEchoTunnels(t, " bye")
+	pod := testPod("default", "web", "node-a")
+	pod.Status.Phase = v1.PodRunning
+	client, _, _ := newTestClient(t, pod)
+	client.config = &rest.Config{}
+
+	forward, err := client.StartPortForward(PortForwardTarget{Namespace: "default", Name: "web", RemotePort: 8080}, 0)
+	if err != nil {
+		t.Fatalf("StartPortForward: %v", err)
+	}
+	defer client.StopPortForward(forward.ID)
+
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(forward.LocalPort)))
+	if err != nil {
+		t.Fatalf("dialing the port-forward: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatalf("writing to the port-forward: %v", err)
+	}
+	if err := conn.(*net.TCPConn).CloseWrite(); err != nil {
+		t.Fatalf("half-closing the connection: %v", err)
+	}
+	if reply, err := io.ReadAll(conn); err != nil || string(reply) != "ping bye" {
+		t.Errorf("reply after half-closing = %q, %v, want %q", reply, err, "ping bye")
+	}
+}
+
+func TestPortForwardHoldsConnectionsWhileReconnecting(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := &portForwardSession{
+		listener:  listener,
+		stop:      make(chan struct{}),
+		status:    PortForwardReconnecting,
+		activated: make(chan struct{}),
+	}
+	defer listener.Close()
+	go session.acceptConnections()
+
+	upstream, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	echo := &echoTunnel{stop: make(chan struct{}), ready: make(chan struct{}), listener: upstream, returned: make(chan struct{})}
+	go echo.ForwardPorts()
+	defer close(echo.stop)
+
+	// The tunnel comes back while the connection waits.
+	time.AfterFunc(100*time.Millisecond, func() {
+		session.activate("web", upstream.Addr().(*net.TCPAddr).Port)
+	})
+	roundTrip(t, session.localPort(), "ping")
+
+	// Connections wait again once the tunnel is lost, until the session stops.
+	session.setStatus(PortForwardReconnecting, "pod web is gone")
+	waited := make(chan bool)
+	go func() {
+		_, ok := session.waitForTunnel()
+		waited <- ok
+	}()
+	select {
+	case <-waited:
+		t.Fatal("waitForTunnel returned while reconnecting")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(session.stop)
+	if ok := <-waited; ok {
+		t.Error("waitForTunnel = true after the session stopped, want false")
+	}
+}
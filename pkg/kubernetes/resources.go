@@ -0,0 +1,294 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+)
+
+const tableAcceptHeader = "application/json;as=Table;v=v1;g=meta.k8s.io,application/json"
+
+// APIResource describes a listable resource type discovered on the API server.
+type APIResource struct {
+	Group   string
+	Version string
+	Name    string
+	// SingularName is the singular name from discovery, such as "ingress"
+	// for "ingresses". Older servers and some CRDs leave it empty.
+	SingularName string
+	Kind         string
+	ShortNames   []string
+	Namespaced   bool
+	Verbs        []string
+}
+
+// ResourceTable is the server-side Table representation of a resource list.
+type ResourceTable struct {
+	Resource APIResource
+	Columns  []string
+	Rows     []ResourceRow
+}
+
+// ObjectRef identifies a single object of any resource type.
+type ObjectRef struct {
+	Resource  APIResource
+	Namespace string
+	Name      string
+}
+
+var (
+	PodResource        = APIResource{Version: "v1", Name: "pods", SingularName: "pod", Kind: "Pod", ShortNames: []string{"po"}, Namespaced: true}
+	NodeResource       = APIResource{Version: "v1", Name: "nodes", SingularName: "node", Kind: "Node", ShortNames: []string{"no"}}
+	ReplicaSetResource = APIResource{Group: "apps", Version: "v1", Name: "replicasets", SingularName: "replicaset", Kind: "ReplicaSet", ShortNames: []string{"rs"}, Namespaced: true}
+)
+
+type ResourceRow struct {
+	Name      string
+	Namespace string
+	Cells     []string
+}
+
+func (r APIResource) GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Name}
+}
+
+// String returns the resource in kubectl notation, e.g. "deployments.apps".
+func (r APIResource) String() string {
+	if r.Group == "" {
+		return r.Name
+	}
+	return r.Name + "." + r.Group
+}
+
+func (r APIResource) HasVerb(verb string) bool {
+	for _, v := range r.Verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether name refers to this resource by plural or singular
+// name, kind, short name or fully qualified "resource.group" form.
+func (r APIResource) Matches(name string) bool {
+	name = strings.ToLower(name)
+	if name == r.Name || name == strings.ToLower(r.Kind) || name == r.String() {
+		return true
+	}
+	if r.SingularName != "" && (name == r.SingularName || name == r.SingularName+"."+r.Group) {
+		return true
+	}
+	for _, short := range r.ShortNames {
+		if name == short {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the object as kind/name, prefixed by its namespace if it has one.
+func (o ObjectRef) String() string {
+	if o.Namespace == "" {
+		return fmt.Sprintf("%s/%s", strings.ToLower(o.Resource.Kind), o.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", o.Namespace, strings.ToLower(o.Resource.Kind), o.Name)
+}
+
+// Equal reports whether both references point at the same object.
+func (o ObjectRef) Equal(other ObjectRef) bool {
+	return o.Resource.GroupVersionResource() == other.Resource.GroupVersionResource() &&
+		o.Namespace == other.Namespace &&
+		o.Name == other.Name
+}
+
+func (c *Client) ListAPIResources() ([]APIResource, error) {
+	// The package function rather than the method, which fake discovery
+	// clients do not implement.
+	lists, err := discovery.ServerPreferredResources(c.clientset.Discovery())
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("failed to discover API resources: %v", err)
+	}
+
+	var resources []APIResource
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, res := range list.APIResources {
+			// Subresources such as pods/log cannot be listed on their own.
+			if strings.Contains(res.Name, "/") {
+				continue
+			}
+			resource := APIResource{
+				Group:        gv.Group,
+				Version:      gv.Version,
+				Name:         res.Name,
+				SingularName: res.SingularName,
+				Kind:         res.Kind,
+				ShortNames:   res.ShortNames,
+				Namespaced:   res.Namespaced,
+				Verbs:        res.Verbs,
+			}
+			if !resource.HasVerb("list") {
+				continue
+			}
+			resources = append(resources, resource)
+		}
+	}
+
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].String() < resources[j].String()
+	})
+	return resources, nil
+}
+
+func (c *Client) FindAPIResource(name string) (APIResource, error) {
+	resources, err := c.ListAPIResources()
+	if err != nil {
+		return APIResource{}, err
+	}
+	for _, resource := range resources {
+		if resource.Matches(name) {
+			return resource, nil
+		}
+	}
+
+	// Discovery may not know the singular name, so let a RESTMapper resolve
+	// the name the way kubectl does.
+	if gvr, err := c.mapResource(name); err == nil {
+		for _, resource := range resources {
+			if resource.Group == gvr.Group && resource.Name == gvr.Resource {
+				return resource, nil
+			}
+		}
+	}
+	return APIResource{}, fmt.Errorf("the server doesn't have a resource type %q", name)
+}
+
+// mapResource resolves name, in any form kubectl accepts, with a RESTMapper
+// built from discovery.
+func (c *Client) mapResource(name string) (schema.GroupVersionResource, error) {
+	groupResources, err := restmapper.GetAPIGroupResources(c.clientset.Discovery())
+	if err != nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("failed to discover API groups: %v", err)
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+
+	fullySpecified, groupResource := schema.ParseResourceArg(strings.ToLower(name))
+	if fullySpecified != nil {
+		if gvr, err := mapper.ResourceFor(*fullySpecified); err == nil {
+			return gvr, nil
+		}
+	}
+	return mapper.ResourceFor(groupResource.WithVersion(""))
+}
+
+// GetResourceTable lists instances of resource in the current namespace using the
+// server-side Table printer, so columns match what kubectl get shows.
+func (c *Client) GetResourceTable(resource APIResource) (*ResourceTable, error) {
+	restClient := c.clientset.Discovery().RESTClient()
+	if restClient == nil {
+		return nil, fmt.Errorf("table printing is not supported by this client")
+	}
+
+	raw, err := restClient.Get().
+		AbsPath(resourcePath(resource, c.namespace)).
+		SetHeader("Accept", tableAcceptHeader).
+		Do(context.TODO()).
+		Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	var table metav1.Table
+	if err := json.Unmarshal(raw, &table); err != nil {
+		return nil, fmt.Errorf("failed to decode table for %s: %v", resource, err)
+	}
+
+	result := &ResourceTable{Resource: resource}
+	var visible []int
+	for i, column := range table.ColumnDefinitions {
+		if column.Priority > 0 {
+			continue
+		}
+		visible = append(visible, i)
+		result.Columns = append(result.Columns, column.Name)
+	}
+
+	for _, row := range table.Rows {
+		var meta metav1.PartialObjectMetadata
+		if len(row.Object.Raw) > 0 {
+			if err := json.Unmarshal(row.Object.Raw, &meta); err != nil {
+				return nil, fmt.Errorf("failed to decode row metadata for %s: %v", resource, err)
+			}
+		}
+
+		resourceRow := ResourceRow{Name: meta.Name, Namespace: meta.Namespace}
+		for _, i := range visible {
+			if i < len(row.Cells) {
+				resourceRow.Cells = append(resourceRow.Cells, fmt.Sprintf("%v", row.Cells[i]))
+			} else {
+				resourceRow.Cells = append(resourceRow.Cells, "")
+			}
+		}
+		result.Rows = append(result.Rows, resourceRow)
+	}
+
+	return result, nil
+}
+
+// GetResourceYAML returns the complete object as YAML. managedFields are noisy and
+// rarely useful, so they are stripped unless showManagedFields is set.
+func (c *Client) GetResourceYAML(ref ObjectRef, showManagedFields bool) (string, error) {
+	obj, err := c.getUnstructured(ref)
+	if err != nil {
+		return "", err
+	}
+
+	if !showManagedFields {
+		unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+	}
+
+	out, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s as YAML: %v", ref, err)
+	}
+	return string(out), nil
+}
+
+func (c *Client) getUnstructured(ref ObjectRef) (*unstructured.Unstructured, error) {
+	resourceClient := c.dynamicClient.Resource(ref.Resource.GroupVersionResource())
+	if ref.Resource.Namespaced {
+		return resourceClient.Namespace(ref.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	}
+	return resourceClient.Get(context.TODO(), ref.Name, metav1.GetOptions{})
+}
+
+func resourcePath(resource APIResource, namespace string) string {
+	path := "/apis/" + resource.Group + "/" + resource.Version
+	if resource.Group == "" {
+		path = "/api/" + resource.Version
+	}
+	if resource.Namespaced && namespace != "" {
+		path += "/namespaces/" + namespace
+	}
+	return path + "/" + resource.Name
+}
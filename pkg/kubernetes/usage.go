@@ -0,0 +1,256 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// Usage is the CPU and memory a node, pod or container used at one time.
+type Usage struct {
+	Time   time.Time
+	CPU    int64 // millicores
+	Memory int64 // bytes
+}
+
+type NodeUsage struct {
+	Name string
+	Usage
+}
+
+// PodUsage is the usage of a pod, the sum of its containers.
+type PodUsage struct {
+	Pod Pod
+	// Workload is the controller owning the pod, such as "Deployment/web",
+	// or empty when it is unknown.
+	Workload   string
+	Containers []ContainerUsage
+	Usage
+	// Requests and Limits sum those of the containers. A limit is only set
+	// when every container has one.
+	Requests Resources
+	Limits   Resources
+}
+
+type ContainerUsage struct {
+	Name string
+	Usage
+	Requests Resources
+	Limits   Resources
+}
+
+// Resources are the CPU and memory requests or limits of a pod or container.
+// Zero means unset.
+type Resources struct {
+	CPU    int64 // millicores
+	Memory int64 // bytes
+}
+
+// Percent returns usage as a percentage of total, and false when total is
+// unset.
+func Percent(usage int64, total int64) (int64, bool) {
+	if total <= 0 {
+		return 0, false
+	}
+	return usage * 100 / total, true
+}
+
+// FormatCPU formats millicores the way the tables show them.
+func FormatCPU(millicores int64) string {
+	return fmt.Sprintf("%dm", millicores)
+}
+
+// FormatMemory formats bytes the way the tables show them.
+func FormatMemory(bytes int64) string {
+	return fmt.Sprintf("%dMi", bytes/(1024*1024))
+}
+
+// ListNodeUsage returns the current usage of every node in a single request.
+func (c *Client) ListNodeUsage() ([]NodeUsage, error) {
+	list, err := c.metricsClient.MetricsV1beta1().NodeMetricses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]NodeUsage, 0, len(list.Items))
+	for _, item := range list.Items {
+		usages = append(usages, NodeUsage{Name: item.Name, Usage: toUsage(item.Timestamp.Time, item.Usage)})
+	}
+	return usages, nil
+}
+
+// ListPodUsage returns the current usage of every pod and container in all
+// namespaces, or in the current namespace when the user may not list them
+// across namespaces.
+func (c *Client) ListPodUsage() ([]PodUsage, error) {
+	var list *metricsv1beta1.PodMetricsList
+	err := c.listAcrossNamespaces(func(namespace string) (err error) {
+		list, err = c.metricsClient.MetricsV1beta1().PodMetricses(namespace).List(context.TODO(), metav1.ListOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]PodUsage, 0, len(list.Items))
+	for _, item := range list.Items {
+		usage := PodUsage{
+			Pod:   Pod{Name: item.Name, Namespace: item.Namespace},
+			Usage: Usage{Time: item.Timestamp.Time},
+		}
+		for _, container := range item.Containers {
+			containerUsage := ContainerUsage{Name: container.Name, Usage: toUsage(item.Timestamp.Time, container.Usage)}
+			usage.Containers = append(usage.Containers, containerUsage)
+			usage.CPU += containerUsage.CPU
+			usage.Memory += containerUsage.Memory
+		}
+		usages = append(usages, usage)
+	}
+	c.setPodSpecs(usages)
+	return usages, nil
+}
+
+// listAcrossNamespaces calls list for all namespaces, and again for the
+// current namespace only when listing all of them is forbidden.
+func (c *Client) listAcrossNamespaces(list func(namespace string) error) error {
+	err := list("")
+	if apierrors.IsForbidden(err) && c.namespace != "" {
+		return list(c.namespace)
+	}
+	return err
+}
+
+// podSpecRefreshInterval bounds how often the pods are listed to find the
+// workloads and resources of new pods.
+const podSpecRefreshInterval = time.Minute
+
+// podSpecCache keeps what ListPodUsage needs from the pod specs, so the pods
+// are only listed again when new ones show up.
+type podSpecCache struct {
+	mu        sync.Mutex
+	byPod     map[string]podSpec
+	refreshed time.Time
+}
+
+type podSpec struct {
+	workload   string
+	containers map[string][2]Resources // requests and limits by container name
+}
+
+// setPodSpecs fills in the workload, requests and limits of usages, listing
+// the pods again when one of them is unknown.
+func (c *Client) setPodSpecs(usages []PodUsage) {
+	c.podSpecs.mu.Lock()
+	defer c.podSpecs.mu.Unlock()
+
+	for _, usage := range usages {
+		if _, ok := c.podSpecs.byPod[usage.Pod.Namespace+"/"+usage.Pod.Name]; !ok && time.Since(c.podSpecs.refreshed) > podSpecRefreshInterval {
+			c.podSpecs.refreshed = time.Now()
+			var list *v1.PodList
+			err := c.listAcrossNamespaces(func(namespace string) (err error) {
+				list, err = c.clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+				return err
+			})
+			if err != nil {
+				break
+			}
+			c.podSpecs.byPod = make(map[string]podSpec, len(list.Items))
+			for i := range list.Items {
+				pod := &list.Items[i]
+				c.podSpecs.byPod[pod.Namespace+"/"+pod.Name] = newPodSpec(pod)
+			}
+			break
+		}
+	}
+
+	for i := range usages {
+		usage := &usages[i]
+		spec, ok := c.podSpecs.byPod[usage.Pod.Namespace+"/"+usage.Pod.Name]
+		if !ok {
+			continue
+		}
+		usage.Workload = spec.workload
+		cpuLimited, memoryLimited := true, true
+		for j := range usage.Containers {
+			container := &usage.Containers[j]
+			resources := spec.containers[container.Name]
+			container.Requests, container.Limits = resources[0], resources[1]
+		}
+		for _, resources := range spec.containers {
+			usage.Requests.CPU += resources[0].CPU
+			usage.Requests.Memory += resources[0].Memory
+			usage.Limits.CPU += resources[1].CPU
+			usage.Limits.Memory += resources[1].Memory
+			cpuLimited = cpuLimited && resources[1].CPU > 0
+			memoryLimited = memoryLimited && resources[1].Memory > 0
+		}
+		if !cpuLimited {
+			usage.Limits.CPU = 0
+		}
+		if !memoryLimited {
+			usage.Limits.Memory = 0
+		}
+	}
+}
+
+// newPodSpec reads the workload of pod and the resources of its containers
+// that run alongside each other: the regular containers and the sidecars
+// among the init containers.
+func newPodSpec(pod *v1.Pod) podSpec {
+	spec := podSpec{workload: WorkloadOf(pod), containers: make(map[string][2]Resources)}
+	add := func(container v1.Container) {
+		spec.containers[container.Name] = [2]Resources{toResources(container.Resources.Requests), toResources(container.Resources.Limits)}
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if container.RestartPolicy != nil && *container.RestartPolicy == v1.ContainerRestartPolicyAlways {
+			add(container)
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		add(container)
+	}
+	return spec
+}
+
+func toResources(resources v1.ResourceList) Resources {
+	cpu := resources[v1.ResourceCPU]
+	memory := resources[v1.ResourceMemory]
+	return Resources{CPU: cpu.MilliValue(), Memory: memory.Value()}
+}
+
+// WorkloadOf names the workload owning pod as "Kind/name". Pods of a
+// Deployment are attributed to the Deployment rather than to its current
+// ReplicaSet, so their history survives rollouts. Pods without a controller
+// are their own workload.
+func WorkloadOf(pod *v1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind == "Node" {
+		return "Pod/" + pod.Name
+	}
+	if owner.Kind == "ReplicaSet" {
+		if hash := pod.Labels["pod-template-hash"]; hash != "" && strings.HasSuffix(owner.Name, "-"+hash) {
+			return "Deployment/" + strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+	}
+	return owner.Kind + "/" + owner.Name
+}
+
+func toUsage(at time.Time, resources v1.ResourceList) Usage {
+	cpu := resources[v1.ResourceCPU]
+	memory := resources[v1.ResourceMemory]
+	return Usage{Time: at, CPU: cpu.MilliValue(), Memory: memory.Value()}
+}
@@ -0,0 +1,336 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+	revisionAnnotation    = "deployment.kubernetes.io/revision"
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+)
+
+// Revision is one entry of a deployment's rollout history, backed by a ReplicaSet.
+type Revision struct {
+	Number      int64
+	ReplicaSet  string
+	ChangeCause string
+	Images      []string
+	Replicas    int32
+	Created     time.Time
+}
+
+// RolloutStatus summarizes the progress of a workload rollout.
+type RolloutStatus struct {
+	Desired   int32
+	Updated   int32
+	Ready     int32
+	Available int32
+	Paused    bool
+	Done      bool
+	Message   string
+}
+
+// IsWorkload reports whether resource is a built-in workload kind that supports
+// rollout operations.
+func IsWorkload(resource APIResource) bool {
+	if resource.Group != "apps" {
+		return false
+	}
+	switch resource.Name {
+	case "deployments", "statefulsets", "daemonsets", "replicasets":
+		return true
+	}
+	return false
+}
+
+func (c *Client) GetReplicas(ref ObjectRef) (int32, error) {
+	obj, err := c.getUnstructured(ref)
+	if err != nil {
+		return 0, err
+	}
+	replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("%s has no replica count", ref)
+	}
+	return int32(replicas), nil
+}
+
+// ScaleWorkload sets the replica count through the scale subresource, which
+// works for any scalable resource including custom resources.
+func (c *Client) ScaleWorkload(ref ObjectRef, replicas int32) error {
+	patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)
+	return c.patchResource(ref, "scale", types.MergePatchType, []byte(patch), "scale")
+}
+
+// RestartWorkload triggers a rolling restart the same way kubectl does, by
+// stamping the pod template with a restartedAt annotation.
+func (c *Client) RestartWorkload(ref ObjectRef) error {
+	if !IsWorkload(ref.Resource) || ref.Resource.Name == "replicasets" {
+		return fmt.Errorf("restart is not supported for %s", ref.Resource)
+	}
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, restartedAtAnnotation, time.Now().Format(time.RFC3339))
+	return c.patchResource(ref, "restart", types.StrategicMergePatchType, []byte(patch))
+}
+
+func (c *Client) SetRolloutPaused(ref ObjectRef, paused bool) error {
+	if !IsDeployment(ref.Resource) {
+		return fmt.Errorf("pause and resume are only supported for deployments")
+	}
+	verb := "pause"
+	if !paused {
+		verb = "resume"
+	}
+	patch := fmt.Sprintf(`{"spec":{"paused":%t}}`, paused)
+	return c.patchResource(ref, verb, types.StrategicMergePatchType, []byte(patch))
+}
+
+// GetRolloutHistory returns the revisions of a deployment, newest first.
+func (c *Client) GetRolloutHistory(ref ObjectRef) ([]Revision, error) {
+	if !IsDeployment(ref.Resource) {
+		return nil, fmt.Errorf("rollout history is only supported for deployments")
+	}
+
+	deployment, replicaSets, err := c.getDeploymentReplicaSets(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []Revision
+	for _, rs := range replicaSets {
+		number, err := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		if err != nil {
+			continue
+		}
+		revision := Revision{
+			Number:      number,
+			ReplicaSet:  rs.Name,
+			ChangeCause: rs.Annotations[changeCauseAnnotation],
+			Replicas:    rs.Status.Replicas,
+			Created:     rs.CreationTimestamp.Time,
+		}
+		for _, container := range rs.Spec.Template.Spec.Containers {
+			revision.Images = append(revision.Images, container.Image)
+		}
+		revisions = append(revisions, revision)
+	}
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("no revisions found for deployment %s/%s", deployment.Namespace, deployment.Name)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Number > revisions[j].Number
+	})
+	return revisions, nil
+}
+
+// UndoRollout rolls a deployment back to revision by copying the pod template
+// of the matching ReplicaSet into the deployment, like kubectl rollout undo.
+func (c *Client) UndoRollout(ref ObjectRef, revision int64) error {
+	if !IsDeployment(ref.Resource) {
+		return fmt.Errorf("rollout undo is only supported for deployments")
+	}
+
+	deployment, replicaSets, err := c.getDeploymentReplicaSets(ref)
+	if err != nil {
+		return err
+	}
+	if deployment.Spec.Paused {
+		return fmt.Errorf("cannot roll back a paused deployment, resume it first")
+	}
+
+	for _, rs := range replicaSets {
+		if rs.Annotations[revisionAnnotation] != strconv.FormatInt(revision, 10) {
+			continue
+		}
+
+		template := rs.Spec.Template.DeepCopy()
+		delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+
+		patch, err := json.Marshal([]map[string]interface{}{
+			{"op": "replace", "path": "/spec/template", "value": template},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to build rollback patch: %v", err)
+		}
+		return c.patchResource(ref, fmt.Sprintf("undo to revision %d", revision), types.JSONPatchType, patch)
+	}
+
+	return fmt.Errorf("revision %d not found for deployment %s/%s", revision, ref.Namespace, ref.Name)
+}
+
+func (c *Client) GetRolloutStatus(ref ObjectRef) (RolloutStatus, error) {
+	apps := c.clientset.AppsV1()
+
+	switch ref.Resource.Name {
+	case "deployments":
+		deployment, err := apps.Deployments(ref.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return RolloutStatus{}, err
+		}
+		status := RolloutStatus{
+			Desired:   replicasOrDefault(deployment.Spec.Replicas),
+			Updated:   deployment.Status.UpdatedReplicas,
+			Ready:     deployment.Status.ReadyReplicas,
+			Available: deployment.Status.AvailableReplicas,
+			Paused:    deployment.Spec.Paused,
+		}
+		switch {
+		case deployment.Generation > deployment.Status.ObservedGeneration:
+			status.Message = "Waiting for the deployment spec update to be observed"
+		case status.Updated < status.Desired:
+			status.Message = fmt.Sprintf("%d of %d new replicas have been updated", status.Updated, status.Desired)
+		case deployment.Status.Replicas > status.Updated:
+			status.Message = fmt.Sprintf("%d old replicas are pending termination", deployment.Status.Replicas-status.Updated)
+		case status.Available < status.Updated:
+			status.Message = fmt.Sprintf("%d of %d updated replicas are available", status.Available, status.Updated)
+		default:
+			status.Done = true
+			status.Message = "Rollout complete"
+		}
+		for _, condition := range deployment.Status.Conditions {
+			if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+				status.Message = "Rollout exceeded its progress deadline: " + condition.Message
+			}
+		}
+		return status, nil
+	case "statefulsets":
+		statefulSet, err := apps.StatefulSets(ref.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return RolloutStatus{}, err
+		}
+		status := RolloutStatus{
+			Desired:   replicasOrDefault(statefulSet.Spec.Replicas),
+			Updated:   statefulSet.Status.UpdatedReplicas,
+			Ready:     statefulSet.Status.ReadyReplicas,
+			Available: statefulSet.Status.AvailableReplicas,
+		}
+		switch {
+		case statefulSet.Generation > statefulSet.Status.ObservedGeneration:
+			status.Message = "Waiting for the statefulset spec update to be observed"
+		case status.Ready < status.Desired:
+			status.Message = fmt.Sprintf("%d of %d pods are ready", status.Ready, status.Desired)
+		case statefulSet.Status.UpdateRevision != statefulSet.Status.CurrentRevision:
+			status.Message = fmt.Sprintf("%d of %d pods have been updated", status.Updated, status.Desired)
+		default:
+			status.Done = true
+			status.Message = "Rollout complete"
+		}
+		return status, nil
+	case "daemonsets":
+		daemonSet, err := apps.DaemonSets(ref.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return RolloutStatus{}, err
+		}
+		status := RolloutStatus{
+			Desired:   daemonSet.Status.DesiredNumberScheduled,
+			Updated:   daemonSet.Status.UpdatedNumberScheduled,
+			Ready:     daemonSet.Status.NumberReady,
+			Available: daemonSet.Status.NumberAvailable,
+		}
+		switch {
+		case daemonSet.Generation > daemonSet.Status.ObservedGeneration:
+			status.Message = "Waiting for the daemonset spec update to be observed"
+		case status.Updated < status.Desired:
+			status.Message = fmt.Sprintf("%d of %d pods have been updated", status.Updated, status.Desired)
+		case status.Available < status.Desired:
+			status.Message = fmt.Sprintf("%d of %d updated pods are available", status.Available, status.Desired)
+		default:
+			status.Done = true
+			status.Message = "Rollout complete"
+		}
+		return status, nil
+	case "replicasets":
+		replicaSet, err := apps.ReplicaSets(ref.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return RolloutStatus{}, err
+		}
+		status := RolloutStatus{
+			Desired:   replicasOrDefault(replicaSet.Spec.Replicas),
+			Updated:   replicaSet.Status.Replicas,
+			Ready:     replicaSet.Status.ReadyReplicas,
+			Available: replicaSet.Status.AvailableReplicas,
+		}
+		if status.Available == status.Desired && replicaSet.Status.Replicas == status.Desired {
+			status.Done = true
+			status.Message = "All replicas are available"
+		} else {
+			status.Message = fmt.Sprintf("%d of %d replicas are available", status.Available, status.Desired)
+		}
+		return status, nil
+	default:
+		return RolloutStatus{}, fmt.Errorf("rollout status is not supported for %s", ref.Resource)
+	}
+}
+
+func (c *Client) getDeploymentReplicaSets(ref ObjectRef) (*appsv1.Deployment, []appsv1.ReplicaSet, error) {
+	deployment, err := c.clientset.AppsV1().Deployments(ref.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid selector on deployment %s/%s: %v", ref.Namespace, ref.Name, err)
+	}
+	list, err := c.clientset.AppsV1().ReplicaSets(ref.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var owned []appsv1.ReplicaSet
+	for _, rs := range list.Items {
+		if controller := metav1.GetControllerOf(&rs); controller != nil && controller.UID == deployment.UID {
+			owned = append(owned, rs)
+		}
+	}
+	return deployment, owned, nil
+}
+
+func (c *Client) patchResource(ref ObjectRef, verb string, patchType types.PatchType, patch []byte, subresources ...string) error {
+	resourceClient := c.dynamicClient.Resource(ref.Resource.GroupVersionResource())
+	options := metav1.PatchOptions{FieldManager: fieldManager}
+
+	return c.write(verb, ref.String(), func() error {
+		var err error
+		if ref.Resource.Namespaced {
+			_, err = resourceClient.Namespace(ref.Namespace).Patch(context.TODO(), ref.Name, patchType, patch, options, subresources...)
+		} else {
+			_, err = resourceClient.Patch(context.TODO(), ref.Name, patchType, patch, options, subresources...)
+		}
+		return err
+	})
+}
+
+// IsDeployment reports whether resource is the deployment kind, the only one
+// supporting pause, resume and rollout history.
+func IsDeployment(resource APIResource) bool {
+	return resource.Group == "apps" && resource.Name == "deployments"
+}
+
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
@@ -0,0 +1,184 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+var deploymentResource = APIResource{Group: "apps", Version: "v1", Name: "deployments", SingularName: "deployment", Kind: "Deployment", Namespaced: true}
+
+func int32Pointer(value int32) *int32 {
+	return &value
+}
+
+func testDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web", UID: types.UID("web-uid"), Generation: 2},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Pointer(3),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template: podTemplate("web:2"),
+		},
+		Status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3, AvailableReplicas: 3},
+	}
+}
+
+func podTemplate(image string) v1.PodTemplateSpec {
+	return v1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "web", Image: image}}},
+	}
+}
+
+// testReplicaSet returns revision of the deployment web running image.
+func testReplicaSet(revision string, image string, owner *appsv1.Deployment) *appsv1.ReplicaSet {
+	template := podTemplate(image)
+	template.Labels[appsv1.DefaultDeploymentUniqueLabelKey] = "hash-" + revision
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "web-" + revision,
+			Labels:          map[string]string{"app": "web"},
+			Annotations:     map[string]string{revisionAnnotation: revision, changeCauseAnnotation: "deploy " + image},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner, appsv1.SchemeGroupVersion.WithKind("Deployment"))},
+		},
+		Spec: appsv1.ReplicaSetSpec{Template: template},
+	}
+}
+
+// newWorkloadClient returns a client holding the deployment web, with
+// revisions 1 (web:1) and 2 (web:2), and a replica set of another owner.
+func newWorkloadClient(t *testing.T, deployment *appsv1.Deployment) (*Client, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	other := testReplicaSet("1", "other:1", &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "other", UID: types.UID("other-uid")}})
+	other.Name = "other-1"
+	clientset := fake.NewSimpleClientset(deployment, testReplicaSet("1", "web:1", deployment), testReplicaSet("2", "web:2", deployment), other)
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	object := &unstructured.Unstructured{Object: content}
+	object.SetAPIVersion("apps/v1")
+	object.SetKind("Deployment")
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), object)
+	return NewClientFromInterfaces(clientset, metricsfake.NewSimpleClientset(), dynamicClient, "default"), dynamicClient
+}
+
+func TestGetRolloutHistory(t *testing.T) {
+	client, _ := newWorkloadClient(t, testDeployment())
+	revisions, err := client.GetRolloutHistory(ObjectRef{Resource: deploymentResource, Namespace: "default", Name: "web"})
+	if err != nil {
+		t.Fatalf("GetRolloutHistory: %v", err)
+	}
+	var got []string
+	for _, revision := range revisions {
+		got = append(got, revision.ReplicaSet+" "+strings.Join(revision.Images, ",")+" "+revision.ChangeCause)
+	}
+	if want := []string{"web-2 web:2 deploy web:2", "web-1 web:1 deploy web:1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetRolloutHistory = %q, want %q", got, want)
+	}
+
+	if _, err := client.GetRolloutHistory(ObjectRef{Resource: ReplicaSetResource, Namespace: "default", Name: "web-1"}); err == nil {
+		t.Error("GetRolloutHistory of a replica set succeeded, want an error")
+	}
+}
+
+func TestGetReplicas(t *testing.T) {
+	client, _ := newWorkloadClient(t, testDeployment())
+	if replicas, err := client.GetReplicas(ObjectRef{Resource: deploymentResource, Namespace: "default", Name: "web"}); err != nil || replicas != 3 {
+		t.Errorf("GetReplicas = %d, %v, want 3", replicas, err)
+	}
+}
+
+func TestUndoRollout(t *testing.T) {
+	client, dynamicClient := newWorkloadClient(t, testDeployment())
+	ref := ObjectRef{Resource: deploymentResource, Namespace: "default", Name: "web"}
+
+	if err := client.UndoRollout(ref, 1); err != nil {
+		t.Fatalf("UndoRollout: %v", err)
+	}
+	updated, err := dynamicClient.Resource(deploymentResource.GroupVersionResource()).Namespace("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(updated.Object["spec"].(map[string]interface{})["template"])
+	var template v1.PodTemplateSpec
+	if err := json.Unmarshal(data, &template); err != nil {
+		t.Fatal(err)
+	}
+	if image := template.Spec.Containers[0].Image; image != "web:1" {
+		t.Errorf("image after UndoRollout = %q, want web:1", image)
+	}
+	if _, ok := template.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; ok {
+		t.Errorf("template labels after UndoRollout = %v, want no %s", template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	}
+
+	if err := client.UndoRollout(ref, 7); err == nil || !strings.Contains(err.Error(), "revision 7 not found") {
+		t.Errorf("UndoRollout to a missing revision error = %v", err)
+	}
+
+	paused := testDeployment()
+	paused.Spec.Paused = true
+	client, _ = newWorkloadClient(t, paused)
+	if err := client.UndoRollout(ref, 1); err == nil || !strings.Contains(err.Error(), "paused") {
+		t.Errorf("UndoRollout of a paused deployment error = %v", err)
+	}
+}
+
+func TestGetRolloutStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*appsv1.Deployment)
+		done   bool
+		want   string
+	}{
+		{"complete", func(*appsv1.Deployment) {}, true, "Rollout complete"},
+		{"not observed", func(d *appsv1.Deployment) { d.Generation = 3 }, false, "Waiting for the deployment spec update"},
+		{"updating", func(d *appsv1.Deployment) { d.Status.UpdatedReplicas = 1 }, false, "1 of 3 new replicas have been updated"},
+		{"old replicas", func(d *appsv1.Deployment) { d.Status.Replicas = 4 }, false, "1 old replicas are pending termination"},
+		{"unavailable", func(d *appsv1.Deployment) { d.Status.AvailableReplicas = 2 }, false, "2 of 3 updated replicas are available"},
+		{"deadline", func(d *appsv1.Deployment) {
+			d.Status.UpdatedReplicas = 1
+			d.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Reason: "ProgressDeadlineExceeded", Message: "timed out"}}
+		}, false, "Rollout exceeded its progress deadline: timed out"},
+	}
+	for _, test := range tests {
+		deployment := testDeployment()
+		test.modify(deployment)
+		client, _ := newWorkloadClient(t, deployment)
+		status, err := client.GetRolloutStatus(ObjectRef{Resource: deploymentResource, Namespace: "default", Name: "web"})
+		if err != nil {
+			t.Errorf("%s: GetRolloutStatus: %v", test.name, err)
+			continue
+		}
+		if status.Done != test.done || !strings.HasPrefix(status.Message, test.want) {
+			t.Errorf("%s: GetRolloutStatus = %+v, want done %v and %q", test.name, status, test.done, test.want)
+		}
+	}
+
+	client, _ := newWorkloadClient(t, testDeployment())
+	if _, err := client.GetRolloutStatus(ObjectRef{Resource: NodeResource, Name: "node-a"}); err == nil {
+		t.Error("GetRolloutStatus of a node succeeded, want an error")
+	}
+}
@@ -0,0 +1,243 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+// Package metrics keeps a short in-memory history of the usage samples
+// KubePulse collects, so trends can be shown next to the current values.
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+)
+
+// DefaultCapacity is the number of samples kept per node, pod and container:
+// 30 minutes at the resolution of metrics-server.
+const DefaultCapacity = 120
+
+// History keeps the most recent samples of every node, pod and container. It
+// is safe for concurrent use.
+type History struct {
+	mu         sync.Mutex
+	capacity   int
+	nodes      map[string]*ring
+	pods       map[string]*ring
+	containers map[string]map[string]*ring    // by pod key, then container name
+	latest     map[string]kubernetes.PodUsage // by pod key
+}
+
+func NewHistory(capacity int) *History {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &History{
+		capacity:   capacity,
+		nodes:      make(map[string]*ring),
+		pods:       make(map[string]*ring),
+		containers: make(map[string]map[string]*ring),
+		latest:     make(map[string]kubernetes.PodUsage),
+	}
+}
+
+// RecordNodes adds a sample for each node. Nodes missing from usages have
+// left the cluster and their history is dropped.
+func (h *History) RecordNodes(usages []kubernetes.NodeUsage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	seen := make(map[string]bool, len(usages))
+	for _, usage := range usages {
+		seen[usage.Name] = true
+		h.ringFor(h.nodes, usage.Name).add(usage.Usage)
+	}
+	for name := range h.nodes {
+		if !seen[name] {
+			delete(h.nodes, name)
+		}
+	}
+}
+
+// RecordPods adds a sample for each pod and its containers. Pods missing from
+// usages have been deleted and their history is dropped.
+func (h *History) RecordPods(usages []kubernetes.PodUsage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	seen := make(map[string]bool, len(usages))
+	for _, usage := range usages {
+		key := podKey(usage.Pod)
+		seen[key] = true
+		h.ringFor(h.pods, key).add(usage.Usage)
+		h.latest[key] = usage
+
+		if h.containers[key] == nil {
+			h.containers[key] = make(map[string]*ring)
+		}
+		for _, container := range usage.Containers {
+			h.ringFor(h.containers[key], container.Name).add(container.Usage)
+		}
+	}
+	for key := range h.pods {
+		if !seen[key] {
+			delete(h.pods, key)
+			delete(h.containers, key)
+			delete(h.latest, key)
+		}
+	}
+}
+
+// Node returns the samples of a node, oldest first.
+func (h *History) Node(name string) []kubernetes.Usage {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.nodes[name].samples()
+}
+
+// Pod returns the samples of a pod, oldest first.
+func (h *History) Pod(pod kubernetes.Pod) []kubernetes.Usage {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.pods[podKey(pod)].samples()
+}
+
+// Workload returns the workload owning pod when it is known, such as
+// "Deployment/web".
+func (h *History) Workload(pod kubernetes.Pod) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.latest[podKey(pod)].Workload
+}
+
+// Latest returns the last usage recorded for pod, with its requests and
+// limits.
+func (h *History) Latest(pod kubernetes.Pod) (kubernetes.PodUsage, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	usage, ok := h.latest[podKey(pod)]
+	return usage, ok
+}
+
+// Containers returns the names of the containers of pod with samples, sorted.
+func (h *History) Containers(pod kubernetes.Pod) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var names []string
+	for name := range h.containers[podKey(pod)] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Container returns the samples of a container, oldest first.
+func (h *History) Container(pod kubernetes.Pod, container string) []kubernetes.Usage {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.containers[podKey(pod)][container].samples()
+}
+
+// WorkloadContainer is a container of a workload, with its samples in every
+// pod of the workload and its requests and limits in the latest of them.
+type WorkloadContainer struct {
+	Namespace string
+	Workload  string
+	Container string
+	Requests  kubernetes.Resources
+	Limits    kubernetes.Resources
+	Samples   []kubernetes.Usage
+}
+
+// WorkloadContainers groups the containers of the sampled pods in namespace,
+// or in every namespace when it is empty, by workload. Pods whose workload is
+// unknown are left out. The result is sorted by namespace, workload and
+// container.
+func (h *History) WorkloadContainers(namespace string) []WorkloadContainer {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	byKey := make(map[string]*WorkloadContainer)
+	latest := make(map[string]time.Time)
+	for key, usage := range h.latest {
+		if usage.Workload == "" || (namespace != "" && usage.Pod.Namespace != namespace) {
+			continue
+		}
+		for _, container := range usage.Containers {
+			groupKey := usage.Pod.Namespace + "/" + usage.Workload + "/" + container.Name
+			group, ok := byKey[groupKey]
+			if !ok {
+				group = &WorkloadContainer{Namespace: usage.Pod.Namespace, Workload: usage.Workload, Container: container.Name}
+				byKey[groupKey] = group
+			}
+			if !usage.Time.Before(latest[groupKey]) {
+				latest[groupKey] = usage.Time
+				group.Requests, group.Limits = container.Requests, container.Limits
+			}
+			group.Samples = append(group.Samples, h.containers[key][container.Name].samples()...)
+		}
+	}
+
+	containers := make([]WorkloadContainer, 0, len(byKey))
+	for _, group := range byKey {
+		sort.Slice(group.Samples, func(i, j int) bool { return group.Samples[i].Time.Before(group.Samples[j].Time) })
+		containers = append(containers, *group)
+	}
+	sort.Slice(containers, func(i, j int) bool {
+		a, b := containers[i], containers[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Workload != b.Workload {
+			return a.Workload < b.Workload
+		}
+		return a.Container < b.Container
+	})
+	return containers
+}
+
+func (h *History) ringFor(rings map[string]*ring, key string) *ring {
+	r, ok := rings[key]
+	if !ok {
+		r = &ring{buffer: make([]kubernetes.Usage, 0, h.capacity)}
+		rings[key] = r
+	}
+	return r
+}
+
+func podKey(pod kubernetes.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
+
+// ring is a fixed-size buffer that overwrites its oldest sample when full.
+type ring struct {
+	buffer []kubernetes.Usage
+	start  int
+}
+
+// add appends sample. metrics-server only refreshes its values once per
+// scrape, so a sample with the same timestamp as the last one is skipped.
+func (r *ring) add(sample kubernetes.Usage) {
+	if n := len(r.buffer); n > 0 && r.buffer[(r.start+n-1)%n].Time.Equal(sample.Time) {
+		return
+	}
+	if len(r.buffer) < cap(r.buffer) {
+		r.buffer = append(r.buffer, sample)
+		return
+	}
+	r.buffer[r.start] = sample
+	r.start = (r.start + 1) % len(r.buffer)
+}
+
+func (r *ring) samples() []kubernetes.Usage {
+	if r == nil {
+		return nil
+	}
+	samples := make([]kubernetes.Usage, 0, len(r.buffer))
+	samples = append(samples, r.buffer[r.start:]...)
+	return append(samples, r.buffer[:r.start]...)
+}
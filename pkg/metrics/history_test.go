@@ -0,0 +1,79 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+)
+
+var start = time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func sample(i int) kubernetes.Usage {
+	return kubernetes.Usage{Time: start.Add(time.Duration(i) * 15 * time.Second), CPU: int64(i), Memory: int64(i) << 20}
+}
+
+func cpus(samples []kubernetes.Usage) []int64 {
+	var values []int64
+	for _, s := range samples {
+		values = append(values, s.CPU)
+	}
+	return values
+}
+
+func TestHistoryKeepsMostRecentSamples(t *testing.T) {
+	history := NewHistory(3)
+	for i := 1; i <= 5; i++ {
+		history.RecordNodes([]kubernetes.NodeUsage{{Name: "node-a", Usage: sample(i)}})
+	}
+	if got, want := cpus(history.Node("node-a")), []int64{3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Node samples = %v, want %v", got, want)
+	}
+}
+
+func TestHistorySkipsRepeatedScrapes(t *testing.T) {
+	history := NewHistory(10)
+	history.RecordNodes([]kubernetes.NodeUsage{{Name: "node-a", Usage: sample(1)}})
+	history.RecordNodes([]kubernetes.NodeUsage{{Name: "node-a", Usage: sample(1)}})
+	if got := history.Node("node-a"); len(got) != 1 {
+		t.Errorf("Node samples = %v, want a single sample", got)
+	}
+}
+
+func TestHistoryRecordsContainersAndForgetsDeletedPods(t *testing.T) {
+	history := NewHistory(10)
+	web := kubernetes.Pod{Name: "web", Namespace: "default"}
+	api := kubernetes.Pod{Name: "api", Namespace: "default"}
+	history.RecordPods([]kubernetes.PodUsage{
+		{Pod: web, Usage: sample(3), Containers: []kubernetes.ContainerUsage{
+			{Name: "proxy", Usage: sample(1)},
+			{Name: "app", Usage: sample(2)},
+		}},
+		{Pod: api, Usage: sample(4)},
+	})
+	if got, want := history.Containers(web), []string{"app", "proxy"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Containers = %v, want %v", got, want)
+	}
+	if got := cpus(history.Container(web, "app")); !reflect.DeepEqual(got, []int64{2}) {
+		t.Errorf("Container samples = %v, want [2]", got)
+	}
+
+	history.RecordPods([]kubernetes.PodUsage{{Pod: api, Usage: sample(5)}})
+	if got := history.Pod(web); got != nil {
+		t.Errorf("deleted pod still has samples %v", got)
+	}
+	if got := history.Containers(web); got != nil {
+		t.Errorf("deleted pod still has containers %v", got)
+	}
+	if got := cpus(history.Pod(api)); !reflect.DeepEqual(got, []int64{4, 5}) {
+		t.Errorf("Pod samples = %v, want [4 5]", got)
+	}
+}
@@ -0,0 +1,165 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+)
+
+const (
+	// DefaultPercentile is the percentile of usage a recommended request
+	// covers.
+	DefaultPercentile = 90
+	// DefaultHeadroom is the percentage added on top of the observed usage.
+	DefaultHeadroom = 15
+	// MinRecommendationSamples is the number of samples needed before
+	// recommending anything: two minutes at the resolution of metrics-server.
+	MinRecommendationSamples = 8
+	// ConfidentSpan is how long usage has to be sampled before a
+	// recommendation is trusted. Shorter ones likely miss the busy hours of
+	// the day, so they are shown as low confidence.
+	ConfidentSpan = 6 * time.Hour
+)
+
+// RecommendOptions configure right-sizing recommendations.
+type RecommendOptions struct {
+	Percentile int // of usage the request covers, DefaultPercentile when zero
+	Headroom   int // percent added to the observed usage
+}
+
+// Recommendation is the suggested requests and limits of a container. Requests
+// cover the configured percentile of usage and limits its peak, both with
+// headroom.
+type Recommendation struct {
+	Requests kubernetes.Resources
+	Limits   kubernetes.Resources
+	Samples  int
+	Span     time.Duration
+}
+
+// LowConfidence reports whether the samples span less than ConfidentSpan.
+func (r Recommendation) LowConfidence() bool {
+	return r.Span < ConfidentSpan
+}
+
+// Provisioning tells how current requests compare to a recommendation.
+type Provisioning int
+
+const (
+	RightSized Provisioning = iota
+	OverProvisioned
+	UnderProvisioned
+)
+
+func (p Provisioning) String() string {
+	switch p {
+	case OverProvisioned:
+		return "Over-provisioned"
+	case UnderProvisioned:
+		return "Under-provisioned"
+	default:
+		return "Right-sized"
+	}
+}
+
+// Recommend derives requests from samples and limits from peaks, the largest
+// usage within the period each sample averages, or returns false when there
+// are too few samples to tell. Peaks may be nil when the samples are not
+// averages, to take the limits from the samples.
+func Recommend(samples []kubernetes.Usage, peaks []kubernetes.Usage, options RecommendOptions) (Recommendation, bool) {
+	if len(samples) < MinRecommendationSamples {
+		return Recommendation{}, false
+	}
+	percentile := options.Percentile
+	if percentile <= 0 || percentile > 100 {
+		percentile = DefaultPercentile
+	}
+	headroom := max(options.Headroom, 0)
+
+	cpu := make([]int64, len(samples))
+	memory := make([]int64, len(samples))
+	first, last := samples[0].Time, samples[0].Time
+	for i, sample := range samples {
+		cpu[i], memory[i] = sample.CPU, sample.Memory
+		if sample.Time.Before(first) {
+			first = sample.Time
+		}
+		if sample.Time.After(last) {
+			last = sample.Time
+		}
+	}
+	sort.Slice(cpu, func(i, j int) bool { return cpu[i] < cpu[j] })
+	sort.Slice(memory, func(i, j int) bool { return memory[i] < memory[j] })
+	cpuPeak, memoryPeak := cpu[len(cpu)-1], memory[len(memory)-1]
+	for _, peak := range peaks {
+		cpuPeak, memoryPeak = max(cpuPeak, peak.CPU), max(memoryPeak, peak.Memory)
+	}
+
+	grow := func(value int64) int64 { return value * int64(100+headroom) / 100 }
+	recommendation := Recommendation{
+		Requests: kubernetes.Resources{
+			CPU:    roundCPU(grow(nearestRank(cpu, percentile))),
+			Memory: roundMemory(grow(nearestRank(memory, percentile))),
+		},
+		Limits: kubernetes.Resources{
+			CPU:    roundCPU(grow(cpuPeak)),
+			Memory: roundMemory(grow(memoryPeak)),
+		},
+		Samples: len(samples),
+		Span:    last.Sub(first),
+	}
+	return recommendation, true
+}
+
+// Classify compares the current requests with the recommended ones. Requests
+// below the recommendation, or unset, are under-provisioned; requests more
+// than half again as large as the recommendation, and by more than a few
+// millicores or mebibytes, are over-provisioned. The second value ranks how
+// far off the requests are, larger is worse.
+func Classify(current kubernetes.Resources, recommended kubernetes.Resources) (Provisioning, float64) {
+	provisioning, severity := RightSized, 0.0
+	for _, resource := range []struct{ have, want, slack int64 }{
+		{current.CPU, recommended.CPU, 25},
+		{current.Memory, recommended.Memory, 32 << 20},
+	} {
+		have, want := resource.have, resource.want
+		switch {
+		case have <= 0:
+			return UnderProvisioned, float64(1 << 30)
+		case have < want*9/10:
+			if ratio := float64(want) / float64(have); provisioning != UnderProvisioned || ratio > severity {
+				provisioning, severity = UnderProvisioned, ratio
+			}
+		case have > want*3/2 && have-want > resource.slack && provisioning != UnderProvisioned:
+			if ratio := float64(have) / float64(want); ratio > severity {
+				provisioning, severity = OverProvisioned, ratio
+			}
+		}
+	}
+	return provisioning, severity
+}
+
+// nearestRank returns the percentile of sorted values.
+func nearestRank(sorted []int64, percentile int) int64 {
+	rank := (percentile*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// roundCPU rounds millicores up to a multiple of 5m.
+func roundCPU(millicores int64) int64 {
+	return max((millicores+4)/5*5, 5)
+}
+
+// roundMemory rounds bytes up to a whole MiB.
+func roundMemory(bytes int64) int64 {
+	const mebibyte = 1024 * 1024
+	return max((bytes+mebibyte-1)/mebibyte*mebibyte, mebibyte)
+}
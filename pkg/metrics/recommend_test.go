@@ -0,0 +1,98 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+)
+
+func TestRecommendUsesPercentileAndPeak(t *testing.T) {
+	// 20 samples from 10m to 200m and 10Mi to 200Mi.
+	var samples []kubernetes.Usage
+	for i := 1; i <= 20; i++ {
+		samples = append(samples, kubernetes.Usage{Time: start.Add(time.Duration(i) * time.Minute), CPU: int64(10 * i), Memory: int64(10*i) << 20})
+	}
+
+	recommendation, ok := Recommend(samples, nil, RecommendOptions{Percentile: 90, Headroom: 10})
+	if !ok {
+		t.Fatalf("Recommend found too few samples")
+	}
+	want := Recommendation{
+		Requests: kubernetes.Resources{CPU: 200, Memory: 198 << 20},
+		Limits:   kubernetes.Resources{CPU: 220, Memory: 220 << 20},
+		Samples:  20,
+		Span:     19 * time.Minute,
+	}
+	if recommendation != want {
+		t.Errorf("Recommend = %+v, want %+v", recommendation, want)
+	}
+
+	if _, ok := Recommend(samples[:MinRecommendationSamples-1], nil, RecommendOptions{}); ok {
+		t.Errorf("Recommend accepted %d samples", MinRecommendationSamples-1)
+	}
+}
+
+func TestRecommendTakesLimitsFromPeaks(t *testing.T) {
+	var samples, peaks []kubernetes.Usage
+	for i := 0; i < 8*60; i++ {
+		at := start.Add(time.Duration(i) * time.Minute)
+		samples = append(samples, kubernetes.Usage{Time: at, CPU: 100, Memory: 100 << 20})
+		peaks = append(peaks, kubernetes.Usage{Time: at, CPU: 100, Memory: 100 << 20})
+	}
+	// A spike within one minute barely moves its average.
+	samples[30].CPU, peaks[30].CPU = 110, 400
+
+	recommendation, ok := Recommend(samples, peaks, RecommendOptions{Percentile: 90})
+	if !ok {
+		t.Fatalf("Recommend found too few samples")
+	}
+	if got, want := recommendation.Limits, (kubernetes.Resources{CPU: 400, Memory: 100 << 20}); got != want {
+		t.Errorf("limits = %+v, want the peaks %+v", got, want)
+	}
+	if got := recommendation.Requests.CPU; got != 100 {
+		t.Errorf("CPU request = %dm, want 100m", got)
+	}
+	if recommendation.LowConfidence() {
+		t.Errorf("recommendation over %s has low confidence", recommendation.Span)
+	}
+
+	if short, _ := Recommend(samples[:60], peaks[:60], RecommendOptions{}); !short.LowConfidence() {
+		t.Errorf("recommendation over %s does not have low confidence", short.Span)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	recommended := kubernetes.Resources{CPU: 100, Memory: 256 << 20}
+	for _, tc := range []struct {
+		current kubernetes.Resources
+		want    Provisioning
+	}{
+		{kubernetes.Resources{CPU: 100, Memory: 256 << 20}, RightSized},
+		{kubernetes.Resources{CPU: 500, Memory: 256 << 20}, OverProvisioned},
+		{kubernetes.Resources{CPU: 500, Memory: 128 << 20}, UnderProvisioned},
+		{kubernetes.Resources{Memory: 256 << 20}, UnderProvisioned},
+		{kubernetes.Resources{CPU: 10, Memory: 256 << 20}, UnderProvisioned},
+	} {
+		if got, _ := Classify(tc.current, recommended); got != tc.want {
+			t.Errorf("Classify(%+v) = %v, want %v", tc.current, got, tc.want)
+		}
+	}
+	// Twice as much, but only by a few millicores and mebibytes.
+	if got, _ := Classify(kubernetes.Resources{CPU: 10, Memory: 8 << 20}, kubernetes.Resources{CPU: 5, Memory: 4 << 20}); got != RightSized {
+		t.Errorf("Classify of a tiny container = %v, want %v", got, RightSized)
+	}
+
+	_, slightly := Classify(kubernetes.Resources{CPU: 200, Memory: 256 << 20}, recommended)
+	_, heavily := Classify(kubernetes.Resources{CPU: 800, Memory: 256 << 20}, recommended)
+	if heavily <= slightly {
+		t.Errorf("severity of 8x (%v) is not above 2x (%v)", heavily, slightly)
+	}
+}
@@ -0,0 +1,577 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package metrics
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/utils"
+)
+
+// DefaultRetention is how long the store keeps samples unless configured
+// otherwise.
+const DefaultRetention = 24 * time.Hour
+
+const (
+	headFile     = "head.jsonl"
+	blockPattern = "block-*.jsonl.gz"
+
+	// blockSpan is the period covered by a block file. Samples are moved out
+	// of the head into a block once their hour is over.
+	blockSpan = int64(time.Hour / time.Second)
+	// blockResolution is the resolution samples are averaged to in blocks.
+	blockResolution = int64(time.Minute / time.Second)
+)
+
+// DefaultStorePath returns the directory the metrics of every context are
+// stored in, for example ~/.cache/kubepulse/metrics on Linux.
+func DefaultStorePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kubepulse", "metrics")
+}
+
+// ContextDir returns the directory within root storing the metrics of a
+// kubeconfig context, with the characters unsafe in file names replaced.
+func ContextDir(root string, contextName string) string {
+	name := []rune(contextName)
+	for i, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.' || r == '_') {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 || string(name) == "." || string(name) == ".." {
+		return filepath.Join(root, "_")
+	}
+	return filepath.Join(root, string(name))
+}
+
+// NodeSeries names the series of a node in the store.
+func NodeSeries(name string) string {
+	return "node/" + name
+}
+
+// PodSeries names the series of a pod in the store.
+func PodSeries(pod kubernetes.Pod) string {
+	return "pod/" + podKey(pod)
+}
+
+// WorkloadSeries names the series of a workload such as "Deployment/web".
+func WorkloadSeries(namespace string, workload string) string {
+	return "workload/" + namespace + "/" + workload
+}
+
+// ContainerSeries names the series of a container of a workload. It holds the
+// largest usage of the container among the pods of the workload, the one its
+// requests have to fit.
+func ContainerSeries(namespace string, workload string, container string) string {
+	return "container/" + namespace + "/" + workload + "/" + container
+}
+
+// Store persists usage samples in a directory, so trends outlive a session.
+// New samples are appended to a head file as they come. Once an hour is over
+// its samples are averaged to one per minute, keeping the peak of each minute
+// too, and compacted into a gzipped block file. Blocks older than the
+// retention are deleted.
+//
+// Everything within the retention is also kept in memory for queries, as
+// columns of times and values per series, so each series key is held once
+// however many samples it has.
+//
+// A directory should only be used by one Store at a time. It is safe for
+// concurrent use.
+type Store struct {
+	mu        sync.Mutex
+	dir       string
+	retention time.Duration
+	head      *os.File
+	series    map[string]*samples // by series key
+	headHours map[int64]bool      // the hours with samples in the head
+	blocks    map[int64]bool      // the hours compacted into block files
+}
+
+// samples are the samples of one series, oldest first. Those of compacted
+// hours are one per minute, with the peak of the minute next to its average.
+type samples struct {
+	times      []int64 // unix seconds
+	cpu        []int64 // millicores
+	memory     []int64 // bytes
+	cpuPeak    []int64
+	memoryPeak []int64
+}
+
+// round is the usage of every series at one time, one line of a store file.
+type round struct {
+	Time   int64               `json:"t"` // unix seconds
+	Values map[string][2]int64 `json:"v"` // millicores and bytes by series
+	// Peaks holds the peaks of the series whose values are averages above
+	// them, in blocks.
+	Peaks map[string][2]int64 `json:"p,omitempty"`
+}
+
+// OpenStore opens the store in dir, creating it if needed, and compacts the
+// samples left in the head by earlier sessions. Blocks that cannot be read
+// are skipped.
+func OpenStore(dir string, retention time.Duration) (*Store, error) {
+	return openStore(dir, retention, time.Now())
+}
+
+func openStore(dir string, retention time.Duration, now time.Time) (*Store, error) {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create metrics store %s: %v", dir, err)
+	}
+	s := &Store{dir: dir, retention: retention, series: make(map[string]*samples), headHours: make(map[int64]bool), blocks: make(map[int64]bool)}
+
+	paths, err := filepath.Glob(filepath.Join(dir, blockPattern))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		var hour int64
+		if _, err := fmt.Sscanf(filepath.Base(path), "block-%d.jsonl.gz", &hour); err != nil {
+			continue
+		}
+		rounds, err := readBlock(path)
+		if err != nil {
+			utils.Warn(fmt.Sprintf("Skipping metrics block: %v", err))
+			continue
+		}
+		for _, r := range rounds {
+			s.add(r)
+		}
+		s.blocks[hour] = true
+	}
+
+	rounds, err := readHead(filepath.Join(dir, headFile))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rounds {
+		s.add(r)
+		s.headHours[hourOf(r.Time)] = true
+	}
+	if err := s.compact(now); err != nil {
+		return nil, err
+	}
+	if s.head == nil {
+		if err := s.openHead(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Retention returns how long samples are kept.
+func (s *Store) Retention() time.Duration {
+	return s.retention
+}
+
+// Record appends the usage of nodes, pods, their workloads and the containers
+// of the workloads at time at. Workloads are stored as the sum of their pods,
+// so their trend spans pod restarts and rollouts.
+func (s *Store) Record(at time.Time, nodes []kubernetes.NodeUsage, pods []kubernetes.PodUsage) error {
+	r := round{Time: at.Unix(), Values: make(map[string][2]int64, len(nodes)+len(pods))}
+	for _, node := range nodes {
+		r.Values[NodeSeries(node.Name)] = [2]int64{node.CPU, node.Memory}
+	}
+	for _, pod := range pods {
+		r.Values[PodSeries(pod.Pod)] = [2]int64{pod.CPU, pod.Memory}
+		if pod.Workload == "" {
+			continue
+		}
+		key := WorkloadSeries(pod.Pod.Namespace, pod.Workload)
+		total := r.Values[key]
+		r.Values[key] = [2]int64{total[0] + pod.CPU, total[1] + pod.Memory}
+		for _, container := range pod.Containers {
+			key := ContainerSeries(pod.Pod.Namespace, pod.Workload, container.Name)
+			largest := r.Values[key]
+			r.Values[key] = [2]int64{max(largest[0], container.CPU), max(largest[1], container.Memory)}
+		}
+	}
+	if len(r.Values) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for hour := range s.headHours {
+		if hour < hourOf(r.Time) {
+			if err := s.compact(at); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := s.head.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write metrics store: %v", err)
+	}
+	s.add(r)
+	s.headHours[hourOf(r.Time)] = true
+	return nil
+}
+
+// Series returns the samples of a series since the given time, averaged over
+// periods of step, oldest first.
+func (s *Store) Series(key string, since time.Time, step time.Duration) []kubernetes.Usage {
+	averages, _ := s.query(key, since, step)
+	return averages
+}
+
+// Peaks returns the peaks of a series since the given time over periods of
+// step, oldest first. Unlike the averages of Series, they include the peaks
+// within the minutes compacted into blocks.
+func (s *Store) Peaks(key string, since time.Time, step time.Duration) []kubernetes.Usage {
+	_, peaks := s.query(key, since, step)
+	return peaks
+}
+
+// query returns the averages and the peaks of a series since the given time
+// over periods of step.
+func (s *Store) query(key string, since time.Time, step time.Duration) ([]kubernetes.Usage, []kubernetes.Usage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.series[key]
+	if !ok {
+		return nil, nil
+	}
+	stepSeconds := max(int64(step/time.Second), 1)
+	var averages, peaks []kubernetes.Usage
+	var cpu, memory, count int64
+	flush := func() {
+		if count > 0 {
+			last := &averages[len(averages)-1]
+			last.CPU, last.Memory = cpu/count, memory/count
+		}
+		cpu, memory, count = 0, 0, 0
+	}
+	for i := stored.search(since.Unix()); i < len(stored.times); i++ {
+		bucket := stored.times[i] - stored.times[i]%stepSeconds
+		if len(averages) == 0 || averages[len(averages)-1].Time.Unix() != bucket {
+			flush()
+			averages = append(averages, kubernetes.Usage{Time: time.Unix(bucket, 0)})
+			peaks = append(peaks, kubernetes.Usage{Time: time.Unix(bucket, 0)})
+		}
+		cpu += stored.cpu[i]
+		memory += stored.memory[i]
+		count++
+		peak := &peaks[len(peaks)-1]
+		peak.CPU, peak.Memory = max(peak.CPU, stored.cpuPeak[i]), max(peak.Memory, stored.memoryPeak[i])
+	}
+	flush()
+	return averages, peaks
+}
+
+// Close closes the head file.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.head == nil {
+		return nil
+	}
+	err := s.head.Close()
+	s.head = nil
+	return err
+}
+
+// add adds the values of r to their series.
+func (s *Store) add(r round) {
+	for key, value := range r.Values {
+		series, ok := s.series[key]
+		if !ok {
+			series = &samples{}
+			s.series[key] = series
+		}
+		peak, ok := r.Peaks[key]
+		if !ok {
+			peak = value
+		}
+		series.insert(r.Time, value, peak)
+	}
+}
+
+// compact moves the samples of the hours before now from the head into
+// blocks and deletes what is older than the retention.
+func (s *Store) compact(now time.Time) error {
+	current := hourOf(now.Unix())
+	cutoff := now.Add(-s.retention).Unix()
+
+	finished := false
+	for hour := range s.headHours {
+		if hour >= current {
+			continue
+		}
+		finished = true
+		delete(s.headHours, hour)
+		if hour+blockSpan <= cutoff {
+			continue
+		}
+		for _, series := range s.series {
+			series.downsample(hour, hour+blockSpan)
+		}
+		if err := writeBlock(s.blockPath(hour), s.rounds(hour, hour+blockSpan)); err != nil {
+			return err
+		}
+		s.blocks[hour] = true
+	}
+	for hour := range s.blocks {
+		if hour+blockSpan <= cutoff {
+			if err := os.Remove(s.blockPath(hour)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to delete expired metrics: %v", err)
+			}
+			delete(s.blocks, hour)
+		}
+	}
+	for key, series := range s.series {
+		if series.trim(hourOf(cutoff)) {
+			delete(s.series, key)
+		}
+	}
+
+	if !finished {
+		return nil
+	}
+	return s.rewriteHead(current)
+}
+
+// rewriteHead replaces the head file with the samples from the hour current
+// on.
+func (s *Store) rewriteHead(current int64) error {
+	if s.head != nil {
+		s.head.Close()
+		s.head = nil
+	}
+	path := filepath.Join(s.dir, headFile)
+	err := writeFile(path, func(file *os.File) error {
+		writer := bufio.NewWriter(file)
+		encoder := json.NewEncoder(writer)
+		for _, r := range s.rounds(current, math.MaxInt64) {
+			if err := encoder.Encode(r); err != nil {
+				return err
+			}
+		}
+		return writer.Flush()
+	})
+	if err != nil {
+		return err
+	}
+	return s.openHead()
+}
+
+// rounds gathers the samples of every series from from until to by time.
+func (s *Store) rounds(from int64, to int64) []round {
+	byTime := make(map[int64]*round)
+	for key, series := range s.series {
+		for i := series.search(from); i < len(series.times) && series.times[i] < to; i++ {
+			r, ok := byTime[series.times[i]]
+			if !ok {
+				r = &round{Time: series.times[i], Values: make(map[string][2]int64)}
+				byTime[series.times[i]] = r
+			}
+			value, peak := [2]int64{series.cpu[i], series.memory[i]}, [2]int64{series.cpuPeak[i], series.memoryPeak[i]}
+			r.Values[key] = value
+			if peak != value {
+				if r.Peaks == nil {
+					r.Peaks = make(map[string][2]int64)
+				}
+				r.Peaks[key] = peak
+			}
+		}
+	}
+	rounds := make([]round, 0, len(byTime))
+	for _, r := range byTime {
+		rounds = append(rounds, *r)
+	}
+	sort.Slice(rounds, func(i, j int) bool { return rounds[i].Time < rounds[j].Time })
+	return rounds
+}
+
+func (s *Store) openHead() error {
+	file, err := os.OpenFile(filepath.Join(s.dir, headFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open metrics store: %v", err)
+	}
+	s.head = file
+	return nil
+}
+
+func (s *Store) blockPath(hour int64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("block-%d.jsonl.gz", hour))
+}
+
+func hourOf(unix int64) int64 {
+	return unix - unix%blockSpan
+}
+
+// search returns the index of the first sample at or after t.
+func (c *samples) search(t int64) int {
+	return sort.Search(len(c.times), func(i int) bool { return c.times[i] >= t })
+}
+
+// insert adds a sample after those at or before t, which is the end unless
+// samples arrive out of order.
+func (c *samples) insert(t int64, value [2]int64, peak [2]int64) {
+	i := len(c.times)
+	if i > 0 && c.times[i-1] > t {
+		i = sort.Search(len(c.times), func(j int) bool { return c.times[j] > t })
+	}
+	c.times = slices.Insert(c.times, i, t)
+	c.cpu = slices.Insert(c.cpu, i, value[0])
+	c.memory = slices.Insert(c.memory, i, value[1])
+	c.cpuPeak = slices.Insert(c.cpuPeak, i, peak[0])
+	c.memoryPeak = slices.Insert(c.memoryPeak, i, peak[1])
+}
+
+// downsample averages the samples from from until to to one per
+// blockResolution, keeping their peaks.
+func (c *samples) downsample(from int64, to int64) {
+	i, j := c.search(from), c.search(to)
+	if i == j {
+		return
+	}
+	var times, cpu, memory, cpuPeak, memoryPeak []int64
+	for k := i; k < j; {
+		bucket := c.times[k] - c.times[k]%blockResolution
+		var cpuSum, memorySum, count, cpuMax, memoryMax int64
+		for ; k < j && c.times[k] < bucket+blockResolution; k++ {
+			cpuSum += c.cpu[k]
+			memorySum += c.memory[k]
+			count++
+			cpuMax, memoryMax = max(cpuMax, c.cpuPeak[k]), max(memoryMax, c.memoryPeak[k])
+		}
+		times = append(times, bucket)
+		cpu = append(cpu, cpuSum/count)
+		memory = append(memory, memorySum/count)
+		cpuPeak = append(cpuPeak, cpuMax)
+		memoryPeak = append(memoryPeak, memoryMax)
+	}
+	c.times = slices.Replace(c.times, i, j, times...)
+	c.cpu = slices.Replace(c.cpu, i, j, cpu...)
+	c.memory = slices.Replace(c.memory, i, j, memory...)
+	c.cpuPeak = slices.Replace(c.cpuPeak, i, j, cpuPeak...)
+	c.memoryPeak = slices.Replace(c.memoryPeak, i, j, memoryPeak...)
+}
+
+// trim drops the samples before t and reports whether none are left.
+func (c *samples) trim(t int64) bool {
+	if i := c.search(t); i > 0 {
+		c.times = slices.Delete(c.times, 0, i)
+		c.cpu = slices.Delete(c.cpu, 0, i)
+		c.memory = slices.Delete(c.memory, 0, i)
+		c.cpuPeak = slices.Delete(c.cpuPeak, 0, i)
+		c.memoryPeak = slices.Delete(c.memoryPeak, 0, i)
+	}
+	return len(c.times) == 0
+}
+
+// readHead reads the head file. A line cut short by a crash is skipped.
+func readHead(path string) ([]round, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics store: %v", err)
+	}
+	defer file.Close()
+
+	var rounds []round
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		var r round
+		if json.Unmarshal(scanner.Bytes(), &r) == nil {
+			rounds = append(rounds, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read metrics store: %v", err)
+	}
+	sort.SliceStable(rounds, func(i, j int) bool { return rounds[i].Time < rounds[j].Time })
+	return rounds, nil
+}
+
+func readBlock(path string) ([]round, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics block: %v", err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics block %s: %v", path, err)
+	}
+	defer reader.Close()
+
+	var rounds []round
+	decoder := json.NewDecoder(reader)
+	for decoder.More() {
+		var r round
+		if err := decoder.Decode(&r); err != nil {
+			return nil, fmt.Errorf("failed to read metrics block %s: %v", path, err)
+		}
+		rounds = append(rounds, r)
+	}
+	return rounds, nil
+}
+
+func writeBlock(path string, rounds []round) error {
+	return writeFile(path, func(file *os.File) error {
+		writer := gzip.NewWriter(file)
+		encoder := json.NewEncoder(writer)
+		for _, r := range rounds {
+			if err := encoder.Encode(r); err != nil {
+				return err
+			}
+		}
+		return writer.Close()
+	})
+}
+
+// writeFile writes path through a temporary file renamed into place, so a
+// crash never leaves a partial file behind.
+func writeFile(path string, write func(*os.File) error) error {
+	file, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write metrics store: %v", err)
+	}
+	defer os.Remove(file.Name())
+
+	err = write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write metrics store: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+)
+
+func recordPods(t *testing.T, store *Store, at time.Time, usages ...kubernetes.PodUsage) {
+	t.Helper()
+	if err := store.Record(at, nil, usages); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+}
+
+func TestStoreSumsWorkloadsAndSurvivesReopening(t *testing.T) {
+	dir := t.TempDir()
+	store, err := openStore(dir, DefaultRetention, start)
+	if err != nil {
+		t.Fatalf("openStore: %v", err)
+	}
+	// One of the two pods of the Deployment is replaced halfway through.
+	for i := 0; i < 8; i++ {
+		name := "web-1"
+		if i >= 4 {
+			name = "web-2"
+		}
+		recordPods(t, store, start.Add(time.Duration(i)*15*time.Second),
+			kubernetes.PodUsage{Pod: kubernetes.Pod{Name: name, Namespace: "default"}, Workload: "Deployment/web", Usage: kubernetes.Usage{CPU: 100, Memory: 1 << 20}},
+			kubernetes.PodUsage{Pod: kubernetes.Pod{Name: "web-0", Namespace: "default"}, Workload: "Deployment/web", Usage: kubernetes.Usage{CPU: 20, Memory: 1 << 20}})
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	store, err = openStore(dir, DefaultRetention, start.Add(2*time.Minute))
+	if err != nil {
+		t.Fatalf("reopening: %v", err)
+	}
+	defer store.Close()
+	series := store.Series(WorkloadSeries("default", "Deployment/web"), start, time.Minute)
+	if len(series) != 2 {
+		t.Fatalf("workload series = %v, want two one-minute averages", series)
+	}
+	for _, usage := range series {
+		if usage.CPU != 120 || usage.Memory != 2<<20 {
+			t.Errorf("workload usage = %+v, want the sum of its pods", usage)
+		}
+	}
+	if got := store.Series(PodSeries(kubernetes.Pod{Name: "web-2", Namespace: "default"}), start, 15*time.Second); len(got) != 4 {
+		t.Errorf("pod series has %d samples, want 4", len(got))
+	}
+}
+
+func TestStoreCompactsFinishedHoursAndExpiresOldBlocks(t *testing.T) {
+	dir := t.TempDir()
+	store, err := openStore(dir, 2*time.Hour, start)
+	if err != nil {
+		t.Fatalf("openStore: %v", err)
+	}
+	defer store.Close()
+
+	// Four hours of samples, two per minute.
+	for i := 0; i < 4*120; i++ {
+		at := start.Add(time.Duration(i) * 30 * time.Second)
+		if err := store.Record(at, []kubernetes.NodeUsage{{Name: "node-a", Usage: kubernetes.Usage{CPU: int64(i % 2 * 100)}}}, nil); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	blocks, _ := filepath.Glob(filepath.Join(dir, blockPattern))
+	if len(blocks) != 2 {
+		t.Errorf("store has %d blocks, want the 2 finished hours within the retention: %v", len(blocks), blocks)
+	}
+	if reopened, err := readHead(filepath.Join(dir, headFile)); err != nil || len(reopened) != 120 {
+		t.Errorf("head holds %d samples, %v, want the 120 of the current hour", len(reopened), err)
+	}
+
+	series := store.Series(NodeSeries("node-a"), start, time.Minute)
+	if len(series) != 3*60 {
+		t.Fatalf("series has %d points, want one per minute for the last 3 hours", len(series))
+	}
+	if first, want := series[0].Time, start.Add(time.Hour); !first.Equal(want) {
+		t.Errorf("series starts at %v, want %v", first, want)
+	}
+	var values []int64
+	for _, usage := range series[:3] {
+		values = append(values, usage.CPU)
+	}
+	if want := []int64{50, 50, 50}; !reflect.DeepEqual(values, want) {
+		t.Errorf("compacted values = %v, want the minute averages %v", values, want)
+	}
+}
+
+func TestStoreKeepsPeaksOfCompactedMinutes(t *testing.T) {
+	dir := t.TempDir()
+	store, err := openStore(dir, DefaultRetention, start)
+	if err != nil {
+		t.Fatalf("openStore: %v", err)
+	}
+	// Four samples a minute for an hour, one of each minute a spike.
+	for i := 0; i < 4*60; i++ {
+		cpu := int64(100)
+		if i%4 == 0 {
+			cpu = 500
+		}
+		if err := store.Record(start.Add(time.Duration(i)*15*time.Second), []kubernetes.NodeUsage{{Name: "node-a", Usage: kubernetes.Usage{CPU: cpu}}}, nil); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Reopening after the hour compacts it into a block.
+	store, err = openStore(dir, DefaultRetention, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("reopening: %v", err)
+	}
+	defer store.Close()
+	if blocks, _ := filepath.Glob(filepath.Join(dir, blockPattern)); len(blocks) != 1 {
+		t.Fatalf("store has %d blocks, want 1", len(blocks))
+	}
+	averages := store.Series(NodeSeries("node-a"), start, time.Minute)
+	peaks := store.Peaks(NodeSeries("node-a"), start, time.Minute)
+	if len(averages) != 60 || len(peaks) != 60 {
+		t.Fatalf("store has %d averages and %d peaks, want one per minute", len(averages), len(peaks))
+	}
+	if averages[0].CPU != 200 || peaks[0].CPU != 500 {
+		t.Errorf("first minute = %dm average, %dm peak, want 200m and 500m", averages[0].CPU, peaks[0].CPU)
+	}
+	if hourly := store.Peaks(NodeSeries("node-a"), start, time.Hour); len(hourly) != 1 || hourly[0].CPU != 500 {
+		t.Errorf("hourly peaks = %+v, want 500m", hourly)
+	}
+}
+
+func TestStoreSkipsTruncatedHeadLine(t *testing.T) {
+	dir := t.TempDir()
+	head := `{"t":1729252800,"v":{"node/node-a":[100,0]}}` + "\n" + `{"t":1729252815,"v":{"node/no`
+	if err := os.WriteFile(filepath.Join(dir, headFile), []byte(head), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := openStore(dir, DefaultRetention, start)
+	if err != nil {
+		t.Fatalf("openStore: %v", err)
+	}
+	defer store.Close()
+	if got := store.Series(NodeSeries("node-a"), start, time.Minute); len(got) != 1 || got[0].CPU != 100 {
+		t.Errorf("series = %v, want the complete sample", got)
+	}
+}
+
+func TestStoreSkipsUnreadableBlock(t *testing.T) {
+	dir := t.TempDir()
+	store, err := openStore(dir, DefaultRetention, start)
+	if err != nil {
+		t.Fatalf("openStore: %v", err)
+	}
+	for i := 0; i < 2*60; i++ {
+		at := start.Add(time.Duration(i) * time.Minute)
+		if err := store.Record(at, []kubernetes.NodeUsage{{Name: "node-a", Usage: kubernetes.Usage{CPU: 100}}}, nil); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	broken := filepath.Join(dir, fmt.Sprintf("block-%d.jsonl.gz", hourOf(start.Unix())-blockSpan))
+	if err := os.WriteFile(broken, []byte("not gzip"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = openStore(dir, DefaultRetention, start.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("openStore with an unreadable block: %v", err)
+	}
+	defer store.Close()
+	if got := store.Series(NodeSeries("node-a"), start, time.Hour); len(got) != 2 || got[0].CPU != 100 || got[1].CPU != 100 {
+		t.Errorf("series = %+v, want the two readable hours", got)
+	}
+}
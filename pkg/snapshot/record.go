@@ -0,0 +1,148 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// recordedLogLines and recordedLogBytes bound the logs kept for each pod.
+	recordedLogLines = 500
+	recordedLogBytes = 256 * 1024
+
+	// logWorkers is the number of pods whose logs are fetched in parallel.
+	logWorkers = 8
+)
+
+// Record captures the pods, nodes, namespaces, events, resource quotas and
+// metrics of every namespace the client can see, and the recent logs of every
+// pod. A user who may not list pods in every namespace gets a snapshot of the
+// current namespace only, as recorded in Scope. Only a failure to list pods
+// is fatal; anything else that cannot be read is listed in the snapshot's
+// warnings.
+func Record(client *kubernetes.Client) (*Snapshot, error) {
+	ctx := context.TODO()
+	clientset := client.Clientset()
+	metrics := client.MetricsClient().MetricsV1beta1()
+	snapshot := &Snapshot{
+		Version:    formatVersion,
+		CapturedAt: time.Now().UTC(),
+		Context:    client.ContextName(),
+		User:       client.UserName(),
+		Namespace:  client.Namespace(),
+	}
+
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if apierrors.IsForbidden(err) {
+		snapshot.Scope = client.Namespace()
+		snapshot.Warnings = append(snapshot.Warnings, fmt.Sprintf("only namespace %s was recorded: %s", snapshot.Scope, kubernetes.DescribeError(err)))
+		pods, err = clientset.CoreV1().Pods(snapshot.Scope).List(ctx, metav1.ListOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+	snapshot.Pods = pods.Items
+
+	if namespaces, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{}); err != nil {
+		snapshot.warn("namespaces", err)
+	} else {
+		snapshot.Namespaces = namespaces.Items
+	}
+	if nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{}); err != nil {
+		snapshot.warn("nodes", err)
+	} else {
+		snapshot.Nodes = nodes.Items
+	}
+	if events, err := clientset.CoreV1().Events(snapshot.Scope).List(ctx, metav1.ListOptions{}); err != nil {
+		snapshot.warn("events", err)
+	} else {
+		snapshot.Events = events.Items
+	}
+	if quotas, err := clientset.CoreV1().ResourceQuotas(snapshot.Scope).List(ctx, metav1.ListOptions{}); err != nil {
+		snapshot.warn("resource quotas", err)
+	} else {
+		snapshot.ResourceQuotas = quotas.Items
+	}
+	if info, err := clientset.Discovery().ServerVersion(); err == nil {
+		snapshot.ServerVersion = info.GitVersion
+	}
+	if nodeMetrics, err := metrics.NodeMetricses().List(ctx, metav1.ListOptions{}); err != nil {
+		snapshot.warn("node metrics", err)
+	} else {
+		snapshot.NodeMetrics = nodeMetrics.Items
+	}
+	if podMetrics, err := metrics.PodMetricses(snapshot.Scope).List(ctx, metav1.ListOptions{}); err != nil {
+		snapshot.warn("pod metrics", err)
+	} else {
+		snapshot.PodMetrics = podMetrics.Items
+	}
+
+	snapshot.Logs = recordLogs(ctx, client, snapshot.Pods)
+	return snapshot, nil
+}
+
+func (s *Snapshot) warn(what string, err error) {
+	s.Warnings = append(s.Warnings, fmt.Sprintf("%s were not recorded: %s", what, kubernetes.DescribeError(err)))
+}
+
+// recordLogs fetches the logs of pods in parallel. The result is in the order
+// of pods.
+func recordLogs(ctx context.Context, client *kubernetes.Client, pods []v1.Pod) []PodLogs {
+	logs := make([]PodLogs, len(pods))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < logWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				logs[index] = recordPodLogs(ctx, client, &pods[index])
+			}
+		}()
+	}
+	for i := range pods {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return logs
+}
+
+func recordPodLogs(ctx context.Context, client *kubernetes.Client, pod *v1.Pod) PodLogs {
+	tailLines, limitBytes := int64(recordedLogLines), int64(recordedLogBytes)
+	result := PodLogs{Namespace: pod.Namespace, Name: pod.Name}
+	stream, err := client.Clientset().CoreV1().Pods(pod.Namespace).
+		GetLogs(pod.Name, &v1.PodLogOptions{TailLines: &tailLines, LimitBytes: &limitBytes}).
+		Stream(ctx)
+	if err == nil {
+		defer stream.Close()
+		var logs []byte
+		logs, err = io.ReadAll(stream)
+		result.Logs = string(logs)
+	}
+	if err != nil {
+		var status apierrors.APIStatus
+		if !errors.As(err, &status) {
+			status = apierrors.NewInternalError(err)
+		}
+		errorStatus := status.Status()
+		result.Error = &errorStatus
+	}
+	return result
+}
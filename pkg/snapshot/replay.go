@@ -0,0 +1,218 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	fakerest "k8s.io/client-go/rest/fake"
+	k8stesting "k8s.io/client-go/testing"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+var (
+	nodeMetricsResource = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}
+	podMetricsResource  = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+)
+
+// readVerbs are the verbs the replayed user is granted. Everything else is
+// denied, so the UI greys out every action.
+var readVerbs = []string{"get", "list", "watch"}
+
+// NewClient returns a read-only KubePulse client serving the snapshot. The
+// context name shows when the snapshot was captured.
+func NewClient(s *Snapshot) (*kubernetes.Client, error) {
+	var objects []runtime.Object
+	for i := range s.Namespaces {
+		objects = append(objects, &s.Namespaces[i])
+	}
+	if len(s.Namespaces) == 0 {
+		objects = append(objects, namespacesOf(s.Pods)...)
+	}
+	for i := range s.Nodes {
+		objects = append(objects, &s.Nodes[i])
+	}
+	for i := range s.Pods {
+		objects = append(objects, &s.Pods[i])
+	}
+	for i := range s.Events {
+		objects = append(objects, &s.Events[i])
+	}
+	for i := range s.ResourceQuotas {
+		objects = append(objects, &s.ResourceQuotas[i])
+	}
+
+	metricsClient := metricsfake.NewSimpleClientset()
+	for i := range s.NodeMetrics {
+		if err := metricsClient.Tracker().Create(nodeMetricsResource, &s.NodeMetrics[i], ""); err != nil {
+			return nil, fmt.Errorf("failed to load metrics of node %s: %v", s.NodeMetrics[i].Name, err)
+		}
+	}
+	for i := range s.PodMetrics {
+		metrics := &s.PodMetrics[i]
+		if err := metricsClient.Tracker().Create(podMetricsResource, metrics, metrics.Namespace); err != nil {
+			return nil, fmt.Errorf("failed to load metrics of pod %s/%s: %v", metrics.Namespace, metrics.Name, err)
+		}
+	}
+
+	logs := make(map[string]PodLogs, len(s.Logs))
+	for _, podLogs := range s.Logs {
+		logs[podLogs.Namespace+"/"+podLogs.Name] = podLogs
+	}
+	clientset := &clientset{Clientset: fake.NewSimpleClientset(objects...), logs: logs}
+	if s.ServerVersion != "" {
+		clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: s.ServerVersion}
+	}
+	addReactors(clientset)
+
+	namespace := s.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	client := kubernetes.NewClientFromInterfaces(clientset, metricsClient, dynamicfake.NewSimpleDynamicClient(scheme.Scheme, objects...), namespace)
+	client.SetIdentity(fmt.Sprintf("%s (snapshot %s)", s.Context, s.CapturedAt.Local().Format("2006-01-02 15:04:05")), s.User)
+	client.SetReadOnly(true)
+	return client, nil
+}
+
+// namespacesOf returns the namespaces of pods, for snapshots recorded without
+// permission to list namespaces.
+func namespacesOf(pods []v1.Pod) []runtime.Object {
+	seen := make(map[string]bool)
+	var namespaces []runtime.Object
+	for _, pod := range pods {
+		if seen[pod.Namespace] {
+			continue
+		}
+		seen[pod.Namespace] = true
+		namespaces = append(namespaces, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: pod.Namespace}})
+	}
+	return namespaces
+}
+
+// addReactors grants the replayed user read access and applies the pod field
+// selectors the fake clientset ignores.
+func addReactors(clientset *clientset) {
+	clientset.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &authorizationv1.SelfSubjectRulesReview{
+			Status: authorizationv1.SubjectRulesReviewStatus{
+				ResourceRules: []authorizationv1.ResourceRule{{Verbs: readVerbs, APIGroups: []string{"*"}, Resources: []string{"*"}}},
+			},
+		}, nil
+	})
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		allowed := false
+		if attributes := review.Spec.ResourceAttributes; attributes != nil {
+			for _, verb := range readVerbs {
+				allowed = allowed || attributes.Verb == verb
+			}
+		}
+		return true, &authorizationv1.SelfSubjectAccessReview{
+			Status: authorizationv1.SubjectAccessReviewStatus{Allowed: allowed, Reason: "snapshot replay"},
+		}, nil
+	})
+	clientset.PrependReactor("list", "pods", kubernetes.ListPodsReactor(clientset.Tracker()))
+}
+
+// clientset serves the recorded logs instead of the fixed "fake logs" of the
+// fake clientset.
+type clientset struct {
+	*fake.Clientset
+	logs map[string]PodLogs
+}
+
+func (c *clientset) CoreV1() corev1client.CoreV1Interface {
+	return &coreV1{CoreV1Interface: c.Clientset.CoreV1(), logs: c.logs}
+}
+
+type coreV1 struct {
+	corev1client.CoreV1Interface
+	logs map[string]PodLogs
+}
+
+func (c *coreV1) Pods(namespace string) corev1client.PodInterface {
+	return &pods{PodInterface: c.CoreV1Interface.Pods(namespace), namespace: namespace, logs: c.logs}
+}
+
+type pods struct {
+	corev1client.PodInterface
+	namespace string
+	logs      map[string]PodLogs
+}
+
+// GetLogs returns the recorded logs of the pod, or the error recorded in
+// their place. Following is not supported; the stream ends after the
+// recorded lines.
+func (p *pods) GetLogs(name string, options *v1.PodLogOptions) *rest.Request {
+	client := &fakerest.RESTClient{
+		Client: fakerest.CreateHTTPClient(func(request *http.Request) (*http.Response, error) {
+			podLogs, ok := p.logs[p.namespace+"/"+name]
+			switch {
+			case !ok:
+				return errorResponse(apierrors.NewNotFound(v1.Resource("pods/log"), name).Status()), nil
+			case podLogs.Error != nil:
+				return errorResponse(*podLogs.Error), nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader(tail(podLogs.Logs, options))),
+			}, nil
+		}),
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		GroupVersion:         v1.SchemeGroupVersion,
+		VersionedAPIPath:     fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/log", p.namespace, name),
+	}
+	return client.Request()
+}
+
+// tail returns the last options.TailLines lines of logs.
+func tail(logs string, options *v1.PodLogOptions) string {
+	if options == nil || options.TailLines == nil {
+		return logs
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(logs, "\n"), "\n")
+	if int(*options.TailLines) >= len(lines) {
+		return logs
+	}
+	return strings.Join(lines[len(lines)-int(*options.TailLines):], "") + "\n"
+}
+
+// errorResponse encodes status as the API server would send it.
+func errorResponse(status metav1.Status) *http.Response {
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	code := int(status.Code)
+	if code == 0 {
+		code = http.StatusInternalServerError
+	}
+	encoded, _ := json.Marshal(status)
+	return &http.Response{
+		StatusCode: code,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(string(encoded))),
+	}
+}
@@ -0,0 +1,103 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+// Package snapshot records the state KubePulse sees of a cluster into an
+// archive and replays it later, without access to the cluster.
+package snapshot
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// formatVersion is incremented whenever the archive format changes in a way
+// older versions of KubePulse cannot read.
+const formatVersion = 1
+
+// Snapshot is the state of a cluster at one point in time. It is stored as
+// gzip-compressed JSON.
+type Snapshot struct {
+	Version     int                          `json:"version"`
+	CapturedAt  time.Time                    `json:"capturedAt"`
+	Context     string                       `json:"context"`
+	User        string                       `json:"user"`
+	Namespace   string                       `json:"namespace"`
+	Namespaces  []v1.Namespace               `json:"namespaces"`
+	Nodes       []v1.Node                    `json:"nodes"`
+	Pods        []v1.Pod                     `json:"pods"`
+	Events      []v1.Event                   `json:"events"`
+	NodeMetrics []metricsv1beta1.NodeMetrics `json:"nodeMetrics"`
+	PodMetrics  []metricsv1beta1.PodMetrics  `json:"podMetrics"`
+	Logs        []PodLogs                    `json:"logs"`
+	// ResourceQuotas and ServerVersion are missing from snapshots of older
+	// versions.
+	ResourceQuotas []v1.ResourceQuota `json:"resourceQuotas,omitempty"`
+	ServerVersion  string             `json:"serverVersion,omitempty"`
+	// Scope is the only namespace recorded when the user may not list pods in
+	// every namespace, and empty otherwise.
+	Scope string `json:"scope,omitempty"`
+	// Warnings lists the parts of the cluster that could not be recorded.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// PodLogs holds the most recent log lines of a pod's default container, or
+// the error the API server returned for them.
+type PodLogs struct {
+	Namespace string         `json:"namespace"`
+	Name      string         `json:"name"`
+	Logs      string         `json:"logs,omitempty"`
+	Error     *metav1.Status `json:"error,omitempty"`
+}
+
+// Save writes the snapshot to path.
+func (s *Snapshot) Save(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot %s: %v", path, err)
+	}
+	defer file.Close()
+
+	writer := gzip.NewWriter(file)
+	if err := json.NewEncoder(writer).Encode(s); err != nil {
+		return fmt.Errorf("failed to write snapshot %s: %v", path, err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot %s: %v", path, err)
+	}
+	return file.Close()
+}
+
+// Load reads a snapshot written by Save.
+func Load(path string) (*Snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot %s: %v", path, err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a KubePulse snapshot: %v", path, err)
+	}
+	defer reader.Close()
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(reader).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %v", path, err)
+	}
+	if snapshot.Version != formatVersion {
+		return nil, fmt.Errorf("snapshot %s has format version %d, this version of KubePulse reads version %d", path, snapshot.Version, formatVersion)
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,235 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/demo"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// recordDemo records a demo cluster, saves the snapshot and replays it.
+func recordDemo(t *testing.T) (live *kubernetes.Client, replayed *kubernetes.Client, snapshot *Snapshot) {
+	t.Helper()
+	clock := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	live = demo.NewCluster(func() time.Time { return clock }, 1).Client()
+
+	recorded, err := Record(live)
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "cluster.kubepulse")
+	if err := recorded.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	snapshot, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	replayed, err = NewClient(snapshot)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return live, replayed, snapshot
+}
+
+func sortedPods(t *testing.T, client *kubernetes.Client) []kubernetes.Pod {
+	t.Helper()
+	pods, err := client.GetPods()
+	if err != nil {
+		t.Fatalf("GetPods: %v", err)
+	}
+	// Decoded timestamps are in local time, so compare them in UTC.
+	for i := range pods {
+		pods[i].Created = pods[i].Created.UTC()
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	return pods
+}
+
+func TestReplayMatchesRecordedCluster(t *testing.T) {
+	live, replayed, snapshot := recordDemo(t)
+	if len(snapshot.Warnings) != 0 {
+		t.Errorf("recording the demo cluster warned: %v", snapshot.Warnings)
+	}
+
+	for _, namespace := range []string{"default", ""} {
+		live.SetNamespace(namespace)
+		replayed.SetNamespace(namespace)
+		if got, want := sortedPods(t, replayed), sortedPods(t, live); !reflect.DeepEqual(got, want) {
+			t.Errorf("replayed pods in %q = %v, want %v", namespace, got, want)
+		}
+	}
+
+	nodes, err := replayed.GetNodes()
+	if err != nil || len(nodes) != 4 {
+		t.Fatalf("GetNodes = %v, %v, want 4 nodes", nodes, err)
+	}
+	for _, node := range nodes {
+		liveCPU, liveMemory, _ := live.GetNodeMetrics(node)
+		cpu, memory, err := replayed.GetNodeMetrics(node)
+		if err != nil || cpu != liveCPU || memory != liveMemory {
+			t.Errorf("GetNodeMetrics(%s) = %s, %s, %v, want %s, %s", node, cpu, memory, err, liveCPU, liveMemory)
+		}
+		nodePods, err := replayed.GetPodsByNode(node)
+		if err != nil {
+			t.Fatalf("GetPodsByNode: %v", err)
+		}
+		for _, pod := range nodePods {
+			if pod.NodeName != node {
+				t.Errorf("GetPodsByNode(%s) returned %s on %s", node, pod.Name, pod.NodeName)
+			}
+		}
+	}
+
+	namespaces, err := replayed.ListNamespaces()
+	if err != nil || len(namespaces) != 5 {
+		t.Errorf("ListNamespaces = %v, %v, want 5 namespaces", namespaces, err)
+	}
+
+	if summary, err := replayed.GetClusterSummary(); err != nil || summary.ServerVersion != "v1.31.1" {
+		t.Errorf("replayed server version = %q, %v, want v1.31.1", summary.ServerVersion, err)
+	}
+
+	liveSummaries, err := live.ListNamespaceSummaries()
+	if err != nil {
+		t.Fatalf("ListNamespaceSummaries: %v", err)
+	}
+	summaries, err := replayed.ListNamespaceSummaries()
+	if err != nil || len(summaries) != len(liveSummaries) {
+		t.Fatalf("replayed ListNamespaceSummaries = %v, %v, want %d namespaces", summaries, err, len(liveSummaries))
+	}
+	for i, summary := range summaries {
+		if want := liveSummaries[i]; summary.Name != want.Name || summary.Pods() != want.Pods() || summary.Quota != want.Quota {
+			t.Errorf("replayed summary of %s = %+v, want %+v", summary.Name, summary, want)
+		}
+	}
+}
+
+func TestReplayServesRecordedLogs(t *testing.T) {
+	live, replayed, _ := recordDemo(t)
+	live.SetNamespace("")
+
+	var running, pending int
+	for _, pod := range sortedPods(t, live) {
+		liveLogs, liveErr := live.GetPodLogs(pod)
+		logs, err := replayed.GetPodLogs(pod)
+		switch {
+		case liveErr == nil:
+			running++
+			if err != nil || logs != liveLogs {
+				t.Errorf("replayed logs of %s differ: %v", pod.Name, err)
+			}
+		case apierrors.IsBadRequest(liveErr):
+			pending++
+			if !apierrors.IsBadRequest(err) || err.Error() != liveErr.Error() {
+				t.Errorf("replayed log error of %s = %v, want %v", pod.Name, err, liveErr)
+			}
+		}
+	}
+	if running == 0 || pending == 0 {
+		t.Errorf("compared %d pods with logs and %d without, want both", running, pending)
+	}
+}
+
+func TestReplayIsReadOnly(t *testing.T) {
+	_, replayed, snapshot := recordDemo(t)
+	if !replayed.ReadOnly() {
+		t.Errorf("replayed client is not read-only")
+	}
+	if replayed.CanI("delete", kubernetes.PodResource, "", "default") {
+		t.Errorf("replayed client may delete pods")
+	}
+	if !replayed.CanI("list", kubernetes.PodResource, "", "default") {
+		t.Errorf("replayed client may not list pods")
+	}
+	pod := kubernetes.Pod{Name: snapshot.Pods[0].Name, Namespace: snapshot.Pods[0].Namespace}
+	if err := replayed.DeletePod(pod, false); err != kubernetes.ErrReadOnly {
+		t.Errorf("DeletePod = %v, want %v", err, kubernetes.ErrReadOnly)
+	}
+}
+
+func TestReplayShowsRecordedEvents(t *testing.T) {
+	pod := v1.Pod{}
+	pod.Namespace, pod.Name = "default", "web"
+	event := v1.Event{Reason: "BackOff", Message: "Back-off restarting failed container", Type: v1.EventTypeWarning}
+	event.Namespace, event.Name = "default", "web.1"
+	event.InvolvedObject = v1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "web"}
+
+	replayed, err := NewClient(&Snapshot{Version: formatVersion, Context: "prod", Pods: []v1.Pod{pod}, Events: []v1.Event{event}})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	details, err := replayed.GetPodDetails(kubernetes.Pod{Name: "web", Namespace: "default"})
+	if err != nil {
+		t.Fatalf("GetPodDetails: %v", err)
+	}
+	if !strings.Contains(details, "BackOff") || !strings.Contains(details, "Back-off restarting failed container") {
+		t.Errorf("GetPodDetails does not show the recorded event:\n%s", details)
+	}
+	if namespaces, _ := replayed.ListNamespaces(); !reflect.DeepEqual(namespaces, []string{"default"}) {
+		t.Errorf("ListNamespaces = %v, want the namespaces of the recorded pods", namespaces)
+	}
+}
+
+func TestRecordFallsBackToTheNamespace(t *testing.T) {
+	clock := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	live := demo.NewCluster(func() time.Time { return clock }, 1).Client()
+	// The user may only list pods and events in their namespace.
+	onlyInNamespace := func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "" {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: action.GetResource().Resource}, "", errors.New("denied"))
+		}
+		return false, nil, nil
+	}
+	// The demo clientset wraps a fake one, whose reactors it promotes.
+	clientset := live.Clientset().(interface {
+		PrependReactor(verb string, resource string, reaction k8stesting.ReactionFunc)
+	})
+	clientset.PrependReactor("list", "pods", onlyInNamespace)
+	clientset.PrependReactor("list", "events", onlyInNamespace)
+
+	recorded, err := Record(live)
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if recorded.Scope != "default" || len(recorded.Warnings) != 1 || !strings.Contains(recorded.Warnings[0], "only namespace default was recorded") {
+		t.Errorf("scope = %q, warnings = %v, want only default recorded", recorded.Scope, recorded.Warnings)
+	}
+	if len(recorded.Pods) == 0 {
+		t.Fatal("no pods were recorded")
+	}
+	for _, pod := range recorded.Pods {
+		if pod.Namespace != "default" {
+			t.Errorf("recorded pod %s/%s outside the namespace", pod.Namespace, pod.Name)
+		}
+	}
+}
+
+func TestLoadRejectsOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plain.json")
+	if err := os.WriteFile(path, []byte(`{"version":1}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Errorf("Load accepted an uncompressed file")
+	}
+}
@@ -0,0 +1,20 @@
+#!/bin/bash
+
+# Run from the repository root regardless of where the script is called from
+cd "$(dirname "$0")/.." || exit 1
+
+echo "Running go vet..."
+go vet ./...
+if [ $? -ne 0 ]; then
+    echo "go vet reported problems!"
+    exit 1
+fi
+
+echo "Running tests..."
+go test -race -count=1 "$@" ./...
+if [ $? -ne 0 ]; then
+    echo "Tests failed!"
+    exit 1
+fi
+
+echo "All tests passed."
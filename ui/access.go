@@ -0,0 +1,49 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/utils"
+)
+
+// requireAccess reports whether the current user may perform verb on resource
+// in namespace, explaining in the status bar why not.
+func (controller *UIController) requireAccess(verb string, resource kubernetes.APIResource, subresource string, namespace string) bool {
+	if controller.KubernetesClient.CanI(verb, resource, subresource, namespace) {
+		return true
+	}
+	errorMessage := kubernetes.AccessDeniedMessage(verb, resource, subresource, namespace)
+	utils.Warn(errorMessage)
+	controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+	return false
+}
+
+// instruction returns the status bar text of an action, greyed out when the
+// current user may not perform it or it would change a read-only cluster.
+// Access is checked in the background, redrawing the status bar once known.
+func (controller *UIController) instruction(text string, verb string, resource kubernetes.APIResource, subresource string) string {
+	client := controller.KubernetesClient
+	if client.ReadOnly() && verb != "get" && verb != "list" && verb != "watch" {
+		return "[gray]" + text + "[-]"
+	}
+	if client.CachedCanI(verb, resource, subresource, client.Namespace(), controller.accessChecked) {
+		return text
+	}
+	return "[gray]" + text + "[-]"
+}
+
+// accessChecked redraws the status bar with the outcome of an access check
+// that finished in the background, unless it shows a message by now.
+func (controller *UIController) accessChecked() {
+	controller.Application.QueueUpdateDraw(func() {
+		if controller.UIManager.StatusBar.GetText(false) == controller.statusMessage {
+			controller.updateStatusBar()
+		}
+	})
+}
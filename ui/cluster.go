@@ -0,0 +1,115 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/ui/panels"
+	"github.com/rdmnl/kubepulse/utils"
+	"github.com/rivo/tview"
+)
+
+// clusterSummaryInterval is how often the cluster health in the header is
+// refreshed. It lists every node, pod and recent warning, so it runs less
+// often than the metrics sampler.
+const clusterSummaryInterval = 30 * time.Second
+
+// StartClusterSummary refreshes the cluster health in the header each
+// clusterSummaryInterval. It blocks, so run it in its own goroutine.
+func (controller *UIController) StartClusterSummary() {
+	ticker := time.NewTicker(clusterSummaryInterval)
+	defer ticker.Stop()
+
+	for {
+		summary, err := controller.KubernetesClient.GetClusterSummary()
+		if err != nil {
+			utils.Warn(fmt.Sprintf("Error summarizing the cluster: %v", err))
+		}
+		controller.Application.QueueUpdateDraw(func() {
+			controller.showClusterSummary(summary, err)
+		})
+		<-ticker.C
+	}
+}
+
+// showClusterSummary keeps the latest cluster summary, or the error that
+// prevented it, and shows it in the header.
+func (controller *UIController) showClusterSummary(summary kubernetes.ClusterSummary, err error) {
+	controller.clusterSummary = &summary
+	controller.clusterSummaryErr = err
+	controller.updateHeader()
+}
+
+// clusterHealth describes the nodes, pods, usage and warnings of the cluster
+// for the second line of the header.
+func (controller *UIController) clusterHealth() string {
+	if controller.clusterSummaryErr != nil {
+		return "[red]Cluster health unavailable: " + tview.Escape(kubernetes.DescribeError(controller.clusterSummaryErr)) + "[-]"
+	}
+	summary := controller.clusterSummary
+	if summary == nil {
+		return "[gray]Loading cluster health...[-]"
+	}
+
+	nodeColor := "green"
+	if summary.ReadyNodes < summary.Nodes {
+		nodeColor = "red"
+	}
+	parts := []string{
+		fmt.Sprintf("Nodes [%s]%d/%d ready[-]", nodeColor, summary.ReadyNodes, summary.Nodes),
+		"Pods " + describePodCounts(summary.PodCounts),
+	}
+	if summary.UsageAvailable {
+		parts = append(parts,
+			"CPU "+describeAllocation(summary.Usage.CPU, summary.Allocatable.CPU, kubernetes.FormatCPU),
+			"Memory "+describeAllocation(summary.Usage.Memory, summary.Allocatable.Memory, utils.FormatBytes))
+	} else {
+		parts = append(parts, "CPU [gray]n/a[-]", "Memory [gray]n/a[-]")
+	}
+	// Warnings are those of the last hour.
+	warnings := fmt.Sprintf("Warnings [gray]%d[-]", summary.Warnings)
+	if summary.Warnings > 0 {
+		warnings = fmt.Sprintf("Warnings [orange]%d[-]", summary.Warnings)
+	}
+	return strings.Join(append(parts, warnings), " | ")
+}
+
+// describePodCounts lists the pods by phase, leaving out empty phases other
+// than running, and completed pods, which say nothing about health.
+func describePodCounts(counts kubernetes.PodCounts) string {
+	parts := []string{fmt.Sprintf("[green]%d running[-]", counts.Running)}
+	for _, phase := range []struct {
+		count int
+		name  string
+		color string
+	}{
+		{counts.Pending, "pending", "yellow"},
+		{counts.Failed, "failed", "red"},
+		{counts.Unknown, "unknown", "orange"},
+	} {
+		if phase.count > 0 {
+			parts = append(parts, fmt.Sprintf("[%s]%d %s[-]", phase.color, phase.count, phase.name))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeAllocation shows usage against allocatable, colored like a limit in
+// the pod table.
+func describeAllocation(usage int64, allocatable int64, format func(int64) string) string {
+	percent, ok := kubernetes.Percent(usage, allocatable)
+	if !ok {
+		return format(usage)
+	}
+	color := panels.PercentColor(percent, true)
+	return fmt.Sprintf("%s/%s [%s]%d%%[-]", format(usage), format(allocatable), color.Name(), percent)
+}
@@ -0,0 +1,54 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+)
+
+func TestHeaderShowsClusterHealth(t *testing.T) {
+	client := newFakeClient()
+	client.cluster = kubernetes.ClusterSummary{
+		ServerVersion:  "v1.31.1",
+		Nodes:          3,
+		ReadyNodes:     2,
+		PodCounts:      kubernetes.PodCounts{Running: 40, Pending: 2, Failed: 1},
+		Usage:          kubernetes.Resources{CPU: 7200, Memory: 12 << 30},
+		UsageAvailable: true,
+		Allocatable:    kubernetes.Resources{CPU: 8000, Memory: 32 << 30},
+		Warnings:       5,
+	}
+	ui := newTestUI(t, client)
+	ui.assertContains("KubePulse 0.1.0 | Context: test-context", "Loading cluster health...")
+
+	ui.syncDraw(func() {
+		summary, err := client.GetClusterSummary()
+		ui.controller.showClusterSummary(summary, err)
+	})
+	ui.assertContains(
+		"KubePulse 0.1.0 | Context: test-context | Kubernetes v1.31.1",
+		"Nodes 2/3 ready | Pods 40 running, 2 pending, 1 failed | CPU 7200m/8000m 90% | Memory 12.0Gi/32.0Gi 37% | Warnings 5",
+	)
+
+	// Without metrics the usage is unknown rather than zero.
+	ui.syncDraw(func() {
+		summary := client.cluster
+		summary.Usage, summary.UsageAvailable = kubernetes.Resources{}, false
+		ui.controller.showClusterSummary(summary, nil)
+	})
+	ui.assertContains("Nodes 2/3 ready | Pods 40 running, 2 pending, 1 failed | CPU n/a | Memory n/a | Warnings 5")
+
+	// A failed refresh replaces the health, not the context line.
+	ui.syncDraw(func() {
+		ui.controller.showClusterSummary(kubernetes.ClusterSummary{}, errors.New("connection refused"))
+	})
+	ui.assertContains("KubePulse 0.1.0 | Context: test-context", "Cluster health unavailable: connection refused")
+}
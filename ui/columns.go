@@ -0,0 +1,225 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/config"
+	"github.com/rdmnl/kubepulse/ui/panels"
+	"github.com/rdmnl/kubepulse/utils"
+	"github.com/rivo/tview"
+)
+
+// view returns the layout of the table name, nil when it is not configured.
+func (controller *UIController) view(name string) *config.TableView {
+	return controller.Config.Views[name]
+}
+
+// focusedView returns the name of the pod or node table when it has focus.
+func (controller *UIController) focusedView() (string, bool) {
+	switch {
+	case controller.UIManager.PodListPanel.HasFocus():
+		return config.PodsView, true
+	case controller.UIManager.NodeListPanel.HasFocus():
+		return config.NodesView, true
+	}
+	return "", false
+}
+
+// HandleSortColumn sorts the focused table by the visible column step columns
+// away from the current sort column, in ascending order.
+func (controller *UIController) HandleSortColumn(step int) {
+	name, ok := controller.focusedView()
+	if !ok {
+		return
+	}
+	view := controller.Config.View(name)
+	var sortable []string
+	for _, column := range panels.TableColumns(name, view) {
+		if column.Visible && column.Sortable {
+			sortable = append(sortable, column.Name)
+		}
+	}
+	if len(sortable) == 0 {
+		return
+	}
+
+	next := 0
+	if current := slices.Index(sortable, view.SortBy); current >= 0 {
+		next = (current + step + len(sortable)) % len(sortable)
+	} else if step < 0 {
+		next = len(sortable) - 1
+	}
+	view.SortBy, view.Descending = sortable[next], false
+	controller.applyView(name)
+}
+
+// HandleSortInvert reverses the order of the focused table.
+func (controller *UIController) HandleSortInvert() {
+	name, ok := controller.focusedView()
+	if !ok {
+		return
+	}
+	view := controller.Config.View(name)
+	if view.SortBy == "" {
+		controller.UIManager.StatusBar.SetText("[yellow]Choose a sort column with '<' or '>' first")
+		return
+	}
+	view.Descending = !view.Descending
+	controller.applyView(name)
+}
+
+// HandleWideToggle shows or hides the wide columns of the focused table.
+func (controller *UIController) HandleWideToggle() {
+	name, ok := controller.focusedView()
+	if !ok {
+		return
+	}
+	view := controller.Config.View(name)
+	view.Wide = !view.Wide
+	controller.applyView(name)
+}
+
+// HandleColumnEditor opens a list of the columns of the focused table where
+// they can be shown, hidden, moved and sorted by. Changes apply immediately
+// and are saved once the editor closes.
+func (controller *UIController) HandleColumnEditor() {
+	name, ok := controller.focusedView()
+	if !ok {
+		return
+	}
+	view := controller.Config.View(name)
+
+	table := tview.NewTable().SetSelectable(true, false)
+	table.SetBackgroundColor(tcell.ColorBlack).
+		SetBorder(true).
+		SetBorderColor(tcell.ColorLightGreen).
+		SetTitleAlign(tview.AlignLeft)
+
+	// The editor lists the columns regardless of wide mode, so toggling it
+	// does not add the wide columns to the configured ones.
+	columns := panels.TableColumns(name, &config.TableView{Columns: view.Columns})
+	title := " Pod Columns (Space Toggle | 'J'/'K' Move | 's' Sort | 'w' Wide) "
+	if name == config.NodesView {
+		title = " Node Columns (Space Toggle | 'J'/'K' Move | 's' Sort | 'w' Wide) "
+	}
+	table.SetTitle(title)
+	render := func() {
+		table.Clear()
+		for row, column := range columns {
+			check, color := "[ ]", tcell.ColorGray
+			if column.Visible {
+				check, color = "[x]", tcell.ColorLightYellow
+			}
+			sort := ""
+			if view.SortBy == column.Name {
+				sort = "sorted ↑"
+				if view.Descending {
+					sort = "sorted ↓"
+				}
+			}
+			table.SetCell(row, 0, tview.NewTableCell(tview.Escape(check)).SetTextColor(color))
+			table.SetCell(row, 1, tview.NewTableCell(column.Name).SetTextColor(color).SetExpansion(1))
+			table.SetCell(row, 2, tview.NewTableCell(sort).SetTextColor(tcell.ColorLightCyan))
+		}
+	}
+	changed := false
+	apply := func() {
+		render()
+		controller.layoutView(name)
+		changed = true
+	}
+	// Only a change to the columns themselves lists them in the view, so the
+	// defaults keep applying until then.
+	applyColumns := func() {
+		view.Columns = nil
+		for _, column := range columns {
+			if column.Visible {
+				view.Columns = append(view.Columns, column.Name)
+			}
+		}
+		apply()
+	}
+	render()
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := table.GetSelection()
+		if row < 0 || row >= len(columns) {
+			return event
+		}
+		switch {
+		case event.Key() == tcell.KeyEnter:
+			controller.closeModal()
+		case event.Key() == tcell.KeyRune && event.Rune() == ' ':
+			// The name identifies the rows, so it stays.
+			if columns[row].Name != "name" {
+				columns[row].Visible = !columns[row].Visible
+				applyColumns()
+			}
+		case event.Key() == tcell.KeyRune && (event.Rune() == 'J' || event.Rune() == 'K'):
+			target := row + 1
+			if event.Rune() == 'K' {
+				target = row - 1
+			}
+			if target >= 0 && target < len(columns) {
+				columns[row], columns[target] = columns[target], columns[row]
+				table.Select(target, 0)
+				applyColumns()
+			}
+		case event.Key() == tcell.KeyRune && event.Rune() == 's':
+			if columns[row].Sortable {
+				view.Descending = view.SortBy == columns[row].Name && !view.Descending
+				view.SortBy = columns[row].Name
+				apply()
+			}
+		case event.Key() == tcell.KeyRune && event.Rune() == 'w':
+			view.Wide = !view.Wide
+			apply()
+		default:
+			return event
+		}
+		return nil
+	})
+
+	controller.showModal(table, 0, 0)
+	controller.modalCleanup = func() {
+		if changed {
+			controller.saveViews()
+		}
+	}
+}
+
+// applyView lays out the table name again and saves the layout of the tables
+// to the configuration.
+func (controller *UIController) applyView(name string) {
+	controller.layoutView(name)
+	controller.saveViews()
+}
+
+// layoutView lays out the table name again.
+func (controller *UIController) layoutView(name string) {
+	view := controller.view(name)
+	switch name {
+	case config.PodsView:
+		panels.LayoutPodTable(controller.UIManager.PodListPanel, view)
+	case config.NodesView:
+		panels.LayoutNodeTable(controller.UIManager.NodeListPanel, view)
+	}
+}
+
+// saveViews saves the layout of the tables to the configuration.
+func (controller *UIController) saveViews() {
+	if err := config.SaveViews(controller.ConfigPath, controller.Config.Views); err != nil {
+		errorMessage := fmt.Sprintf("Error saving the table layout: %v", err)
+		utils.Warn(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+	}
+}
@@ -0,0 +1,102 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/config"
+	"github.com/rdmnl/kubepulse/ui/panels"
+)
+
+func TestPodColumnsSortHideAndPersist(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("# kubepulse settings\nrightSizing:\n  percentile: 95\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ui := newTestUI(t, newFakeClient())
+	ui.sync(func() { ui.controller.ConfigPath = configPath })
+
+	layout := func() (headers []string, pods []string) {
+		ui.sync(func() {
+			table := ui.controller.UIManager.PodListPanel
+			for col := 0; col < table.GetColumnCount(); col++ {
+				headers = append(headers, table.GetCell(0, col).Text)
+			}
+			for row := 1; row < table.GetRowCount(); row++ {
+				if pod, ok := panels.PodAt(table, row); ok {
+					pods = append(pods, pod.Name)
+				}
+			}
+		})
+		return headers, pods
+	}
+
+	// The first sortable column is the name.
+	ui.typeRunes(">")
+	if headers, pods := layout(); headers[0] != "Pod Name↑" || !reflect.DeepEqual(pods, []string{"api-5c8f2", "web-7d4b9"}) {
+		t.Errorf("sorted by name: headers %q, pods %q", headers, pods)
+	}
+	ui.typeRunes("I")
+	if headers, pods := layout(); headers[0] != "Pod Name↓" || !reflect.DeepEqual(pods, []string{"web-7d4b9", "api-5c8f2"}) {
+		t.Errorf("sorted by name descending: headers %q, pods %q", headers, pods)
+	}
+
+	// Sort by restarts, most first, and hide the namespace.
+	ui.typeRunes("C")
+	ui.assertContains("Pod Columns", "[x] restarts", "sorted ↓")
+	for i := 0; i < 5; i++ {
+		ui.press(tcell.KeyDown)
+	}
+	ui.typeRunes("ss")
+	for i := 0; i < 4; i++ {
+		ui.press(tcell.KeyUp)
+	}
+	ui.typeRunes(" ")
+	ui.assertContains("[ ] namespace")
+	// The editor saves the layout once it closes, not on every key.
+	if data, err := os.ReadFile(configPath); err != nil || strings.Contains(string(data), "sortBy: restarts") {
+		t.Errorf("config saved while the editor is open: %s, %v", data, err)
+	}
+	ui.press(tcell.KeyEscape)
+
+	headers, pods := layout()
+	if want := []string{"Pod Name", "CPU", "Memory", "Status", "Restarts↓", "Age"}; !reflect.DeepEqual(headers[:len(want)], want) {
+		t.Errorf("headers = %q, want them to start with %q", headers, want)
+	}
+	if want := []string{"api-5c8f2", "web-7d4b9"}; !reflect.DeepEqual(pods, want) {
+		t.Errorf("pods sorted by restarts = %q, want %q", pods, want)
+	}
+
+	ui.typeRunes("w")
+	if headers, _ := layout(); !strings.Contains(strings.Join(headers, " "), "Node IP") {
+		t.Errorf("wide mode headers = %q, want the node and IP", headers)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# kubepulse settings", "percentile: 95", "sortBy: restarts", "descending: true", "wide: true"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("saved config is missing %q:\n%s", want, data)
+		}
+	}
+	saved, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("loading the saved config: %v", err)
+	}
+	if view := saved.Views[config.PodsView]; view == nil || strings.Contains(strings.Join(view.Columns, " "), "namespace") || view.SortBy != "restarts" {
+		t.Errorf("saved pod view = %+v, want it sorted by restarts without the namespace", view)
+	}
+}
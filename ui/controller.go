@@ -9,10 +9,14 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/config"
 	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/pkg/metrics"
+	"github.com/rdmnl/kubepulse/ui/panels"
 	"github.com/rdmnl/kubepulse/utils"
 	"github.com/rivo/tview"
 )
@@ -25,20 +29,65 @@ const (
 	detailShortcut             = "'d' Details"
 	filterNamespaceInstruction = "'f' Filter Namespace"
 	backInstruction            = "'b' Back"
+	commandInstruction         = "':' Command"
+	editInstruction            = "'e' Edit"
+	shellInstruction           = "'s' Shell"
+	portForwardInstruction     = "'F' Port-forward"
+	deleteInstruction          = "'D' Delete"
+	forceDeleteInstruction     = "'K' Force Delete"
+	evictInstruction           = "'E' Evict"
+	scaleInstruction           = "'S' Scale"
+	restartInstruction         = "'R' Restart"
+	pauseInstruction           = "'P' Pause/Resume"
+	historyInstruction         = "'H' History"
+	cordonInstruction          = "'c' Cordon"
+	uncordonInstruction        = "'u' Uncordon"
+	drainInstruction           = "'r' Drain"
+	yamlInstruction            = "'y' YAML"
+	managedFieldsInstruction   = "'m' Managed Fields"
+	searchInstruction          = "'/' Search"
+	nextMatchInstruction       = "'N' Next Match"
+	columnsInstruction         = "'C' Columns"
+	filterInstruction          = "'/' Filter"
 )
 
 type UIController struct {
 	UIManager        *UIManager
 	Application      *tview.Application
 	KubernetesClient kubernetes.KubernetesClient
+	Config           *config.Config
+	ConfigPath       string // where table layouts are saved, nothing is saved when empty
+	Protection       *config.ContextProtection
+	Metrics          *metrics.History
+	MetricsStore     *metrics.Store // nil unless usage is kept across sessions
+	podDetails       string         // summary of the pod in the details panel, below its usage chart
+	modalOpen        bool
+	modalCleanup     func()
+
+	// clusterSummary is the latest cluster health shown in the header, nil
+	// until it has been fetched.
+	clusterSummary    *kubernetes.ClusterSummary
+	clusterSummaryErr error
+
+	// filters narrow the pod and node tables, keyed by config.PodsView and
+	// config.NodesView.
+	filters map[string]tableFilter
+
+	// statusMessage is the instructions last shown in the status bar, to
+	// tell them from messages.
+	statusMessage string
 }
 
-func NewUIController(app *tview.Application, uiManager *UIManager, client kubernetes.KubernetesClient) *UIController {
+func NewUIController(app *tview.Application, uiManager *UIManager, client kubernetes.KubernetesClient, cfg *config.Config) *UIController {
 	controller := &UIController{
 		Application:      app,
 		UIManager:        uiManager,
 		KubernetesClient: client,
+		Config:           cfg,
+		Protection:       cfg.ProtectionFor(client.ContextName()),
+		Metrics:          metrics.NewHistory(metrics.DefaultCapacity),
 	}
+	controller.updateHeader()
 
 	// TODO temporarily disable for lagging problem
 	// go controller.startPeriodicUpdate()
@@ -69,7 +118,7 @@ func (controller *UIController) setPanelFocus(panelIndex int) {
 
 	controller.UIManager.CurrentPanel = panelIndex
 	panels := []tview.Primitive{
-		controller.UIManager.PodListPanel,  // Index 0
+		controller.mainListPanel(),         // Index 0
 		controller.UIManager.NodeListPanel, // Index 1
 		controller.UIManager.DetailsPanel,  // Index 2
 		controller.UIManager.LogsViewPanel, // Index 3
@@ -87,27 +136,19 @@ func (controller *UIController) HandlePodSelection() {
 		utils.Warn(fmt.Sprintf("Selected row index %d is out of bounds", row))
 		return
 	}
-	selectedPod := controller.UIManager.PodListPanel.GetCell(row, 0).Text
-	selectedNamespace := controller.UIManager.PodListPanel.GetCell(row, 1).Text
-	if selectedPod == "" {
-		utils.Warn("Selected pod name is empty")
+	pod, ok := panels.PodAt(controller.UIManager.PodListPanel, row)
+	if !ok {
+		utils.Warn("Selected row is not a pod")
 		return
 	}
 
-	pod := kubernetes.Pod{
-		Name:      selectedPod,
-		Namespace: selectedNamespace,
-	}
-
-	podDetails, err := controller.KubernetesClient.GetPodDetails(pod)
-	if err != nil {
+	ref := kubernetes.ObjectRef{Resource: kubernetes.PodResource, Namespace: pod.Namespace, Name: pod.Name}
+	if err := controller.showDetails(ref); err != nil {
 		utils.Errorf("Error fetching pod details for %s/%s: %v", pod.Namespace, pod.Name, err)
 		return
 	}
 
 	controller.UIManager.SelectedPod = fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
-	controller.UIManager.DetailsPanel.Clear()
-	controller.UIManager.DetailsPanel.SetText(podDetails)
 
 	controller.Application.SetFocus(controller.UIManager.DetailsPanel)
 	controller.updateStatusBar()
@@ -123,25 +164,20 @@ func (controller *UIController) HandleLogView() {
 		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
 		return
 	}
-	selectedPod := controller.UIManager.PodListPanel.GetCell(row, 0).Text
-	selectedNamespace := controller.UIManager.PodListPanel.GetCell(row, 1).Text
-	if selectedPod == "" {
-		errorMessage := "Selected pod name is empty"
+	pod, ok := panels.PodAt(controller.UIManager.PodListPanel, row)
+	if !ok {
+		errorMessage := "Selected row is not a pod"
 		utils.Warn(errorMessage)
 		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
 		return
 	}
 
-	pod := kubernetes.Pod{
-		Name:      selectedPod,
-		Namespace: selectedNamespace,
-	}
-
 	podLogs, err := controller.KubernetesClient.GetPodLogs(pod)
 	if err != nil {
 		errorMessage := fmt.Sprintf("Error fetching pod logs for %s/%s: %v", pod.Namespace, pod.Name, err)
 		utils.Errorf(errorMessage)
 		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		controller.UIManager.LogsViewPanel.SetText("[red]" + tview.Escape(kubernetes.DescribeAccessError(err, "get", kubernetes.PodResource, "log", pod.Namespace)))
 		return
 	}
 
@@ -177,64 +213,20 @@ func (controller *UIController) HandleNodeSelection() {
 		utils.Warn(fmt.Sprintf("Selected row index %d is out of bounds", row))
 		return
 	}
-	selectedNode := controller.UIManager.NodeListPanel.GetCell(row, 0).Text
-	if selectedNode == "" {
-		utils.Warn("Selected node name is empty")
+	selectedNode, ok := panels.NodeAt(controller.UIManager.NodeListPanel, row)
+	if !ok {
+		utils.Warn("Selected row is not a node")
 		return
 	}
 
-	pods, err := controller.KubernetesClient.GetPodsByNode(selectedNode)
-	if err != nil {
+	if err := controller.showNodePods(selectedNode); err != nil {
 		utils.Errorf("Error fetching pods for node %s: %v", selectedNode, err)
 		return
 	}
 
 	controller.UIManager.SelectedNode = selectedNode
-	controller.UIManager.PodListPanel.Clear()
-
-	controller.UIManager.PodListPanel.SetCell(0, 0, tview.NewTableCell("Pod Name").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	controller.UIManager.PodListPanel.SetCell(0, 1, tview.NewTableCell("Namespace").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	controller.UIManager.PodListPanel.SetCell(0, 2, tview.NewTableCell("CPU").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	controller.UIManager.PodListPanel.SetCell(0, 3, tview.NewTableCell("Memory").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-
-	for row, pod := range pods {
-		cpuUsage, memoryUsage, err := controller.KubernetesClient.GetPodMetrics(pod)
-		if err != nil {
-			cpuUsage, memoryUsage = "N/A", "N/A"
-			utils.Warn(fmt.Sprintf("Error fetching metrics for pod %s/%s: %v", pod.Namespace, pod.Name, err))
-		}
-
-		controller.UIManager.PodListPanel.SetCell(row+1, 0, tview.NewTableCell(pod.Name).
-			SetTextColor(tcell.ColorLightYellow).
-			SetSelectable(true).
-			SetAlign(tview.AlignLeft))
-
-		controller.UIManager.PodListPanel.SetCell(row+1, 1, tview.NewTableCell(pod.Namespace).
-			SetTextColor(tcell.ColorLightGreen).
-			SetSelectable(false).
-			SetAlign(tview.AlignLeft))
-
-		controller.UIManager.PodListPanel.SetCell(row+1, 2, tview.NewTableCell(cpuUsage).
-			SetTextColor(tcell.ColorLightGreen).
-			SetSelectable(false).
-			SetAlign(tview.AlignRight))
-
-		controller.UIManager.PodListPanel.SetCell(row+1, 3, tview.NewTableCell(memoryUsage).
-			SetTextColor(tcell.ColorLightBlue).
-			SetSelectable(false).
-			SetAlign(tview.AlignRight))
+	if err := controller.showDetails(kubernetes.ObjectRef{Resource: kubernetes.NodeResource, Name: selectedNode}); err != nil {
+		utils.Warn(fmt.Sprintf("Error fetching details for node %s: %v", selectedNode, err))
 	}
 
 	controller.Application.SetFocus(controller.UIManager.PodListPanel)
@@ -243,6 +235,31 @@ func (controller *UIController) HandleNodeSelection() {
 	utils.Info(fmt.Sprintf("Displayed pods for node: %s", selectedNode))
 }
 
+// showNodePods fills the pod list with the pods scheduled on nodeName.
+func (controller *UIController) showNodePods(nodeName string) error {
+	pods, err := controller.listPods(nodeName)
+	if err != nil {
+		controller.showPodList()
+		panels.ShowTableMessage(controller.UIManager.PodListPanel, kubernetes.DescribeAccessError(err, "list", kubernetes.PodResource, "", ""))
+		return err
+	}
+
+	controller.showPodList()
+	panels.FillPodTable(controller.UIManager.PodListPanel, controller.KubernetesClient, controller.Metrics, controller.view(config.PodsView), pods)
+	return nil
+}
+
+// refreshPods reloads the pod list, keeping it scoped to the selected node.
+func (controller *UIController) refreshPods() {
+	if controller.UIManager.SelectedNode == "" {
+		controller.updatePodList()
+		return
+	}
+	if err := controller.showNodePods(controller.UIManager.SelectedNode); err != nil {
+		utils.Warn(fmt.Sprintf("Error fetching pods for node %s: %v", controller.UIManager.SelectedNode, err))
+	}
+}
+
 func (controller *UIController) HandleNamespaceFilter() {
 	form := tview.NewForm()
 
@@ -263,189 +280,53 @@ func (controller *UIController) HandleNamespaceFilter() {
 			if namespace == "" {
 				namespace = "default"
 			}
-			controller.KubernetesClient.SetNamespace(namespace)
-			controller.updatePodList()
-			controller.updatePodTable()
-			controller.Application.SetRoot(controller.UIManager.Layout, true)
+			controller.closeModal()
+			controller.switchNamespace(namespace)
 		}).
 		AddButton("Cancel", func() {
-			controller.Application.SetRoot(controller.UIManager.Layout, true)
+			controller.closeModal()
 		})
 
-	modal := tview.NewFlex().
-		SetDirection(tview.FlexRow).
-		AddItem(nil, 0, 1, false).
-		AddItem(form, 10, 1, true).
-		AddItem(nil, 0, 1, false)
-
 	form.SetBorder(true).
 		SetTitle("Select Namespace").
 		SetTitleAlign(tview.AlignCenter)
 
-	controller.Application.SetRoot(modal, true)
-
+	controller.showModal(form, 0, 10)
 }
 
 func (controller *UIController) updatePodTable() {
-	pods, err := controller.KubernetesClient.GetPods()
+	pods, err := controller.listPods("")
 	if err != nil {
 		utils.Warn(fmt.Sprintf("Error fetching pods: %v", err))
 		controller.UIManager.StatusBar.SetText("[red]Error fetching pods")
+		panels.ShowTableMessage(controller.UIManager.PodListPanel, kubernetes.DescribeAccessError(err, "list", kubernetes.PodResource, "", controller.KubernetesClient.Namespace()))
 		return
 	}
 
-	controller.UIManager.PodListPanel.Clear()
-
-	controller.UIManager.PodListPanel.SetCell(0, 0, tview.NewTableCell("Pod Name").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	controller.UIManager.PodListPanel.SetCell(0, 1, tview.NewTableCell("Namespace").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	controller.UIManager.PodListPanel.SetCell(0, 2, tview.NewTableCell("CPU").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	controller.UIManager.PodListPanel.SetCell(0, 3, tview.NewTableCell("Memory").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-
-	for row, pod := range pods {
-		cpuUsage, memoryUsage, err := controller.KubernetesClient.GetPodMetrics(pod)
-		if err != nil {
-			cpuUsage = "N/A"
-			memoryUsage = "N/A"
-			utils.Warn(fmt.Sprintf("Error fetching metrics for pod %s/%s: %v", pod.Namespace, pod.Name, err))
-		}
-
-		controller.UIManager.PodListPanel.SetCell(row+1, 0, tview.NewTableCell(pod.Name).
-			SetTextColor(tcell.ColorLightYellow).
-			SetSelectable(true).
-			SetAlign(tview.AlignLeft))
-
-		controller.UIManager.PodListPanel.SetCell(row+1, 1, tview.NewTableCell(pod.Namespace).
-			SetTextColor(tcell.ColorLightGreen).
-			SetSelectable(false).
-			SetAlign(tview.AlignLeft))
-
-		controller.UIManager.PodListPanel.SetCell(row+1, 2, tview.NewTableCell(cpuUsage).
-			SetTextColor(tcell.ColorLightGreen).
-			SetSelectable(false).
-			SetAlign(tview.AlignRight))
-
-		controller.UIManager.PodListPanel.SetCell(row+1, 3, tview.NewTableCell(memoryUsage).
-			SetTextColor(tcell.ColorLightBlue).
-			SetSelectable(false).
-			SetAlign(tview.AlignRight))
-	}
-
+	panels.FillPodTable(controller.UIManager.PodListPanel, controller.KubernetesClient, controller.Metrics, controller.view(config.PodsView), pods)
 	controller.updateStatusBar()
 }
 
 func (controller *UIController) updateNodeList() {
-	nodes, err := controller.KubernetesClient.GetNodes()
+	nodes, err := controller.listNodes()
 	if err != nil {
 		controller.UIManager.StatusBar.SetText("[red]Error fetching nodes")
+		panels.ShowTableMessage(controller.UIManager.NodeListPanel, kubernetes.DescribeAccessError(err, "list", kubernetes.NodeResource, "", ""))
 		return
 	}
 
-	controller.UIManager.NodeListPanel.Clear()
-
-	controller.UIManager.NodeListPanel.SetCell(0, 0, tview.NewTableCell("Node Name").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	controller.UIManager.NodeListPanel.SetCell(0, 1, tview.NewTableCell("CPU").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	controller.UIManager.NodeListPanel.SetCell(0, 2, tview.NewTableCell("Memory").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-
-	for row, node := range nodes {
-		cpuUsage, memoryUsage, err := controller.KubernetesClient.GetNodeMetrics(node)
-		if err != nil {
-			cpuUsage = "N/A"
-			memoryUsage = "N/A"
-		}
-
-		controller.UIManager.NodeListPanel.SetCell(row+1, 0, tview.NewTableCell(node).
-			SetTextColor(tcell.ColorLightYellow).
-			SetSelectable(true))
-		controller.UIManager.NodeListPanel.SetCell(row+1, 1, tview.NewTableCell(cpuUsage).
-			SetTextColor(tcell.ColorLightGreen).
-			SetAlign(tview.AlignRight))
-		controller.UIManager.NodeListPanel.SetCell(row+1, 2, tview.NewTableCell(memoryUsage).
-			SetTextColor(tcell.ColorLightBlue).
-			SetAlign(tview.AlignRight))
-	}
+	panels.FillNodeTable(controller.UIManager.NodeListPanel, controller.KubernetesClient, controller.Metrics, controller.view(config.NodesView), nodes)
 }
 
 func (controller *UIController) updatePodList() {
-	pods, err := controller.KubernetesClient.GetPods()
+	pods, err := controller.listPods("")
 	if err != nil {
 		utils.Warn(fmt.Sprintf("Error fetching pods: %v", err))
 		controller.UIManager.StatusBar.SetText("[red]Error fetching pods")
 		return
 	}
 
-	controller.UIManager.PodListPanel.Clear()
-
-	controller.UIManager.PodListPanel.SetCell(0, 0, tview.NewTableCell("Pod Name").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	controller.UIManager.PodListPanel.SetCell(0, 1, tview.NewTableCell("Namespace").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	controller.UIManager.PodListPanel.SetCell(0, 2, tview.NewTableCell("CPU").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	controller.UIManager.PodListPanel.SetCell(0, 3, tview.NewTableCell("Memory").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-
-	for row, pod := range pods {
-		if pod.Name == "" {
-			continue
-		}
-
-		cpuUsage, memoryUsage, err := controller.KubernetesClient.GetPodMetrics(pod)
-		if err != nil {
-			cpuUsage = "N/A"
-			memoryUsage = "N/A"
-			utils.Warn(fmt.Sprintf("Error fetching metrics for pod %s/%s: %v", pod.Namespace, pod.Name, err))
-		}
-
-		controller.UIManager.PodListPanel.SetCell(row+1, 0, tview.NewTableCell(pod.Name).
-			SetTextColor(tcell.ColorLightYellow).
-			SetSelectable(true).
-			SetAlign(tview.AlignLeft))
-
-		controller.UIManager.PodListPanel.SetCell(row+1, 1, tview.NewTableCell(pod.Namespace).
-			SetTextColor(tcell.ColorLightGreen).
-			SetSelectable(false).
-			SetAlign(tview.AlignLeft))
-
-		controller.UIManager.PodListPanel.SetCell(row+1, 2, tview.NewTableCell(cpuUsage).
-			SetTextColor(tcell.ColorLightGreen).
-			SetSelectable(false).
-			SetAlign(tview.AlignRight))
-
-		controller.UIManager.PodListPanel.SetCell(row+1, 3, tview.NewTableCell(memoryUsage).
-			SetTextColor(tcell.ColorLightBlue).
-			SetSelectable(false).
-			SetAlign(tview.AlignRight))
-	}
-
+	panels.FillPodTable(controller.UIManager.PodListPanel, controller.KubernetesClient, controller.Metrics, controller.view(config.PodsView), pods)
 	controller.updateStatusBar()
 }
 
@@ -458,7 +339,7 @@ func (controller *UIController) updateFocusIndicator() {
 	}
 
 	panels := []*tview.Table{
-		controller.UIManager.PodListPanel,
+		controller.mainListPanel(),
 		controller.UIManager.NodeListPanel,
 	}
 	textPanels := []*tview.TextView{
@@ -467,15 +348,35 @@ func (controller *UIController) updateFocusIndicator() {
 	}
 
 	panelTitles := [4]string{" Pods ", " Nodes ", " Detail ", " Logs "}
+	if resource := controller.UIManager.ActiveResource; resource != nil {
+		panelTitles[0] = fmt.Sprintf(" %s ", resource.Kind)
+	}
+	if controller.isYAMLView() {
+		panelTitles[2] = " YAML "
+	}
+
+	// A filtered table shows its filter even when it does not have focus.
+	var filterTitles [2]string
+	if controller.UIManager.ActiveResource == nil {
+		filterTitles[0] = controller.filterTitle(config.PodsView)
+	}
+	filterTitles[1] = controller.filterTitle(config.NodesView)
 
 	for i, panel := range panels {
+		title := ""
+		if filterTitles[i] != "" {
+			title = " " + filterTitles[i] + " "
+		}
 		if i == controller.UIManager.CurrentPanel {
 			panel.SetBorderColor(tcell.ColorLightGreen)
-			panel.SetTitle(panelTitles[i])
+			if title != "" {
+				title = "|" + title
+			}
+			title = panelTitles[i] + title
 		} else {
 			panel.SetBorderColor(tcell.ColorGray)
-			panel.SetTitle("")
 		}
+		panel.SetTitle(title)
 	}
 
 	for i, panel := range textPanels {
@@ -496,35 +397,112 @@ func (controller *UIController) getSelectedPod() (kubernetes.Pod, error) {
 	if row < 1 || row >= controller.UIManager.PodListPanel.GetRowCount() {
 		return kubernetes.Pod{}, fmt.Errorf("selected row index %d is out of bounds", row)
 	}
-	selectedPod := controller.UIManager.PodListPanel.GetCell(row, 0).Text
-	selectedNamespace := controller.UIManager.PodListPanel.GetCell(row, 1).Text
-	if selectedPod == "" {
-		return kubernetes.Pod{}, fmt.Errorf("selected pod name is empty")
+	pod, ok := panels.PodAt(controller.UIManager.PodListPanel, row)
+	if !ok {
+		return kubernetes.Pod{}, fmt.Errorf("selected row is not a pod")
+	}
+	return pod, nil
+}
+
+// getSelectedObject returns the object the focused panel points at: the
+// selected row of a list, or the object shown in the details panel.
+func (controller *UIController) getSelectedObject() (kubernetes.ObjectRef, error) {
+	switch {
+	case controller.UIManager.DetailsPanel.HasFocus():
+		if controller.UIManager.DetailsObject == nil {
+			return kubernetes.ObjectRef{}, fmt.Errorf("no object selected")
+		}
+		return *controller.UIManager.DetailsObject, nil
+	case controller.UIManager.NodeListPanel.HasFocus():
+		row, _ := controller.UIManager.NodeListPanel.GetSelection()
+		if row < 1 || row >= controller.UIManager.NodeListPanel.GetRowCount() {
+			return kubernetes.ObjectRef{}, fmt.Errorf("selected row index %d is out of bounds", row)
+		}
+		node, ok := panels.NodeAt(controller.UIManager.NodeListPanel, row)
+		if !ok {
+			return kubernetes.ObjectRef{}, fmt.Errorf("selected row is not a node")
+		}
+		return kubernetes.ObjectRef{Resource: kubernetes.NodeResource, Name: node}, nil
+	case controller.UIManager.ResourceListPanel.HasFocus():
+		row, _ := controller.UIManager.ResourceListPanel.GetSelection()
+		if row < 1 || row >= controller.UIManager.ResourceListPanel.GetRowCount() {
+			return kubernetes.ObjectRef{}, fmt.Errorf("selected row index %d is out of bounds", row)
+		}
+		resourceRow, ok := controller.UIManager.ResourceListPanel.GetCell(row, 0).GetReference().(kubernetes.ResourceRow)
+		if !ok || resourceRow.Name == "" {
+			return kubernetes.ObjectRef{}, fmt.Errorf("selected resource name is empty")
+		}
+		return kubernetes.ObjectRef{Resource: *controller.UIManager.ActiveResource, Namespace: resourceRow.Namespace, Name: resourceRow.Name}, nil
+	default:
+		pod, err := controller.getSelectedPod()
+		if err != nil {
+			return kubernetes.ObjectRef{}, err
+		}
+		return kubernetes.ObjectRef{Resource: kubernetes.PodResource, Namespace: pod.Namespace, Name: pod.Name}, nil
 	}
-	return kubernetes.Pod{
-		Name:      selectedPod,
-		Namespace: selectedNamespace,
-	}, nil
 }
 
 func (controller *UIController) updateStatusBar() {
 	statusMessage := controller.getStatusBarMessage(controller.UIManager.CurrentPanel, controller.UIManager.SelectedPod)
 	controller.UIManager.StatusBar.SetText(statusMessage)
+	controller.statusMessage = controller.UIManager.StatusBar.GetText(false)
 	utils.Info("Status bar updated")
 }
 
 func (controller *UIController) getStatusBarMessage(panel int, selectedPod string) string {
 	switch panel {
-	case 0: // PodListPanel
-		if controller.UIManager.PodListPanel.GetRowCount() > 1 {
+	case 0: // PodListPanel or ResourceListPanel
+		if resource := controller.UIManager.ActiveResource; resource != nil && kubernetes.IsWorkload(*resource) {
+			instructions := []string{
+				quitInstruction,
+				podShortcut,
+				nodeShortcut,
+				detailShortcut,
+				controller.instruction(editInstruction, "update", *resource, ""),
+				controller.instruction(scaleInstruction, "patch", *resource, "scale"),
+				controller.instruction(restartInstruction, "patch", *resource, ""),
+			}
+			if kubernetes.IsDeployment(*resource) {
+				instructions = append(instructions,
+					controller.instruction(pauseInstruction, "patch", *resource, ""),
+					controller.instruction(historyInstruction, "list", kubernetes.ReplicaSetResource, ""))
+			}
+			return strings.Join(append(instructions, commandInstruction, backInstruction), " | ")
+		}
+		if resource := controller.UIManager.ActiveResource; resource != nil {
 			return fmt.Sprintf("%s | %s | %s | %s | %s | %s | %s",
 				quitInstruction,
 				podShortcut,
 				nodeShortcut,
 				detailShortcut,
-				logShortcut,
+				controller.instruction(editInstruction, "update", *resource, ""),
+				commandInstruction,
+				backInstruction)
+		}
+		if controller.UIManager.PodListPanel.GetRowCount() > 1 {
+			return fmt.Sprintf("%s | %s | %s | %s | %s | %s | %s | %s | %s | %s | %s | %s | %s | %s | %s | %s",
+				quitInstruction,
+				podShortcut,
+				nodeShortcut,
+				detailShortcut,
+				controller.instruction(logShortcut, "get", kubernetes.PodResource, "log"),
+				controller.instruction(editInstruction, "update", kubernetes.PodResource, ""),
+				controller.instruction(shellInstruction, "create", kubernetes.PodResource, "exec"),
+				controller.instruction(portForwardInstruction, "create", kubernetes.PodResource, "portforward"),
+				controller.instruction(deleteInstruction, "delete", kubernetes.PodResource, ""),
+				controller.instruction(forceDeleteInstruction, "delete", kubernetes.PodResource, ""),
+				controller.instruction(evictInstruction, "create", kubernetes.PodResource, "eviction"),
 				filterNamespaceInstruction,
+				filterInstruction,
+				columnsInstruction,
+				commandInstruction,
 				backInstruction)
+		} else if filter := controller.filterTitle(config.PodsView); filter != "" {
+			return fmt.Sprintf("No pods match %s. %s | %s | %s",
+				filter,
+				filterInstruction,
+				quitInstruction,
+				podShortcut)
 		} else {
 			return fmt.Sprintf("No pods available. %s | %s | %s",
 				quitInstruction,
@@ -532,12 +510,45 @@ func (controller *UIController) getStatusBarMessage(panel int, selectedPod strin
 				nodeShortcut)
 		}
 	case 1: // NodeListPanel
-		return fmt.Sprintf("%s | %s | %s | %s",
+		return fmt.Sprintf("%s | %s | %s | %s | %s | %s | %s | %s | %s | %s",
 			quitInstruction,
 			podShortcut,
 			nodeShortcut,
+			controller.instruction(editInstruction, "update", kubernetes.NodeResource, ""),
+			controller.instruction(cordonInstruction, "patch", kubernetes.NodeResource, ""),
+			controller.instruction(uncordonInstruction, "patch", kubernetes.NodeResource, ""),
+			controller.instruction(drainInstruction, "patch", kubernetes.NodeResource, ""),
+			filterInstruction,
+			columnsInstruction,
 			backInstruction)
 	case 2: // DetailsPanel
+		if controller.isYAMLView() {
+			message := fmt.Sprintf("%s | %s | %s | %s | %s | %s | %s",
+				backInstruction,
+				yamlInstruction,
+				managedFieldsInstruction,
+				searchInstruction,
+				editInstruction,
+				podShortcut,
+				quitInstruction)
+			if controller.UIManager.SearchMatches > 0 {
+				message = fmt.Sprintf("(%d/%d) %s | %s",
+					controller.UIManager.SearchIndex+1,
+					controller.UIManager.SearchMatches,
+					nextMatchInstruction,
+					message)
+			}
+			return message
+		}
+		if controller.UIManager.DetailsObject != nil {
+			return fmt.Sprintf("%s | %s | %s | %s | %s | %s",
+				backInstruction,
+				yamlInstruction,
+				editInstruction,
+				podShortcut,
+				nodeShortcut,
+				quitInstruction)
+		}
 		if selectedPod != "" {
 			return fmt.Sprintf("%s | %s | %s | %s",
 				backInstruction,
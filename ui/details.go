@@ -0,0 +1,168 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/ui/panels"
+	"github.com/rdmnl/kubepulse/utils"
+	"github.com/rivo/tview"
+)
+
+// showDetails makes ref the object shown in the details panel and renders it.
+func (controller *UIController) showDetails(ref kubernetes.ObjectRef) error {
+	previous := controller.UIManager.DetailsObject
+	controller.UIManager.DetailsObject = &ref
+	controller.UIManager.SearchQuery = ""
+	if err := controller.renderDetails(); err != nil {
+		controller.UIManager.DetailsObject = previous
+		if previous == nil {
+			controller.UIManager.DetailsPanel.SetRegions(false).
+				SetText("[red]" + tview.Escape(kubernetes.DescribeAccessError(err, "get", ref.Resource, "", ref.Namespace)))
+		}
+		return err
+	}
+	controller.UIManager.DetailsPanel.ScrollToBeginning()
+	return nil
+}
+
+// renderDetails fills the details panel for the current object. Pods have a
+// summary view and a YAML view; every other kind is always shown as YAML.
+func (controller *UIController) renderDetails() error {
+	ref := controller.UIManager.DetailsObject
+	if ref == nil {
+		return nil
+	}
+	detailsPanel := controller.UIManager.DetailsPanel
+
+	if !controller.isYAMLView() {
+		pod := kubernetes.Pod{Name: ref.Name, Namespace: ref.Namespace}
+		podDetails, err := controller.KubernetesClient.GetPodDetails(pod)
+		if err != nil {
+			return err
+		}
+		controller.podDetails = podDetails
+		detailsPanel.SetRegions(false)
+		detailsPanel.Clear()
+		detailsPanel.SetText(controller.usageSection(pod) + podDetails)
+		return nil
+	}
+
+	manifest, err := controller.KubernetesClient.GetResourceYAML(*ref, controller.UIManager.ShowManagedFields)
+	if err != nil {
+		return err
+	}
+
+	text, matches := panels.HighlightYAML(manifest, controller.UIManager.SearchQuery)
+	controller.UIManager.SearchMatches = matches
+	controller.UIManager.SearchIndex = 0
+
+	detailsPanel.SetRegions(true)
+	detailsPanel.Clear()
+	detailsPanel.SetText(text)
+	if matches > 0 {
+		detailsPanel.Highlight("match-0").ScrollToHighlight()
+	} else {
+		detailsPanel.Highlight()
+	}
+	return nil
+}
+
+func (controller *UIController) isYAMLView() bool {
+	ref := controller.UIManager.DetailsObject
+	if ref == nil {
+		return false
+	}
+	return controller.UIManager.ShowYAML || ref.Resource.Name != kubernetes.PodResource.Name || ref.Resource.Group != ""
+}
+
+func (controller *UIController) HandleYAMLToggle() {
+	if controller.UIManager.DetailsObject == nil {
+		controller.UIManager.StatusBar.SetText("[red]No object selected")
+		return
+	}
+
+	controller.UIManager.ShowYAML = !controller.UIManager.ShowYAML
+	controller.UIManager.SearchQuery = ""
+	if err := controller.renderDetails(); err != nil {
+		errorMessage := fmt.Sprintf("Error rendering %s: %v", controller.UIManager.DetailsObject, err)
+		utils.Error(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+	controller.UIManager.DetailsPanel.ScrollToBeginning()
+	controller.updateFocusIndicator()
+	controller.updateStatusBar()
+}
+
+func (controller *UIController) HandleManagedFieldsToggle() {
+	if !controller.isYAMLView() {
+		return
+	}
+
+	controller.UIManager.ShowManagedFields = !controller.UIManager.ShowManagedFields
+	if err := controller.renderDetails(); err != nil {
+		errorMessage := fmt.Sprintf("Error rendering %s: %v", controller.UIManager.DetailsObject, err)
+		utils.Error(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+	controller.updateStatusBar()
+}
+
+func (controller *UIController) HandleDetailsSearch() {
+	if !controller.isYAMLView() {
+		return
+	}
+
+	input := tview.NewInputField().
+		SetLabel("/").
+		SetText(controller.UIManager.SearchQuery).
+		SetFieldWidth(0)
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		query := input.GetText()
+		controller.closeModal()
+		if key != tcell.KeyEnter {
+			return
+		}
+
+		controller.UIManager.SearchQuery = query
+		if err := controller.renderDetails(); err != nil {
+			errorMessage := fmt.Sprintf("Error rendering %s: %v", controller.UIManager.DetailsObject, err)
+			utils.Error(errorMessage)
+			controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+			return
+		}
+		controller.setPanelFocus(2)
+		if query != "" && controller.UIManager.SearchMatches == 0 {
+			controller.UIManager.StatusBar.SetText(fmt.Sprintf("[yellow]Pattern not found: %s", query))
+		}
+	})
+
+	input.SetBorder(true).
+		SetTitle("Search").
+		SetTitleAlign(tview.AlignLeft)
+
+	controller.showModal(input, 60, 3)
+}
+
+func (controller *UIController) HandleNextMatch() {
+	if controller.UIManager.SearchMatches == 0 {
+		return
+	}
+
+	controller.UIManager.SearchIndex = (controller.UIManager.SearchIndex + 1) % controller.UIManager.SearchMatches
+	controller.UIManager.DetailsPanel.
+		Highlight(fmt.Sprintf("match-%d", controller.UIManager.SearchIndex)).
+		ScrollToHighlight()
+	controller.updateStatusBar()
+}
@@ -0,0 +1,83 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"github.com/rivo/tview"
+)
+
+// showModal centers content on the screen in place of the main layout. A width
+// or height of 0 lets the content take a proportional share of the screen.
+func (controller *UIController) showModal(content tview.Primitive, width int, height int) {
+	widthProportion, heightProportion := 0, 0
+	if width == 0 {
+		widthProportion = 3
+	}
+	if height == 0 {
+		heightProportion = 3
+	}
+
+	column := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(content, height, heightProportion, true).
+		AddItem(nil, 0, 1, false)
+
+	modal := tview.NewFlex().
+		SetDirection(tview.FlexColumn).
+		AddItem(nil, 0, 1, false).
+		AddItem(column, width, widthProportion, true).
+		AddItem(nil, 0, 1, false)
+
+	controller.modalOpen = true
+	controller.Application.SetRoot(modal, true)
+}
+
+func (controller *UIController) closeModal() {
+	if controller.modalCleanup != nil {
+		controller.modalCleanup()
+		controller.modalCleanup = nil
+	}
+	controller.modalOpen = false
+	controller.Application.SetRoot(controller.UIManager.Layout, true)
+	controller.setPanelFocus(controller.UIManager.CurrentPanel)
+}
+
+// showMessage shows message in a dialog with the given buttons and calls done
+// with the label of the button that was pressed.
+func (controller *UIController) showMessage(title string, message string, buttons []string, done func(button string)) {
+	modal := tview.NewModal().
+		SetText(tview.Escape(message)).
+		AddButtons(buttons).
+		SetDoneFunc(func(_ int, button string) {
+			controller.closeModal()
+			if done != nil {
+				done(button)
+			}
+		})
+
+	modal.SetTitle(" " + title + " ")
+
+	controller.modalOpen = true
+	controller.Application.SetRoot(modal, true)
+}
+
+// showConfirm asks the user to confirm an action on the object called name and
+// runs confirmed only if the confirm button is pressed. Protected contexts ask
+// for the name to be typed instead.
+func (controller *UIController) showConfirm(title string, message string, confirmLabel string, name string, confirmed func()) {
+	if controller.requiresTypedName() {
+		controller.showTypedConfirm(title, message, confirmLabel, name, confirmed)
+		return
+	}
+	controller.showMessage(title, message, []string{confirmLabel, cancelButton}, func(button string) {
+		if button == confirmLabel {
+			confirmed()
+		}
+	})
+}
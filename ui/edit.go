@@ -0,0 +1,204 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/ui/panels"
+	"github.com/rdmnl/kubepulse/utils"
+	"github.com/rivo/tview"
+)
+
+const (
+	applyButton     = "Apply"
+	editAgainButton = "Edit Again"
+	cancelButton    = "Cancel"
+)
+
+func (controller *UIController) HandleEdit() {
+	if controller.KubernetesClient.ReadOnly() {
+		controller.UIManager.StatusBar.SetText("[red]Cannot edit: " + kubernetes.ErrReadOnly.Error())
+		return
+	}
+
+	ref, err := controller.getSelectedObject()
+	if err != nil {
+		errorMessage := fmt.Sprintf("Nothing to edit: %v", err)
+		utils.Warn(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+	if !controller.requireAccess("update", ref.Resource, "", ref.Namespace) {
+		return
+	}
+
+	manifest, err := controller.KubernetesClient.GetResourceYAML(ref, false)
+	if err != nil {
+		errorMessage := fmt.Sprintf("Error fetching %s: %v", ref, err)
+		utils.Error(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+
+	controller.editManifest(ref, manifest, manifest)
+}
+
+// editManifest opens manifest in the user's editor and previews the result.
+// original is the manifest as read from the cluster, used to detect no-op edits.
+func (controller *UIController) editManifest(ref kubernetes.ObjectRef, original string, manifest string) {
+	edited, err := runEditor(controller.Application, manifest)
+	if err != nil {
+		errorMessage := fmt.Sprintf("Error running editor: %v", err)
+		utils.Error(errorMessage)
+		controller.showMessage("Edit Failed", errorMessage, []string{"OK"}, nil)
+		return
+	}
+
+	if edited == original {
+		controller.UIManager.StatusBar.SetText(fmt.Sprintf("[yellow]Edit cancelled, no changes made to %s", ref))
+		return
+	}
+
+	diff, err := controller.KubernetesClient.DiffResource(ref, edited)
+	if err != nil {
+		controller.showEditError(ref, original, edited, err)
+		return
+	}
+	if diff == "" {
+		controller.UIManager.StatusBar.SetText(fmt.Sprintf("[yellow]Edit of %s results in no changes", ref))
+		return
+	}
+
+	controller.showEditDiff(ref, original, edited, diff)
+}
+
+func (controller *UIController) showEditDiff(ref kubernetes.ObjectRef, original string, edited string, diff string) {
+	diffView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(panels.HighlightDiff(diff))
+	diffView.SetBackgroundColor(tcell.ColorBlack)
+
+	form := tview.NewForm().
+		AddButton(applyButton, func() {
+			controller.closeModal()
+			controller.confirmProtected(fmt.Sprintf("Apply edit to %s", ref), ref.Name, func() {
+				controller.applyEdit(ref, original, edited)
+			})
+		}).
+		AddButton(editAgainButton, func() {
+			controller.closeModal()
+			controller.editManifest(ref, original, edited)
+		}).
+		AddButton(cancelButton, func() {
+			controller.closeModal()
+			controller.UIManager.StatusBar.SetText(fmt.Sprintf("[yellow]Edit of %s discarded", ref))
+		})
+	form.SetButtonsAlign(tview.AlignCenter)
+
+	// Keep the buttons focused but let the arrow keys scroll the diff.
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn, tcell.KeyHome, tcell.KeyEnd:
+			diffView.InputHandler()(event, nil)
+			return nil
+		}
+		return event
+	})
+
+	dialog := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(diffView, 0, 1, false).
+		AddItem(form, 3, 0, true)
+	dialog.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Server dry-run diff: %s ", ref)).
+		SetTitleAlign(tview.AlignLeft)
+
+	controller.showModal(dialog, 0, 0)
+}
+
+func (controller *UIController) applyEdit(ref kubernetes.ObjectRef, original string, edited string) {
+	if err := controller.KubernetesClient.UpdateResource(ref, edited); err != nil {
+		controller.showEditError(ref, original, edited, err)
+		return
+	}
+
+	utils.Info(fmt.Sprintf("Applied edit to %s", ref))
+	controller.UIManager.StatusBar.SetText(fmt.Sprintf("[green]%s edited", ref))
+	if details := controller.UIManager.DetailsObject; details != nil && details.Equal(ref) {
+		if err := controller.renderDetails(); err != nil {
+			utils.Warn(fmt.Sprintf("Error refreshing details for %s: %v", ref, err))
+		}
+	}
+}
+
+func (controller *UIController) showEditError(ref kubernetes.ObjectRef, original string, edited string, err error) {
+	utils.Warn(fmt.Sprintf("Edit of %s rejected: %v", ref, err))
+	message := fmt.Sprintf("Edit of %s was rejected.\n\n%s", ref, kubernetes.DescribeError(err))
+	controller.showMessage("Edit Failed", message, []string{editAgainButton, cancelButton}, func(button string) {
+		if button == editAgainButton {
+			controller.editManifest(ref, original, edited)
+		}
+	})
+}
+
+// runEditor suspends the application, opens manifest in $KUBE_EDITOR or
+// $EDITOR and returns the saved content.
+func runEditor(app *tview.Application, manifest string) (string, error) {
+	file, err := os.CreateTemp("", "kubepulse-edit-*.yaml")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(manifest); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	editor := strings.Fields(editorCommand())
+	cmd := exec.Command(editor[0], append(editor[1:], file.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	var runErr error
+	if !app.Suspend(func() { runErr = cmd.Run() }) {
+		return "", fmt.Errorf("unable to suspend the terminal UI")
+	}
+	if runErr != nil {
+		return "", fmt.Errorf("%s exited with an error: %v", editor[0], runErr)
+	}
+
+	content, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func editorCommand() string {
+	for _, variable := range []string{"KUBE_EDITOR", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(variable)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
@@ -0,0 +1,125 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/utils"
+	"github.com/rivo/tview"
+	"golang.org/x/term"
+)
+
+func (controller *UIController) HandleExec() {
+	pod, err := controller.getSelectedPodObject()
+	if err != nil {
+		errorMessage := fmt.Sprintf("Cannot exec: %v", err)
+		utils.Warn(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+	if !controller.requireAccess("create", kubernetes.PodResource, "exec", pod.Namespace) {
+		return
+	}
+
+	containers, err := controller.KubernetesClient.GetPodContainers(pod)
+	if err != nil {
+		errorMessage := fmt.Sprintf("Error fetching containers for %s/%s: %v", pod.Namespace, pod.Name, err)
+		utils.Error(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+
+	switch len(containers) {
+	case 0:
+		controller.UIManager.StatusBar.SetText(fmt.Sprintf("[red]Pod %s/%s has no containers", pod.Namespace, pod.Name))
+	case 1:
+		controller.confirmProtected(fmt.Sprintf("Shell in %s", pod.Name), pod.Name, func() {
+			controller.runShell(pod, containers[0])
+		})
+	default:
+		controller.showContainerPicker(pod, containers, func(pod kubernetes.Pod, container string) {
+			controller.confirmProtected(fmt.Sprintf("Shell in %s", pod.Name), pod.Name, func() {
+				controller.runShell(pod, container)
+			})
+		})
+	}
+}
+
+// getSelectedPodObject returns the pod selected in the focused panel, refusing
+// other kinds of objects.
+func (controller *UIController) getSelectedPodObject() (kubernetes.Pod, error) {
+	ref, err := controller.getSelectedObject()
+	if err != nil {
+		return kubernetes.Pod{}, err
+	}
+	if ref.Resource.GroupVersionResource() != kubernetes.PodResource.GroupVersionResource() {
+		return kubernetes.Pod{}, fmt.Errorf("%s is not a pod", ref)
+	}
+	return kubernetes.Pod{Name: ref.Name, Namespace: ref.Namespace}, nil
+}
+
+func (controller *UIController) showContainerPicker(pod kubernetes.Pod, containers []string, selected func(kubernetes.Pod, string)) {
+	list := tview.NewList().ShowSecondaryText(false)
+	for _, container := range containers {
+		container := container
+		list.AddItem(container, "", 0, func() {
+			controller.closeModal()
+			selected(pod, container)
+		})
+	}
+	list.SetDoneFunc(func() {
+		controller.closeModal()
+	})
+
+	list.SetBorder(true).
+		SetTitle(fmt.Sprintf("Container in %s", pod.Name)).
+		SetTitleAlign(tview.AlignCenter)
+
+	controller.showModal(list, 50, len(containers)+2)
+}
+
+// runShell suspends the application and attaches the terminal to a shell in
+// container. The UI is restored when the shell exits.
+func (controller *UIController) runShell(pod kubernetes.Pod, container string) {
+	utils.Info(fmt.Sprintf("Starting shell in %s/%s container %s", pod.Namespace, pod.Name, container))
+
+	var execErr error
+	suspended := controller.Application.Suspend(func() {
+		fd := int(os.Stdin.Fd())
+		if term.IsTerminal(fd) {
+			state, err := term.MakeRaw(fd)
+			if err != nil {
+				execErr = fmt.Errorf("failed to put terminal into raw mode: %v", err)
+				return
+			}
+			defer term.Restore(fd, state)
+		}
+
+		sizeQueue := newTerminalSizeQueue(int(os.Stdout.Fd()))
+		defer sizeQueue.Stop()
+
+		fmt.Printf("Connecting to %s/%s (%s), exit the shell to return to kubepulse...\r\n", pod.Namespace, pod.Name, container)
+		execErr = controller.KubernetesClient.ExecShell(pod, container, os.Stdin, os.Stdout, sizeQueue)
+	})
+
+	if !suspended {
+		execErr = fmt.Errorf("unable to suspend the terminal UI")
+	}
+	if execErr != nil {
+		errorMessage := fmt.Sprintf("Shell in %s/%s (%s) failed: %v", pod.Namespace, pod.Name, container, execErr)
+		utils.Error(errorMessage)
+		controller.showMessage("Exec Failed", errorMessage, []string{"OK"}, nil)
+		return
+	}
+
+	controller.UIManager.StatusBar.SetText(fmt.Sprintf("[green]Shell in %s/%s (%s) exited", pod.Namespace, pod.Name, container))
+	utils.Info(fmt.Sprintf("Shell in %s/%s container %s exited", pod.Namespace, pod.Name, container))
+}
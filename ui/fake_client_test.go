@@ -0,0 +1,243 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+)
+
+// fakeClient is an in-memory KubernetesClient for UI tests. It implements the
+// read path the main panels use; the embedded interface is nil, so tests that
+// reach any other method fail loudly instead of talking to a cluster.
+type fakeClient struct {
+	kubernetes.KubernetesClient
+
+	mu          sync.Mutex
+	namespace   string
+	nodes       []string
+	pods        []kubernetes.Pod
+	nodeMetrics map[string][2]string
+	podMetrics  map[string][2]string
+	nodeUsage   []kubernetes.NodeUsage
+	podUsage    []kubernetes.PodUsage
+	labels      map[string]map[string]string // by node name or namespace/pod
+	podFilter   kubernetes.Filter            // the filter of the last ListPods
+	summaries   []kubernetes.NamespaceSummary
+	cluster     kubernetes.ClusterSummary
+	logs        map[string]string
+	denied      map[string]bool
+	err         error
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		namespace: "default",
+		nodes:     []string{"node-a", "node-b"},
+		pods: []kubernetes.Pod{
+			{Name: "web-7d4b9", Namespace: "default", NodeName: "node-a", Status: "Running", Created: testNow.Add(-5 * time.Hour)},
+			{Name: "api-5c8f2", Namespace: "default", NodeName: "node-b", Status: "CrashLoopBackOff", Restarts: 7, Created: testNow.Add(-3 * 24 * time.Hour)},
+			{Name: "coredns-x1", Namespace: "kube-system", NodeName: "node-a", Status: "Running", Created: testNow.Add(-30 * 24 * time.Hour)},
+		},
+		nodeMetrics: map[string][2]string{
+			"node-a": {"850m", "3120Mi"},
+			"node-b": {"420m", "1980Mi"},
+		},
+		podMetrics: map[string][2]string{
+			"default/web-7d4b9":      {"120m", "256Mi"},
+			"default/api-5c8f2":      {"45m", "128Mi"},
+			"kube-system/coredns-x1": {"3m", "18Mi"},
+		},
+		logs: map[string]string{
+			"default/web-7d4b9": "listening on :8080\nGET /healthz 200\n",
+		},
+		denied: map[string]bool{},
+	}
+}
+
+func (f *fakeClient) ListNodes(filter kubernetes.Filter) ([]kubernetes.Node, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	var nodes []kubernetes.Node
+	for _, name := range f.nodes {
+		if filter.Matches(name, f.labels[name], map[string]string{"metadata.name": name}) {
+			nodes = append(nodes, kubernetes.Node{Name: name, Status: "Ready"})
+		}
+	}
+	return nodes, nil
+}
+
+// ListPods filters the pods like the API server, matching the status against
+// status.phase, and records the filter.
+func (f *fakeClient) ListPods(namespace string, filter kubernetes.Filter) ([]kubernetes.Pod, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.podFilter = filter
+	var pods []kubernetes.Pod
+	for _, pod := range f.pods {
+		fields := map[string]string{
+			"metadata.name":      pod.Name,
+			"metadata.namespace": pod.Namespace,
+			"spec.nodeName":      pod.NodeName,
+			"status.phase":       pod.Status,
+		}
+		if (namespace == "" || pod.Namespace == namespace) && filter.Matches(pod.Name, f.labels[pod.Namespace+"/"+pod.Name], fields) {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+func (f *fakeClient) GetNodes() ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	return append([]string(nil), f.nodes...), nil
+}
+
+func (f *fakeClient) GetNodeMetrics(nodeName string) (string, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	usage, ok := f.nodeMetrics[nodeName]
+	if !ok {
+		return "", "", fmt.Errorf("no metrics for node %s", nodeName)
+	}
+	return usage[0], usage[1], nil
+}
+
+func (f *fakeClient) GetPods() ([]kubernetes.Pod, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	var pods []kubernetes.Pod
+	for _, pod := range f.pods {
+		if f.namespace == "" || pod.Namespace == f.namespace {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+func (f *fakeClient) GetPodsByNode(nodeName string) ([]kubernetes.Pod, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	var pods []kubernetes.Pod
+	for _, pod := range f.pods {
+		if pod.NodeName == nodeName {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+func (f *fakeClient) GetPodMetrics(pod kubernetes.Pod) (string, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	usage, ok := f.podMetrics[pod.Namespace+"/"+pod.Name]
+	if !ok {
+		return "", "", fmt.Errorf("no metrics for pod %s/%s", pod.Namespace, pod.Name)
+	}
+	return usage[0], usage[1], nil
+}
+
+func (f *fakeClient) ListNodeUsage() ([]kubernetes.NodeUsage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]kubernetes.NodeUsage(nil), f.nodeUsage...), nil
+}
+
+func (f *fakeClient) ListPodUsage() ([]kubernetes.PodUsage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]kubernetes.PodUsage(nil), f.podUsage...), nil
+}
+
+func (f *fakeClient) GetPodDetails(pod kubernetes.Pod) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, p := range f.pods {
+		if p.Name == pod.Name && p.Namespace == pod.Namespace {
+			return fmt.Sprintf("[yellow::b]Pod Info[-::-]\n[lightcyan]Pod Name:[-] %s\n[lightcyan]Namespace:[-] %s\n[lightcyan]Node:[-] %s\n", p.Name, p.Namespace, p.NodeName), nil
+		}
+	}
+	return "", fmt.Errorf("pods %q not found", pod.Name)
+}
+
+func (f *fakeClient) GetPodLogs(pod kubernetes.Pod) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.logs[pod.Namespace+"/"+pod.Name], nil
+}
+
+func (f *fakeClient) GetResourceYAML(ref kubernetes.ObjectRef, showManagedFields bool) (string, error) {
+	return fmt.Sprintf("apiVersion: %s\nkind: %s\nmetadata:\n  name: %s\n", ref.Resource.Version, ref.Resource.Kind, ref.Name), nil
+}
+
+func (f *fakeClient) SetNamespace(namespace string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.namespace = namespace
+}
+
+func (f *fakeClient) Namespace() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.namespace
+}
+
+func (f *fakeClient) ListNamespaces() ([]string, error) {
+	return []string{"default", "kube-system"}, nil
+}
+
+func (f *fakeClient) ListNamespaceSummaries() ([]kubernetes.NamespaceSummary, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]kubernetes.NamespaceSummary(nil), f.summaries...), nil
+}
+
+func (f *fakeClient) GetClusterSummary() (kubernetes.ClusterSummary, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cluster, f.err
+}
+
+func (f *fakeClient) ContextName() string {
+	return "test-context"
+}
+
+func (f *fakeClient) ReadOnly() bool {
+	return false
+}
+
+// CanI allows everything except the verb/resource/subresource keys in denied,
+// such as "delete/pods/".
+func (f *fakeClient) CanI(verb string, resource kubernetes.APIResource, subresource string, namespace string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !f.denied[verb+"/"+resource.Name+"/"+subresource]
+}
+
+func (f *fakeClient) CachedCanI(verb string, resource kubernetes.APIResource, subresource string, namespace string, done func()) bool {
+	return f.CanI(verb, resource, subresource, namespace)
+}
@@ -0,0 +1,122 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/config"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/utils"
+	"github.com/rivo/tview"
+)
+
+// tableFilter is the filter of the pod or node table, as typed in the filter
+// bar and parsed.
+type tableFilter struct {
+	text   string
+	filter kubernetes.Filter
+}
+
+// HandleFilter opens the filter bar in place of the status bar for the
+// focused pod or node table. Enter applies the filter, an empty one clears
+// it, and Esc leaves the table as it is.
+func (controller *UIController) HandleFilter() {
+	name, ok := controller.focusedView()
+	if !ok {
+		return
+	}
+	table := controller.UIManager.PodListPanel
+	placeholder := "name !name label=value label!=value status.phase!=Running"
+	if name == config.NodesView {
+		table = controller.UIManager.NodeListPanel
+		placeholder = "name !name label=value label!=value spec.unschedulable=true"
+	}
+
+	input := tview.NewInputField().
+		SetLabel("/").
+		SetText(controller.filters[name].text).
+		SetPlaceholder(placeholder).
+		SetFieldWidth(0)
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter && key != tcell.KeyEscape {
+			return
+		}
+		layout := controller.UIManager.Layout
+		layout.RemoveItem(input)
+		layout.AddItem(controller.UIManager.StatusBar, 1, 1, false)
+		controller.Application.SetFocus(table)
+		controller.updateStatusBar()
+		if key == tcell.KeyEnter {
+			controller.applyFilter(name, input.GetText())
+		}
+	})
+
+	layout := controller.UIManager.Layout
+	layout.RemoveItem(controller.UIManager.StatusBar)
+	layout.AddItem(input, 1, 1, true)
+	controller.Application.SetFocus(input)
+}
+
+// applyFilter parses text as the filter of the table name and lists the
+// table again. An invalid filter is reported and the previous one is kept.
+func (controller *UIController) applyFilter(name string, text string) {
+	resource := kubernetes.PodResource
+	if name == config.NodesView {
+		resource = kubernetes.NodeResource
+	}
+	filter, err := kubernetes.ParseFilter(text, resource)
+	if err != nil {
+		errorMessage := fmt.Sprintf("Invalid filter: %v", err)
+		utils.Warn(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + tview.Escape(errorMessage))
+		return
+	}
+
+	if controller.filters == nil {
+		controller.filters = make(map[string]tableFilter)
+	}
+	if filter.IsEmpty() {
+		delete(controller.filters, name)
+	} else {
+		controller.filters[name] = tableFilter{text: text, filter: filter}
+	}
+
+	if name == config.NodesView {
+		controller.updateNodeList()
+	} else {
+		controller.refreshPods()
+	}
+	controller.updateFocusIndicator()
+}
+
+// filterTitle shows the filter of the table name in its title, or nothing
+// when it is not filtered.
+func (controller *UIController) filterTitle(name string) string {
+	current, ok := controller.filters[name]
+	if !ok {
+		return ""
+	}
+	return tview.Escape("/" + current.text)
+}
+
+// listPods lists the pods of the namespace, or of nodeName when it is set,
+// that pass the pod filter.
+func (controller *UIController) listPods(nodeName string) ([]kubernetes.Pod, error) {
+	filter := controller.filters[config.PodsView].filter
+	if nodeName != "" {
+		return controller.KubernetesClient.ListPods("", filter.OnNode(nodeName))
+	}
+	return controller.KubernetesClient.ListPods(controller.KubernetesClient.Namespace(), filter)
+}
+
+// listNodes lists the nodes that pass the node filter.
+func (controller *UIController) listNodes() ([]kubernetes.Node, error) {
+	return controller.KubernetesClient.ListNodes(controller.filters[config.NodesView].filter)
+}
@@ -0,0 +1,91 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/ui/panels"
+)
+
+func TestFilterPodsAndNodes(t *testing.T) {
+	client := newFakeClient()
+	client.labels = map[string]map[string]string{
+		"default/web-7d4b9": {"app": "web"},
+		"default/api-5c8f2": {"app": "api"},
+	}
+	ui := newTestUI(t, client)
+
+	pods := func() (names []string, title string) {
+		ui.sync(func() {
+			table := ui.controller.UIManager.PodListPanel
+			for row := 1; row < table.GetRowCount(); row++ {
+				if pod, ok := panels.PodAt(table, row); ok {
+					names = append(names, pod.Name)
+				}
+			}
+			title = table.GetTitle()
+		})
+		return names, title
+	}
+	filter := func(text string) {
+		ui.typeRunes("/")
+		ui.press(tcell.KeyCtrlU)
+		ui.typeRunes(text)
+		ui.press(tcell.KeyEnter)
+	}
+
+	filter("app=web")
+	if names, title := pods(); !reflect.DeepEqual(names, []string{"web-7d4b9"}) || title != " Pods | /app=web " {
+		t.Errorf("filtered by label: pods %q, title %q", names, title)
+	}
+	client.mu.Lock()
+	if selector := client.podFilter.Labels; selector != "app=web" {
+		t.Errorf("label selector sent = %q, want app=web", selector)
+	}
+	client.mu.Unlock()
+
+	filter("!wb7 status.phase!=Running")
+	if names, _ := pods(); !reflect.DeepEqual(names, []string{"api-5c8f2"}) {
+		t.Errorf("filtered by negated name and phase: pods %q, want api-5c8f2", names)
+	}
+
+	// An invalid filter keeps the previous one, and Esc keeps the table.
+	filter("app=(web")
+	ui.assertContains("Invalid filter")
+	ui.typeRunes("/zzz")
+	ui.press(tcell.KeyEscape)
+	if names, title := pods(); !reflect.DeepEqual(names, []string{"api-5c8f2"}) || title != " Pods | /!wb7 status.phase!=Running " {
+		t.Errorf("after an invalid and a cancelled filter: pods %q, title %q", names, title)
+	}
+
+	// Nodes have their own filter, and the pod filter stays in the title.
+	ui.typeRunes("n")
+	filter("b")
+	ui.assertContains("node-b", "/!wb7 status.phase!=Running")
+	var nodes []string
+	ui.sync(func() {
+		table := ui.controller.UIManager.NodeListPanel
+		for row := 1; row < table.GetRowCount(); row++ {
+			if node, ok := panels.NodeAt(table, row); ok {
+				nodes = append(nodes, node)
+			}
+		}
+	})
+	if !reflect.DeepEqual(nodes, []string{"node-b"}) {
+		t.Errorf("filtered nodes = %q, want node-b", nodes)
+	}
+
+	ui.typeRunes("p")
+	filter("")
+	if names, title := pods(); len(names) != 2 || title != " Pods " {
+		t.Errorf("cleared filter: pods %q, title %q", names, title)
+	}
+}
@@ -0,0 +1,200 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/config"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/ui/panels"
+	"github.com/rivo/tview"
+)
+
+// testNow is the time ages in the tables are measured from.
+var testNow = time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current screen contents")
+
+const (
+	screenWidth  = 120
+	screenHeight = 32
+	drawTimeout  = 5 * time.Second
+)
+
+// testUI runs the complete KubePulse UI on a simulated screen. Keys go through
+// the application's real event loop, so the global input capture, focus
+// handling and drawing behave exactly as in a terminal.
+type testUI struct {
+	t          *testing.T
+	screen     tcell.SimulationScreen
+	app        *tview.Application
+	controller *UIController
+	drawn      chan struct{}
+}
+
+func newTestUI(t *testing.T, client kubernetes.KubernetesClient) *testUI {
+	t.Helper()
+	panels.Now = func() time.Time { return testNow }
+
+	// SetScreen initializes the screen, which resets its size.
+	screen := tcell.NewSimulationScreen("UTF-8")
+	ui := &testUI{t: t, screen: screen, drawn: make(chan struct{}, 1)}
+	ui.app = tview.NewApplication().SetScreen(screen)
+	screen.SetSize(screenWidth, screenHeight)
+	ui.app.SetAfterDrawFunc(func(tcell.Screen) {
+		select {
+		case ui.drawn <- struct{}{}:
+		default:
+		}
+	})
+
+	cfg := &config.Config{}
+	uiManager, layout := SetupUILayout(ui.app, client, cfg)
+	ui.controller = NewUIController(ui.app, uiManager, client, cfg)
+	SetupNavigation(ui.app, ui.controller)
+	ui.app.SetRoot(layout, true)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := ui.app.Run(); err != nil {
+			t.Errorf("running application: %v", err)
+		}
+	}()
+	t.Cleanup(func() {
+		ui.app.Stop()
+		<-done
+	})
+
+	ui.waitForDraw()
+	return ui
+}
+
+// waitForDraw blocks until the application has finished its next redraw.
+func (ui *testUI) waitForDraw() {
+	ui.t.Helper()
+	select {
+	case <-ui.drawn:
+	case <-time.After(drawTimeout):
+		ui.t.Fatal("timed out waiting for the screen to be drawn")
+	}
+}
+
+// press sends a key to the application and waits until it has been handled.
+func (ui *testUI) press(key tcell.Key) {
+	ui.t.Helper()
+	ui.send(tcell.NewEventKey(key, 0, tcell.ModNone))
+}
+
+// typeRunes sends each rune as a separate key press.
+func (ui *testUI) typeRunes(runes string) {
+	ui.t.Helper()
+	for _, r := range runes {
+		ui.send(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+}
+
+func (ui *testUI) send(event *tcell.EventKey) {
+	ui.t.Helper()
+	// Drop a pending signal from an earlier redraw so only this key counts.
+	select {
+	case <-ui.drawn:
+	default:
+	}
+	ui.app.QueueEvent(event)
+	ui.waitForDraw()
+}
+
+// sync runs f on the event loop, which is required to inspect widgets safely.
+func (ui *testUI) sync(f func()) {
+	ui.app.QueueUpdate(f)
+}
+
+// syncDraw runs f on the event loop and waits until the screen is redrawn, for
+// changes made outside of a key press, such as a background refresh.
+func (ui *testUI) syncDraw(f func()) {
+	ui.t.Helper()
+	select {
+	case <-ui.drawn:
+	default:
+	}
+	ui.app.QueueUpdateDraw(f)
+	ui.waitForDraw()
+}
+
+// focused returns the primitive that currently has focus.
+func (ui *testUI) focused() tview.Primitive {
+	var focus tview.Primitive
+	ui.sync(func() {
+		focus = ui.app.GetFocus()
+	})
+	return focus
+}
+
+// text returns the screen contents as lines without trailing blanks.
+func (ui *testUI) text() string {
+	cells, width, height := ui.screen.GetContents()
+	lines := make([]string, height)
+	for y := 0; y < height; y++ {
+		var line strings.Builder
+		for x := 0; x < width; x++ {
+			cell := cells[y*width+x]
+			if len(cell.Runes) == 0 || cell.Runes[0] == 0 {
+				line.WriteRune(' ')
+				continue
+			}
+			line.WriteString(string(cell.Runes))
+		}
+		lines[y] = strings.TrimRight(line.String(), " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// assertContains fails unless the screen shows each of want.
+func (ui *testUI) assertContains(want ...string) {
+	ui.t.Helper()
+	screen := ui.text()
+	for _, w := range want {
+		if !strings.Contains(screen, w) {
+			ui.t.Errorf("screen does not contain %q:\n%s", w, screen)
+		}
+	}
+}
+
+// assertGolden compares the screen with testdata/<name>.golden. Run the tests
+// with -update to accept the current screen as the new golden file.
+func (ui *testUI) assertGolden(name string) {
+	ui.t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	got := ui.text()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			ui.t.Fatalf("creating testdata directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			ui.t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		ui.t.Fatalf("reading golden file (run go test ./ui -update to create it): %v", err)
+	}
+	if got != string(want) {
+		ui.t.Errorf("screen does not match %s (run go test ./ui -update to accept it):\n--- got ---\n%s--- want ---\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,82 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/audit"
+	"github.com/rdmnl/kubepulse/utils"
+	"github.com/rivo/tview"
+)
+
+const historyLimit = 500
+
+// ShowHistory lists the most recent changes recorded in the audit log. A
+// session such as a drain or a shell is listed once with its outcome.
+func (controller *UIController) ShowHistory() {
+	entries, err := controller.KubernetesClient.AuditEntries(historyLimit)
+	if err != nil {
+		errorMessage := fmt.Sprintf("Error reading audit log: %v", err)
+		utils.Warn(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+	entries = audit.Outcomes(entries)
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBackgroundColor(tcell.ColorBlack).
+		SetBorder(true).
+		SetBorderColor(tcell.ColorLightGreen).
+		SetTitle(" History (Esc Close) ").
+		SetTitleAlign(tview.AlignLeft)
+
+	headers := []string{"Time", "User", "Context", "Verb", "Resource", "Result", "Error"}
+	for col, header := range headers {
+		table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorWhite).
+			SetSelectable(false).
+			SetAlign(tview.AlignCenter))
+	}
+
+	if len(entries) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No changes recorded yet.").
+			SetTextColor(tcell.ColorGray).
+			SetSelectable(false))
+	}
+
+	for row, entry := range entries {
+		resultColor := tcell.ColorLightGreen
+		switch entry.Result {
+		case audit.ResultFailure:
+			resultColor = tcell.ColorRed
+		case audit.ResultDenied:
+			resultColor = tcell.ColorYellow
+		case audit.ResultStarted:
+			resultColor = tcell.ColorLightCyan
+		}
+
+		cells := []*tview.TableCell{
+			tview.NewTableCell(entry.Timestamp.Local().Format("2006-01-02 15:04:05")).SetTextColor(tcell.ColorLightGreen),
+			tview.NewTableCell(tview.Escape(entry.User)).SetTextColor(tcell.ColorLightCyan),
+			tview.NewTableCell(tview.Escape(entry.Context)).SetTextColor(tcell.ColorLightCyan),
+			tview.NewTableCell(tview.Escape(entry.Verb)).SetTextColor(tcell.ColorLightYellow),
+			tview.NewTableCell(tview.Escape(entry.Resource)).SetTextColor(tcell.ColorLightYellow),
+			tview.NewTableCell(entry.Result).SetTextColor(resultColor),
+			tview.NewTableCell(tview.Escape(entry.Error)).SetTextColor(tcell.ColorGray),
+		}
+		for col, cell := range cells {
+			table.SetCell(row+1, col, cell)
+		}
+	}
+
+	controller.showModal(table, 0, 0)
+}
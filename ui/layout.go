@@ -9,40 +9,52 @@ package ui
 
 import (
 	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/config"
 	"github.com/rdmnl/kubepulse/pkg/kubernetes"
 	"github.com/rdmnl/kubepulse/ui/panels"
 	"github.com/rivo/tview"
 )
 
 type UIManager struct {
-	Header        *tview.TextView
-	NodeListPanel *tview.Table
-	PodListPanel  *tview.Table
-	DetailsPanel  *tview.TextView
-	LogsViewPanel *tview.TextView
-	StatusBar     *tview.TextView
-	CurrentPanel  int
-	SelectedPod   string
-	SelectedNode  string
-	Layout        *tview.Flex
+	Header            *tview.TextView
+	NodeListPanel     *tview.Table
+	PodListPanel      *tview.Table
+	ResourceListPanel *tview.Table
+	DetailsPanel      *tview.TextView
+	LogsViewPanel     *tview.TextView
+	StatusBar         *tview.TextView
+	CurrentPanel      int
+	SelectedPod       string
+	SelectedNode      string
+	ActiveResource    *kubernetes.APIResource
+	DetailsObject     *kubernetes.ObjectRef
+	ShowYAML          bool
+	ShowManagedFields bool
+	SearchQuery       string
+	SearchMatches     int
+	SearchIndex       int
+	ListColumn        *tview.Flex
+	Layout            *tview.Flex
 }
 
-func SetupUILayout(app *tview.Application, client kubernetes.KubernetesClient) (*UIManager, *tview.Flex) {
+func SetupUILayout(app *tview.Application, client kubernetes.KubernetesClient, cfg *config.Config) (*UIManager, *tview.Flex) {
 	header := SetupHeader()
-	nodeListPanel := panels.SetupNodeListPanel(client)
-	podListPanel := panels.SetupPodListPanel(client)
+	nodeListPanel := panels.SetupNodeListPanel(client, cfg.Views[config.NodesView])
+	podListPanel := panels.SetupPodListPanel(client, cfg.Views[config.PodsView])
+	resourceListPanel := panels.SetupResourceListPanel()
 	detailsPanel := panels.SetupDetailsPanel()
 	logsViewPanel := panels.SetupLogsViewPanel()
 	statusBar := SetupStatusBar()
 
 	uiManager := &UIManager{
-		Header:        header,
-		NodeListPanel: nodeListPanel,
-		PodListPanel:  podListPanel,
-		DetailsPanel:  detailsPanel,
-		LogsViewPanel: logsViewPanel,
-		StatusBar:     statusBar,
-		CurrentPanel:  0,
+		Header:            header,
+		NodeListPanel:     nodeListPanel,
+		PodListPanel:      podListPanel,
+		ResourceListPanel: resourceListPanel,
+		DetailsPanel:      detailsPanel,
+		LogsViewPanel:     logsViewPanel,
+		StatusBar:         statusBar,
+		CurrentPanel:      0,
 	}
 
 	nodePodDetailsColumn := tview.NewFlex().
@@ -58,10 +70,11 @@ func SetupUILayout(app *tview.Application, client kubernetes.KubernetesClient) (
 
 	fullLayout := tview.NewFlex().
 		SetDirection(tview.FlexRow).
-		AddItem(uiManager.Header, 3, 1, false).
+		AddItem(uiManager.Header, headerHeight, 1, false).
 		AddItem(mainLayout, 0, 1, true).
 		AddItem(uiManager.StatusBar, 1, 1, false)
 
+	uiManager.ListColumn = nodePodDetailsColumn
 	uiManager.Layout = fullLayout
 
 	app.SetFocus(uiManager.PodListPanel)
@@ -69,12 +82,18 @@ func SetupUILayout(app *tview.Application, client kubernetes.KubernetesClient) (
 	return uiManager, fullLayout
 }
 
-// TODO Get version properly
+// Version is the version of KubePulse shown in the header.
+const Version = "0.1.0"
+
+// headerHeight fits the context and the cluster health on two lines.
+const headerHeight = 4
+
 func SetupHeader() *tview.TextView {
 	header := tview.NewTextView()
 	header.SetTextAlign(tview.AlignCenter).
-		SetText(" KubePulse - Kubernetes Cluster Monitor ").
+		SetText(" KubePulse " + Version + " ").
 		SetDynamicColors(true).
+		SetWrap(false).
 		SetTextColor(tcell.ColorLightCyan).
 		SetBackgroundColor(tcell.ColorBlack).
 		SetBorder(true)
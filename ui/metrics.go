@@ -0,0 +1,210 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rdmnl/kubepulse/pkg/config"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/pkg/metrics"
+	"github.com/rdmnl/kubepulse/ui/panels"
+	"github.com/rdmnl/kubepulse/utils"
+	"github.com/rivo/tview"
+)
+
+const (
+	// metricsInterval matches the default resolution of metrics-server.
+	metricsInterval = 15 * time.Second
+
+	chartHeight       = 3
+	defaultChartWidth = 40
+)
+
+// StartMetricsSampler records the usage of every node and pod each
+// metricsInterval and refreshes the usage on screen. It blocks, so run it in
+// its own goroutine.
+func (controller *UIController) StartMetricsSampler() {
+	ticker := time.NewTicker(metricsInterval)
+	defer ticker.Stop()
+
+	for {
+		controller.sampleMetrics()
+		controller.Application.QueueUpdateDraw(controller.refreshUsage)
+		<-ticker.C
+	}
+}
+
+// sampleMetrics adds the current usage of every node and pod to the history
+// and to the metrics store. The number of requests does not depend on how many
+// pods are shown: one for the nodes, and one for the pods of all namespaces,
+// followed by one for the current namespace when that is forbidden.
+func (controller *UIController) sampleMetrics() {
+	nodes, err := controller.KubernetesClient.ListNodeUsage()
+	if err != nil {
+		utils.Warn(fmt.Sprintf("Error sampling node metrics: %v", err))
+	} else {
+		controller.Metrics.RecordNodes(nodes)
+	}
+	pods, err := controller.KubernetesClient.ListPodUsage()
+	if err != nil {
+		utils.Warn(fmt.Sprintf("Error sampling pod metrics: %v", err))
+	} else {
+		controller.Metrics.RecordPods(pods)
+	}
+
+	if controller.MetricsStore != nil {
+		if err := controller.MetricsStore.Record(time.Now(), nodes, pods); err != nil {
+			utils.Warn(fmt.Sprintf("Error storing metrics: %v", err))
+		}
+	}
+}
+
+// refreshUsage redraws the usage columns and the usage chart of the pod shown
+// in the details panel from the history.
+func (controller *UIController) refreshUsage() {
+	panels.RefreshNodeUsage(controller.UIManager.NodeListPanel, controller.Metrics, controller.view(config.NodesView))
+	panels.RefreshPodUsage(controller.UIManager.PodListPanel, controller.Metrics, controller.view(config.PodsView))
+
+	if ref := controller.UIManager.DetailsObject; ref != nil && !controller.isYAMLView() && controller.podDetails != "" {
+		pod := kubernetes.Pod{Name: ref.Name, Namespace: ref.Namespace}
+		controller.UIManager.DetailsPanel.SetText(controller.usageSection(pod) + controller.podDetails)
+	}
+}
+
+// usageSection charts the CPU and memory history of pod for the details
+// panel, with a sparkline per container for pods with several containers,
+// followed by the right-sizing recommendations and the stored trend of its
+// workload. It is empty until the pod has been sampled.
+func (controller *UIController) usageSection(pod kubernetes.Pod) string {
+	width := defaultChartWidth
+	if _, _, panelWidth, _ := controller.UIManager.DetailsPanel.GetInnerRect(); panelWidth > 10 {
+		width = panelWidth - 4
+	}
+
+	section := ""
+	if samples := controller.Metrics.Pod(pod); len(samples) > 0 {
+		section += fmt.Sprintf("[yellow::b]Usage[-::-] [gray](%d samples over %s)[-]\n", len(samples), samples[len(samples)-1].Time.Sub(samples[0].Time).Round(time.Second))
+		latest, _ := controller.Metrics.Latest(pod)
+		section += usageCharts(samples, width, latest.Requests, latest.Limits)
+		section += controller.containerSparklines(pod, latest)
+		section += "\n"
+	}
+	return section + controller.rightSizingSection(pod) + controller.storedUsageSection(pod, width)
+}
+
+// usageCharts draws the CPU and memory charts of samples with their latest
+// and peak values, and the latest as a percentage of requests and limits.
+func usageCharts(samples []kubernetes.Usage, width int, requests kubernetes.Resources, limits kubernetes.Resources) string {
+	cpu, memory := panels.UsageSeries(samples)
+	latest := samples[len(samples)-1]
+
+	section := fmt.Sprintf("  [lightcyan]CPU:[-] %s [gray](peak %s)[-]%s\n", kubernetes.FormatCPU(latest.CPU), kubernetes.FormatCPU(panels.Peak(cpu)),
+		percentages(latest.CPU, requests.CPU, limits.CPU))
+	for _, line := range panels.Chart(cpu, width, chartHeight) {
+		section += "  [lightgreen]" + line + "[-]\n"
+	}
+	section += fmt.Sprintf("  [lightcyan]Memory:[-] %s [gray](peak %s)[-]%s\n", kubernetes.FormatMemory(latest.Memory), kubernetes.FormatMemory(panels.Peak(memory)),
+		percentages(latest.Memory, requests.Memory, limits.Memory))
+	for _, line := range panels.Chart(memory, width, chartHeight) {
+		section += "  [lightblue]" + line + "[-]\n"
+	}
+	return section
+}
+
+// containerSparklines draws a sparkline per container of pod, if it has more
+// than one, with its usage as a percentage of its requests and limits.
+func (controller *UIController) containerSparklines(pod kubernetes.Pod, latest kubernetes.PodUsage) string {
+	containers := controller.Metrics.Containers(pod)
+	if len(containers) < 2 {
+		return ""
+	}
+	nameWidth := 0
+	for _, name := range containers {
+		nameWidth = max(nameWidth, len(name))
+	}
+	resources := make(map[string]kubernetes.ContainerUsage, len(latest.Containers))
+	for _, container := range latest.Containers {
+		resources[container.Name] = container
+	}
+
+	section := ""
+	for _, name := range containers {
+		containerSamples := controller.Metrics.Container(pod, name)
+		containerCPU, containerMemory := panels.UsageSeries(containerSamples)
+		current := containerSamples[len(containerSamples)-1]
+		spec := resources[name]
+		section += fmt.Sprintf("  [green]%s[-]%s [lightgreen]%s[-] %6s%s  [lightblue]%s[-] %7s%s\n",
+			tview.Escape(name), strings.Repeat(" ", nameWidth-len(name)),
+			panels.Sparkline(containerCPU, panels.SparklineWidth), kubernetes.FormatCPU(current.CPU),
+			percentages(current.CPU, spec.Requests.CPU, spec.Limits.CPU),
+			panels.Sparkline(containerMemory, panels.SparklineWidth), kubernetes.FormatMemory(current.Memory),
+			percentages(current.Memory, spec.Requests.Memory, spec.Limits.Memory))
+	}
+	return section
+}
+
+// percentages describes usage as a percentage of request and limit, leaving
+// out those that are unset.
+func percentages(usage int64, request int64, limit int64) string {
+	var parts []string
+	if percent, ok := kubernetes.Percent(usage, request); ok {
+		parts = append(parts, fmt.Sprintf("[%s]%d%%[gray] of request", panels.PercentColor(percent, false), percent))
+	}
+	if percent, ok := kubernetes.Percent(usage, limit); ok {
+		parts = append(parts, fmt.Sprintf("[%s]%d%%[gray] of limit", panels.PercentColor(percent, true), percent))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " [gray]" + strings.Join(parts, ", ") + "[-]"
+}
+
+// storedUsageSection charts the usage of the workload owning pod over the
+// retention of the metrics store, or of the pod itself when its workload is
+// unknown. Each column averages an equal share of the retention.
+func (controller *UIController) storedUsageSection(pod kubernetes.Pod, width int) string {
+	if controller.MetricsStore == nil {
+		return ""
+	}
+	retention := controller.MetricsStore.Retention()
+	step := max(retention/time.Duration(width), time.Minute)
+
+	name, key := pod.Name, metrics.PodSeries(pod)
+	if workload := controller.Metrics.Workload(pod); workload != "" {
+		name, key = workload, metrics.WorkloadSeries(pod.Namespace, workload)
+	}
+	samples := controller.MetricsStore.Series(key, time.Now().Add(-retention), step)
+	if len(samples) < 2 {
+		return ""
+	}
+
+	section := fmt.Sprintf("[yellow::b]%s[-::-] [gray](last %s, %s averages)[-]\n", tview.Escape(name), formatSpan(retention), formatSpan(step))
+	return section + usageCharts(samples, width, kubernetes.Resources{}, kubernetes.Resources{}) + "\n"
+}
+
+// formatSpan formats a duration without the zero units time.Duration prints,
+// such as "24h" or "36m".
+func formatSpan(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d%(24*time.Hour) == 0:
+		if d == 24*time.Hour {
+			return "24h"
+		}
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d > time.Hour:
+		return fmt.Sprintf("%dh%dm", d/time.Hour, d%time.Hour/time.Minute)
+	default:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+}
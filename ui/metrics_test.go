@@ -0,0 +1,195 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/pkg/metrics"
+)
+
+func TestUsageHistoryShowsTrends(t *testing.T) {
+	client := newFakeClient()
+	ui := newTestUI(t, client)
+	web := kubernetes.Pod{Name: "web-7d4b9", Namespace: "default"}
+	start := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	// A steady pod that spikes in the last sample, on a node that climbs.
+	ui.sync(func() {
+		for i, cpu := range []int64{100, 100, 100, 100, 400} {
+			at := start.Add(time.Duration(i) * metricsInterval)
+			client.mu.Lock()
+			client.nodeUsage = []kubernetes.NodeUsage{
+				{Name: "node-a", Usage: kubernetes.Usage{Time: at, CPU: int64(200 * (i + 1)), Memory: 3 << 30}},
+			}
+			client.podUsage = []kubernetes.PodUsage{{
+				Pod:   web,
+				Usage: kubernetes.Usage{Time: at, CPU: cpu + 20, Memory: 256 << 20},
+				Containers: []kubernetes.ContainerUsage{
+					{Name: "app", Usage: kubernetes.Usage{Time: at, CPU: cpu, Memory: 200 << 20}},
+					{Name: "proxy", Usage: kubernetes.Usage{Time: at, CPU: 20, Memory: 56 << 20}},
+				},
+			}}
+			client.mu.Unlock()
+			ui.controller.sampleMetrics()
+		}
+		ui.controller.refreshUsage()
+	})
+	ui.press(tcell.KeyEnter)
+
+	ui.assertContains("node-a    1000m 3072Mi Ready    -    ▂▃▅▆█")
+	var details, podTrend string
+	ui.sync(func() {
+		details = ui.controller.UIManager.DetailsPanel.GetText(true)
+		podTrend = ui.controller.UIManager.PodListPanel.GetCell(1, 11).Text
+	})
+	if want := "   ▃▃▃▃█"; podTrend != want {
+		t.Errorf("pod CPU trend = %q, want %q", podTrend, want)
+	}
+	for _, want := range []string{
+		"Usage (5 samples over 1m0s)",
+		"CPU: 420m (peak 420m)",
+		"  ▆▆▆▆█\n",
+		"app      ▂▂▂▂█   400m",
+		"proxy    █████    20m",
+		"Pod Name: web-7d4b9",
+	} {
+		if !strings.Contains(details, want) {
+			t.Errorf("details panel is missing %q:\n%s", want, details)
+		}
+	}
+	ui.assertGolden("usage")
+}
+
+func TestPodTableShowsRequestAndLimitPercentages(t *testing.T) {
+	client := newFakeClient()
+	ui := newTestUI(t, client)
+	now := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	ui.sync(func() {
+		client.mu.Lock()
+		client.podUsage = []kubernetes.PodUsage{
+			{
+				Pod:      kubernetes.Pod{Name: "web-7d4b9", Namespace: "default"},
+				Usage:    kubernetes.Usage{Time: now, CPU: 150, Memory: 200 << 20},
+				Requests: kubernetes.Resources{CPU: 100, Memory: 256 << 20},
+				Containers: []kubernetes.ContainerUsage{
+					{Name: "app", Usage: kubernetes.Usage{Time: now, CPU: 140, Memory: 150 << 20}, Limits: kubernetes.Resources{Memory: 160 << 20}},
+					{Name: "proxy", Usage: kubernetes.Usage{Time: now, CPU: 10, Memory: 50 << 20}},
+				},
+			},
+			{
+				Pod:      kubernetes.Pod{Name: "api-5c8f2", Namespace: "default"},
+				Usage:    kubernetes.Usage{Time: now, CPU: 45, Memory: 128 << 20},
+				Requests: kubernetes.Resources{CPU: 100, Memory: 128 << 20},
+				Limits:   kubernetes.Resources{CPU: 200, Memory: 512 << 20},
+			},
+		}
+		client.mu.Unlock()
+		ui.controller.sampleMetrics()
+		ui.controller.refreshUsage()
+	})
+
+	cells := func(row int) (nameColor tcell.Color, values []string) {
+		table := ui.controller.UIManager.PodListPanel
+		for col := 7; col < 11; col++ {
+			values = append(values, table.GetCell(row, col).Text)
+		}
+		name := table.GetCell(row, 0)
+		if name.Style == tcell.StyleDefault {
+			return name.Color, values
+		}
+		foreground, _, _ := name.Style.Decompose()
+		return foreground, values
+	}
+	ui.sync(func() {
+		name, values := cells(1)
+		if want := []string{"████ 150%", "-", "███░  78%", "-"}; !reflect.DeepEqual(values, want) {
+			t.Errorf("web percentages = %q, want %q", values, want)
+		}
+		// The pod has no memory limit as a whole, but app is close to its own.
+		if name != tcell.ColorRed {
+			t.Errorf("web is not shown in red although app is at 93%% of its memory limit")
+		}
+
+		name, values = cells(2)
+		if want := []string{"█▊░░  45%", "▉░░░  22%", "████ 100%", "█░░░  25%"}; !reflect.DeepEqual(values, want) {
+			t.Errorf("api percentages = %q, want %q", values, want)
+		}
+		if name == tcell.ColorRed {
+			t.Errorf("api is shown in red far from its memory limit")
+		}
+	})
+
+	ui.press(tcell.KeyEnter)
+	var details string
+	ui.sync(func() {
+		details = ui.controller.UIManager.DetailsPanel.GetText(true)
+	})
+	for _, want := range []string{
+		"CPU: 150m (peak 150m) 150% of request\n",
+		"Memory: 200Mi (peak 200Mi) 78% of request\n",
+		"140m  ",
+		"150Mi 93% of limit\n",
+	} {
+		if !strings.Contains(details, want) {
+			t.Errorf("details panel is missing %q:\n%s", want, details)
+		}
+	}
+}
+
+func TestDetailsShowStoredWorkloadTrend(t *testing.T) {
+	client := newFakeClient()
+	ui := newTestUI(t, client)
+	store, err := metrics.OpenStore(t.TempDir(), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	defer store.Close()
+
+	// An earlier session recorded a pod of the same Deployment that is gone.
+	now := time.Now()
+	for i := 0; i < 6; i++ {
+		old := kubernetes.PodUsage{
+			Pod:      kubernetes.Pod{Name: "web-5f6c8", Namespace: "default"},
+			Workload: "Deployment/web",
+			Usage:    kubernetes.Usage{CPU: int64(100 * (i + 1)), Memory: 300 << 20},
+		}
+		if err := store.Record(now.Add(time.Duration(i-8)*time.Hour), nil, []kubernetes.PodUsage{old}); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	web := kubernetes.Pod{Name: "web-7d4b9", Namespace: "default"}
+	ui.sync(func() {
+		client.mu.Lock()
+		client.podUsage = []kubernetes.PodUsage{{Pod: web, Workload: "Deployment/web", Usage: kubernetes.Usage{Time: now, CPU: 50, Memory: 256 << 20}}}
+		client.mu.Unlock()
+		ui.controller.MetricsStore = store
+		ui.controller.sampleMetrics()
+	})
+	ui.press(tcell.KeyEnter)
+
+	var details string
+	ui.sync(func() {
+		details = ui.controller.UIManager.DetailsPanel.GetText(true)
+	})
+	for _, want := range []string{
+		"Deployment/web (last 24h, ",
+		"CPU: 50m (peak 600m)",
+		"Memory: 256Mi (peak 300Mi)",
+	} {
+		if !strings.Contains(details, want) {
+			t.Errorf("details panel is missing %q:\n%s", want, details)
+		}
+	}
+}
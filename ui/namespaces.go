@@ -0,0 +1,207 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/ui/panels"
+	"github.com/rdmnl/kubepulse/utils"
+	"github.com/rivo/tview"
+)
+
+// namespaceSort is a column the namespace overview can be sorted by, with
+// the key selecting it.
+type namespaceSort struct {
+	key   rune
+	title string
+	less  func(a, b kubernetes.NamespaceSummary) bool
+}
+
+var namespaceSorts = []namespaceSort{
+	{'N', "name", func(a, b kubernetes.NamespaceSummary) bool { return a.Name < b.Name }},
+	{'P', "pods", func(a, b kubernetes.NamespaceSummary) bool { return a.Pods() > b.Pods() }},
+	{'C', "CPU", func(a, b kubernetes.NamespaceSummary) bool { return a.Usage.CPU > b.Usage.CPU }},
+	{'M', "memory", func(a, b kubernetes.NamespaceSummary) bool { return a.Usage.Memory > b.Usage.Memory }},
+	{'W', "warnings", func(a, b kubernetes.NamespaceSummary) bool { return a.Warnings > b.Warnings }},
+	{'R', "restarts", func(a, b kubernetes.NamespaceSummary) bool { return a.Restarts > b.Restarts }},
+}
+
+// ShowNamespaceOverview lists every namespace with its pods by status, its
+// usage against requests and quota, recent warnings and restarts. Pressing
+// Enter on a namespace scopes the rest of the UI to it.
+func (controller *UIController) ShowNamespaceOverview() {
+	summaries, err := controller.KubernetesClient.ListNamespaceSummaries()
+	if err != nil {
+		errorMessage := fmt.Sprintf("Error summarizing namespaces: %s", kubernetes.DescribeError(err))
+		utils.Warn(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 1)
+	table.SetBackgroundColor(tcell.ColorBlack).
+		SetBorder(true).
+		SetBorderColor(tcell.ColorLightGreen).
+		SetTitleAlign(tview.AlignLeft)
+
+	current, reversed := namespaceSorts[0], false
+	selected := controller.KubernetesClient.Namespace()
+	render := func() {
+		sortNamespaces(summaries, current, reversed)
+		direction := "↓"
+		if reversed {
+			direction = "↑"
+		}
+		var keys []string
+		for _, option := range namespaceSorts {
+			keys = append(keys, string(option.key))
+		}
+		table.SetTitle(fmt.Sprintf(" Namespaces by %s %s ('%s' Sort | Enter Select | Esc Close) ",
+			current.title, direction, strings.Join(keys, "/")))
+		renderNamespaceOverview(table, summaries, selected)
+	}
+	render()
+
+	table.SetSelectedFunc(func(row int, _ int) {
+		summary, ok := table.GetCell(row, 0).GetReference().(kubernetes.NamespaceSummary)
+		if !ok {
+			return
+		}
+		controller.closeModal()
+		controller.switchNamespace(summary.Name)
+	})
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		for _, option := range namespaceSorts {
+			if event.Rune() == option.key {
+				// Selecting the current column again reverses the order.
+				reversed = option.key == current.key && !reversed
+				current = option
+				row, _ := table.GetSelection()
+				if summary, ok := table.GetCell(row, 0).GetReference().(kubernetes.NamespaceSummary); ok {
+					selected = summary.Name
+				}
+				render()
+				return nil
+			}
+		}
+		return event
+	})
+
+	controller.showModal(table, 0, 0)
+}
+
+// sortNamespaces orders summaries by option, by name within equal values.
+func sortNamespaces(summaries []kubernetes.NamespaceSummary, option namespaceSort, reversed bool) {
+	sort.SliceStable(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if reversed {
+			a, b = b, a
+		}
+		if option.less(a, b) || option.less(b, a) {
+			return option.less(a, b)
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+}
+
+// renderNamespaceOverview fills table with summaries and selects the
+// namespace named selected.
+func renderNamespaceOverview(table *tview.Table, summaries []kubernetes.NamespaceSummary, selected string) {
+	table.Clear()
+	headers := []string{"Namespace", "Pods", "Run", "Pend", "Fail", "CPU use/req/quota", "Mem use/req/quota", "Warn 1h", "Restarts"}
+	for col, header := range headers {
+		table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorWhite).
+			SetSelectable(false).
+			SetAlign(tview.AlignCenter))
+	}
+
+	if len(summaries) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No namespaces found.").
+			SetTextColor(tcell.ColorGray).
+			SetSelectable(false))
+		return
+	}
+
+	selectedRow := 1
+	for row, summary := range summaries {
+		if summary.Name == selected {
+			selectedRow = row + 1
+		}
+		cells := []*tview.TableCell{
+			tview.NewTableCell(tview.Escape(summary.Name)).SetTextColor(tcell.ColorLightGreen),
+			countCell(summary.Pods(), tcell.ColorWhite),
+			countCell(summary.Running, tcell.ColorLightGreen),
+			countCell(summary.Pending, tcell.ColorYellow),
+			countCell(summary.Failed, tcell.ColorRed),
+			tview.NewTableCell(formatQuotaUsage(summary.Usage.CPU, summary.UsageAvailable, summary.Requests.CPU, summary.Quota.CPU, kubernetes.FormatCPU)).
+				SetTextColor(quotaColor(summary.Requests.CPU, summary.Quota.CPU)).SetAlign(tview.AlignRight),
+			tview.NewTableCell(formatQuotaUsage(summary.Usage.Memory, summary.UsageAvailable, summary.Requests.Memory, summary.Quota.Memory, kubernetes.FormatMemory)).
+				SetTextColor(quotaColor(summary.Requests.Memory, summary.Quota.Memory)).SetAlign(tview.AlignRight),
+			countCell(summary.Warnings, tcell.ColorOrange),
+			countCell(int(summary.Restarts), tcell.ColorOrange),
+		}
+		for col, cell := range cells {
+			table.SetCell(row+1, col, cell.SetReference(summary))
+		}
+	}
+	table.Select(selectedRow, 0)
+}
+
+// countCell shows a count in color, or gray when it is zero.
+func countCell(count int, color tcell.Color) *tview.TableCell {
+	if count == 0 {
+		color = tcell.ColorGray
+	}
+	return tview.NewTableCell(fmt.Sprint(count)).SetTextColor(color).SetAlign(tview.AlignRight)
+}
+
+// formatQuotaUsage shows usage, requests and quota separated by slashes, with
+// n/a for unknown usage and a dash for a missing quota.
+func formatQuotaUsage(usage int64, usageAvailable bool, requests int64, quota int64, format func(int64) string) string {
+	used := "n/a"
+	if usageAvailable {
+		used = format(usage)
+	}
+	limit := "-"
+	if quota > 0 {
+		limit = format(quota)
+	}
+	return used + "/" + format(requests) + "/" + limit
+}
+
+// quotaColor warns when the requests of a namespace are close to its quota,
+// the way the pod table warns about limits.
+func quotaColor(requests int64, quota int64) tcell.Color {
+	if percent, ok := kubernetes.Percent(requests, quota); ok {
+		return panels.PercentColor(percent, true)
+	}
+	return tcell.ColorLightCyan
+}
+
+// switchNamespace scopes the pod list, and the resource browser when it is
+// open, to namespace.
+func (controller *UIController) switchNamespace(namespace string) {
+	controller.KubernetesClient.SetNamespace(namespace)
+	controller.UIManager.SelectedNode = ""
+	controller.updatePodList()
+	controller.updatePodTable()
+	if resource := controller.UIManager.ActiveResource; resource != nil {
+		controller.showResource(*resource)
+	}
+}
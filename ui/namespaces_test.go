@@ -0,0 +1,67 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+)
+
+func TestNamespaceOverviewSortsAndScopes(t *testing.T) {
+	client := newFakeClient()
+	client.summaries = []kubernetes.NamespaceSummary{
+		{
+			Name: "default", PodCounts: kubernetes.PodCounts{Running: 2}, Restarts: 1,
+			Usage:          kubernetes.Resources{CPU: 165, Memory: 384 << 20},
+			UsageAvailable: true,
+			Requests:       kubernetes.Resources{CPU: 950, Memory: 512 << 20},
+			Quota:          kubernetes.Resources{CPU: 1000},
+		},
+		{Name: "kube-system", PodCounts: kubernetes.PodCounts{Running: 1, Pending: 1}, Restarts: 7, Warnings: 3, UsageAvailable: true},
+	}
+	ui := newTestUI(t, client)
+
+	ui.typeRunes(":ns")
+	ui.press(tcell.KeyEnter)
+	ui.assertContains(
+		"Namespaces by name ↓",
+		"default        2   2    0    0   165m/950m/1000m     384Mi/512Mi/-",
+		"kube-system    2   1    1    0           0m/0m/-         0Mi/0Mi/-",
+	)
+
+	// Restarts sort the most first, pressing the key again reverses them.
+	ui.typeRunes("R")
+	screen := ui.text()
+	if !strings.Contains(screen, "Namespaces by restarts ↓") || strings.Index(screen, "kube-system ") > strings.Index(screen, "default ") {
+		t.Errorf("namespaces are not sorted by restarts:\n%s", screen)
+	}
+	ui.typeRunes("R")
+	ui.assertContains("Namespaces by restarts ↑")
+
+	ui.press(tcell.KeyDown)
+	ui.press(tcell.KeyEnter)
+	if namespace := client.Namespace(); namespace != "kube-system" {
+		t.Fatalf("namespace = %q, want kube-system", namespace)
+	}
+	ui.assertContains("coredns-x1")
+	if strings.Contains(ui.text(), "web-7d4b9") {
+		t.Errorf("pod list is not scoped to kube-system:\n%s", ui.text())
+	}
+}
+
+func TestFormatQuotaUsage(t *testing.T) {
+	if got := formatQuotaUsage(165, true, 950, 1000, kubernetes.FormatCPU); got != "165m/950m/1000m" {
+		t.Errorf("formatQuotaUsage = %q, want 165m/950m/1000m", got)
+	}
+	if got := formatQuotaUsage(0, false, 950, 0, kubernetes.FormatCPU); got != "n/a/950m/-" {
+		t.Errorf("formatQuotaUsage without metrics = %q, want n/a/950m/-", got)
+	}
+}
@@ -24,11 +24,19 @@ func SetupNavigation(app *tview.Application, controller *UIController) {
 		if _, ok := app.GetFocus().(*tview.InputField); ok {
 			return event
 		}
+		if controller.modalOpen {
+			if event.Key() == tcell.KeyEscape {
+				controller.closeModal()
+				return nil
+			}
+			return event
+		}
 
 		switch event.Key() {
 		case tcell.KeyRune:
 			switch event.Rune() {
 			case 'p':
+				controller.showPodList()
 				controller.setPanelFocus(0)
 			case 'n':
 				controller.setPanelFocus(1)
@@ -42,6 +50,66 @@ func SetupNavigation(app *tview.Application, controller *UIController) {
 				controller.HandleBackNavigation()
 			case 'f':
 				controller.HandleNamespaceFilter()
+			case ':':
+				controller.HandleCommandPrompt()
+				return nil
+			case 'e':
+				controller.HandleEdit()
+			case 's':
+				controller.HandleExec()
+			case 'F':
+				controller.HandlePortForward()
+			case 'D':
+				controller.HandleDeletePod(false)
+			case 'K':
+				controller.HandleDeletePod(true)
+			case 'E':
+				controller.HandleEvictPod()
+			case 'S':
+				controller.HandleScale()
+			case 'R':
+				controller.HandleRestart()
+			case 'P':
+				controller.HandlePauseToggle()
+			case 'H':
+				controller.HandleRolloutHistory()
+			case 'c':
+				controller.HandleCordon(true)
+			case 'u':
+				controller.HandleCordon(false)
+			case 'r':
+				controller.HandleDrain()
+			case 'y':
+				if controller.UIManager.DetailsPanel.HasFocus() {
+					controller.HandleYAMLToggle()
+				}
+			case 'm':
+				if controller.UIManager.DetailsPanel.HasFocus() {
+					controller.HandleManagedFieldsToggle()
+				}
+			case '/':
+				if controller.UIManager.DetailsPanel.HasFocus() {
+					controller.HandleDetailsSearch()
+					return nil
+				}
+				if _, ok := controller.focusedView(); ok {
+					controller.HandleFilter()
+					return nil
+				}
+			case 'N':
+				if controller.UIManager.DetailsPanel.HasFocus() {
+					controller.HandleNextMatch()
+				}
+			case '<':
+				controller.HandleSortColumn(-1)
+			case '>':
+				controller.HandleSortColumn(1)
+			case 'I':
+				controller.HandleSortInvert()
+			case 'w':
+				controller.HandleWideToggle()
+			case 'C':
+				controller.HandleColumnEditor()
 			case 'q':
 				utils.Info("Quit key pressed")
 				app.Stop()
@@ -51,6 +119,8 @@ func SetupNavigation(app *tview.Application, controller *UIController) {
 				controller.HandlePodSelection()
 			} else if controller.UIManager.NodeListPanel.HasFocus() {
 				controller.HandleNodeSelection()
+			} else if controller.UIManager.ResourceListPanel.HasFocus() {
+				controller.HandleResourceSelection()
 			}
 		}
 		return event
@@ -0,0 +1,127 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/demo"
+	"github.com/rivo/tview"
+)
+
+func TestInitialLayout(t *testing.T) {
+	ui := newTestUI(t, newFakeClient())
+
+	if ui.focused() != ui.controller.UIManager.PodListPanel {
+		t.Errorf("initial focus is not on the pod list")
+	}
+	ui.assertGolden("initial")
+}
+
+func TestPanelShortcutsMoveFocus(t *testing.T) {
+	ui := newTestUI(t, newFakeClient())
+	manager := ui.controller.UIManager
+
+	tests := []struct {
+		key   rune
+		panel tview.Primitive
+		title string
+	}{
+		{'n', manager.NodeListPanel, " Nodes "},
+		{'d', manager.DetailsPanel, " Detail "},
+		{'p', manager.PodListPanel, " Pods "},
+	}
+	for _, test := range tests {
+		ui.typeRunes(string(test.key))
+		if ui.focused() != test.panel {
+			t.Errorf("after %q the focus is not on the%s panel", test.key, test.title)
+		}
+		ui.assertContains(test.title)
+	}
+}
+
+func TestPodSelectionShowsDetails(t *testing.T) {
+	ui := newTestUI(t, newFakeClient())
+
+	ui.press(tcell.KeyEnter)
+
+	if ui.focused() != ui.controller.UIManager.DetailsPanel {
+		t.Errorf("selecting a pod did not focus the details panel")
+	}
+	ui.assertGolden("pod_details")
+}
+
+func TestLogViewShowsSelectedPodLogs(t *testing.T) {
+	ui := newTestUI(t, newFakeClient())
+
+	ui.typeRunes("l")
+
+	if ui.focused() != ui.controller.UIManager.LogsViewPanel {
+		t.Errorf("opening logs did not focus the logs panel")
+	}
+	ui.assertContains("Logs for pod default/web-7d4b9:", "GET /healthz 200")
+}
+
+func TestNodeSelectionListsNodePods(t *testing.T) {
+	ui := newTestUI(t, newFakeClient())
+
+	ui.typeRunes("n")
+	ui.press(tcell.KeyDown)
+	ui.press(tcell.KeyEnter)
+
+	if ui.focused() != ui.controller.UIManager.PodListPanel {
+		t.Errorf("selecting a node did not focus the pod list")
+	}
+	ui.assertGolden("node_pods")
+}
+
+func TestDeniedActionsAreGreyedOut(t *testing.T) {
+	client := newFakeClient()
+	client.denied["delete/pods/"] = true
+	ui := newTestUI(t, client)
+
+	ui.typeRunes("n")
+	ui.typeRunes("p")
+
+	var status string
+	ui.sync(func() {
+		status = ui.controller.UIManager.StatusBar.GetText(false)
+	})
+	for _, want := range []string{"[gray]'D' Delete[-]", "[gray]'K' Force Delete[-]"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("status bar %q does not grey out %q", status, want)
+		}
+	}
+	if strings.Contains(status, "[gray]'E'") {
+		t.Errorf("status bar %q greys out an allowed action", status)
+	}
+}
+
+func TestListErrorsAreShownInPanels(t *testing.T) {
+	client := newFakeClient()
+	client.err = errors.New("connection refused")
+	ui := newTestUI(t, client)
+
+	ui.assertContains("connection refused")
+}
+
+func TestDemoCluster(t *testing.T) {
+	start := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	client := demo.NewCluster(func() time.Time { return start }, 1).Client()
+	ui := newTestUI(t, client)
+	ui.syncDraw(func() {
+		summary, err := client.GetClusterSummary()
+		ui.controller.showClusterSummary(summary, err)
+	})
+
+	ui.assertGolden("demo")
+}
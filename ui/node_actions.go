@@ -0,0 +1,318 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/utils"
+	"github.com/rivo/tview"
+)
+
+const defaultDrainTimeout = 5 * time.Minute
+
+func (controller *UIController) HandleCordon(cordon bool) {
+	node, err := controller.getSelectedNodeName()
+	if err != nil {
+		controller.reportActionError("Cannot cordon", err)
+		return
+	}
+	if !controller.requireAccess("patch", kubernetes.NodeResource, "", "") {
+		return
+	}
+
+	action := "cordoned"
+	if !cordon {
+		action = "uncordoned"
+	}
+	controller.confirmProtected(fmt.Sprintf("Node %s %s", node, action), node, func() {
+		if err := controller.KubernetesClient.CordonNode(node, cordon); err != nil {
+			controller.reportActionError(fmt.Sprintf("Node %s not %s", node, action), err)
+			return
+		}
+
+		utils.Info(fmt.Sprintf("Node %s %s", node, action))
+		controller.UIManager.StatusBar.SetText(fmt.Sprintf("[green]Node %s %s", node, action))
+	})
+}
+
+func (controller *UIController) HandleDrain() {
+	node, err := controller.getSelectedNodeName()
+	if err != nil {
+		controller.reportActionError("Cannot drain", err)
+		return
+	}
+	// Draining cordons the node and evicts its pods in every namespace.
+	if !controller.requireAccess("patch", kubernetes.NodeResource, "", "") ||
+		!controller.requireAccess("create", kubernetes.PodResource, "eviction", "") {
+		return
+	}
+
+	form := tview.NewForm().
+		AddCheckbox("Ignore DaemonSets:", true, nil).
+		AddCheckbox("Delete emptyDir data:", false, nil).
+		AddCheckbox("Force unmanaged pods:", false, nil).
+		AddInputField("Timeout (seconds, 0 = none):", strconv.Itoa(int(defaultDrainTimeout.Seconds())), 8, tview.InputFieldInteger, nil).
+		AddInputField("Grace period (seconds, -1 = pod default):", "-1", 8, tview.InputFieldInteger, nil)
+
+	form.AddButton("Analyze", func() {
+		options, err := drainOptionsFromForm(form)
+		if err != nil {
+			controller.UIManager.StatusBar.SetText("[red]Drain options: " + err.Error())
+			return
+		}
+
+		report, err := controller.KubernetesClient.AnalyzeDrain(node, options)
+		if err != nil {
+			controller.reportActionError(fmt.Sprintf("Error analyzing drain of node %s", node), err)
+			return
+		}
+		controller.closeModal()
+		controller.showDrainReport(report, options)
+	})
+	form.AddButton("Drain", func() {
+		options, err := drainOptionsFromForm(form)
+		if err != nil {
+			controller.UIManager.StatusBar.SetText("[red]Drain options: " + err.Error())
+			return
+		}
+		controller.closeModal()
+		controller.confirmProtected(fmt.Sprintf("Drain node %s", node), node, func() {
+			controller.showDrainProgress(node, options)
+		})
+	}).
+		AddButton("Cancel", func() {
+			controller.closeModal()
+		})
+
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf("Drain node %s", node)).
+		SetTitleAlign(tview.AlignCenter)
+
+	controller.showModal(form, 70, 15)
+}
+
+func drainOptionsFromForm(form *tview.Form) (kubernetes.DrainOptions, error) {
+	timeout, err := strconv.Atoi(form.GetFormItem(3).(*tview.InputField).GetText())
+	if err != nil || timeout < 0 {
+		return kubernetes.DrainOptions{}, fmt.Errorf("invalid timeout")
+	}
+	gracePeriod, err := strconv.Atoi(form.GetFormItem(4).(*tview.InputField).GetText())
+	if err != nil || gracePeriod < -1 {
+		return kubernetes.DrainOptions{}, fmt.Errorf("invalid grace period")
+	}
+
+	options := kubernetes.DrainOptions{
+		IgnoreDaemonSets:   form.GetFormItem(0).(*tview.Checkbox).IsChecked(),
+		DeleteEmptyDirData: form.GetFormItem(1).(*tview.Checkbox).IsChecked(),
+		Force:              form.GetFormItem(2).(*tview.Checkbox).IsChecked(),
+		Timeout:            time.Duration(timeout) * time.Second,
+	}
+	if gracePeriod >= 0 {
+		seconds := int64(gracePeriod)
+		options.GracePeriodSeconds = &seconds
+	}
+	return options, nil
+}
+
+// showDrainReport shows the dry-run impact of a drain and lets the user start
+// it with the same options.
+func (controller *UIController) showDrainReport(report *kubernetes.DrainReport, options kubernetes.DrainOptions) {
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(renderDrainReport(report))
+	view.SetBackgroundColor(tcell.ColorBlack)
+
+	form := tview.NewForm().
+		AddButton("Drain", func() {
+			controller.closeModal()
+			controller.confirmProtected(fmt.Sprintf("Drain node %s", report.Node), report.Node, func() {
+				controller.showDrainProgress(report.Node, options)
+			})
+		}).
+		AddButton(cancelButton, func() {
+			controller.closeModal()
+		})
+	form.SetButtonsAlign(tview.AlignCenter)
+
+	// Keep the buttons focused but let the arrow keys scroll the report.
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn, tcell.KeyHome, tcell.KeyEnd:
+			view.InputHandler()(event, nil)
+			return nil
+		}
+		return event
+	})
+
+	dialog := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(view, 0, 1, false).
+		AddItem(form, 3, 0, true)
+	dialog.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Drain impact: %s ", report.Node)).
+		SetTitleAlign(tview.AlignLeft)
+
+	controller.showModal(dialog, 0, 0)
+}
+
+func renderDrainReport(report *kubernetes.DrainReport) string {
+	var evicted, blocked, refused, unmanaged, localStorage, skipped []string
+	for _, impact := range report.Pods {
+		name := tview.Escape(impact.Pod.Namespace + "/" + impact.Pod.Name)
+		switch impact.Status {
+		case kubernetes.DrainSkipped:
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", name, impact.Reason))
+			continue
+		case kubernetes.DrainFailed:
+			refused = append(refused, fmt.Sprintf("%s (%s)", name, impact.Reason))
+		case kubernetes.DrainBlocked:
+			blocked = append(blocked, fmt.Sprintf("%s (PodDisruptionBudget %s)", name, impact.BlockingPDB))
+		default:
+			evicted = append(evicted, fmt.Sprintf("%s (%s CPU, %s memory)", name, formatMillicores(impact.CPURequest), utils.FormatBytes(impact.MemoryRequest)))
+		}
+		if impact.Unmanaged {
+			unmanaged = append(unmanaged, name)
+		}
+		if impact.LocalStorage {
+			localStorage = append(localStorage, name)
+		}
+	}
+
+	var builder strings.Builder
+	writeSection := func(title string, color string, items []string) {
+		builder.WriteString(fmt.Sprintf("[white::b]%s (%d)[-::-]\n", title, len(items)))
+		for _, item := range items {
+			builder.WriteString(fmt.Sprintf("  [%s]%s\n", color, item))
+		}
+		builder.WriteString("\n")
+	}
+	writeSection("Would be evicted", "lightgreen", evicted)
+	writeSection("Blocked by PodDisruptionBudgets", "yellow", blocked)
+	writeSection("Unmanaged, lost when evicted", "red", unmanaged)
+	writeSection("Local storage, emptyDir data is deleted", "red", localStorage)
+	writeSection("Refusing the drain with these options", "red", refused)
+	writeSection("Skipped", "gray", skipped)
+
+	builder.WriteString(fmt.Sprintf("[white::b]Capacity[-::-]\n  Evicted requests: [lightyellow]%s CPU, %s memory\n",
+		formatMillicores(report.CPURequests), utils.FormatBytes(report.MemoryRequests)))
+	for _, node := range report.Nodes {
+		builder.WriteString(fmt.Sprintf("  [white]%s: [lightblue]%s CPU, %s memory free\n",
+			tview.Escape(node.Name), formatMillicores(node.FreeCPU), utils.FormatBytes(node.FreeMemory)))
+	}
+	switch {
+	case len(report.Nodes) == 0:
+		builder.WriteString("  [red]No other schedulable node is ready to take the evicted pods\n")
+	case report.Fits:
+		builder.WriteString("  [lightgreen]The remaining nodes have enough allocatable CPU and memory\n")
+	default:
+		builder.WriteString("  [red]The remaining nodes do not have enough allocatable CPU or memory\n")
+	}
+	return builder.String()
+}
+
+func formatMillicores(millicores int64) string {
+	return fmt.Sprintf("%dm", millicores)
+}
+
+// showDrainProgress drains node and lists the state of every pod while it runs.
+// Closing the dialog cancels the drain; the node stays cordoned.
+func (controller *UIController) showDrainProgress(node string, options kubernetes.DrainOptions) {
+	summary := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(fmt.Sprintf("[yellow]Draining node %s...", node))
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	headers := []string{"Pod", "Namespace", "Status", "Message"}
+	for col, header := range headers {
+		table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorWhite).
+			SetSelectable(false).
+			SetAlign(tview.AlignCenter))
+	}
+
+	content := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(summary, 2, 0, false).
+		AddItem(table, 0, 1, true)
+	content.SetBackgroundColor(tcell.ColorBlack).
+		SetBorder(true).
+		SetBorderColor(tcell.ColorLightGreen).
+		SetTitle(fmt.Sprintf(" Drain %s (Esc Close, cancels a running drain) ", node)).
+		SetTitleAlign(tview.AlignLeft)
+
+	rows := make(map[string]int)
+	progress := func(event kubernetes.DrainEvent) {
+		controller.Application.QueueUpdateDraw(func() {
+			key := event.Pod.Namespace + "/" + event.Pod.Name
+			row, ok := rows[key]
+			if !ok {
+				row = table.GetRowCount()
+				rows[key] = row
+			}
+			table.SetCell(row, 0, tview.NewTableCell(event.Pod.Name).SetTextColor(tcell.ColorLightYellow))
+			table.SetCell(row, 1, tview.NewTableCell(event.Pod.Namespace).SetTextColor(tcell.ColorLightGreen))
+			table.SetCell(row, 2, tview.NewTableCell(event.Status).SetTextColor(drainStatusColor(event.Status)))
+			table.SetCell(row, 3, tview.NewTableCell(tview.Escape(event.Message)).SetTextColor(tcell.ColorGray))
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		err := controller.KubernetesClient.DrainNode(ctx, node, options, progress)
+		controller.Application.QueueUpdateDraw(func() {
+			if err != nil {
+				errorMessage := fmt.Sprintf("Drain of node %s failed: %v", node, err)
+				utils.Warn(errorMessage)
+				summary.SetText("[red]" + tview.Escape(errorMessage))
+				return
+			}
+			utils.Info(fmt.Sprintf("Drained node %s", node))
+			summary.SetText(fmt.Sprintf("[lightgreen]Node %s drained, it stays cordoned until uncordoned with 'u'", node))
+		})
+	}()
+
+	controller.showModal(content, 0, 0)
+	controller.modalCleanup = func() {
+		cancel()
+		controller.refreshPods()
+	}
+}
+
+// getSelectedNodeName returns the node selected in the focused panel.
+func (controller *UIController) getSelectedNodeName() (string, error) {
+	ref, err := controller.getSelectedObject()
+	if err != nil {
+		return "", err
+	}
+	if ref.Resource.GroupVersionResource() != kubernetes.NodeResource.GroupVersionResource() {
+		return "", fmt.Errorf("%s is not a node", ref)
+	}
+	return ref.Name, nil
+}
+
+func drainStatusColor(status string) tcell.Color {
+	switch status {
+	case kubernetes.DrainEvicted:
+		return tcell.ColorLightGreen
+	case kubernetes.DrainSkipped:
+		return tcell.ColorGray
+	case kubernetes.DrainFailed, kubernetes.DrainCancelled:
+		return tcell.ColorRed
+	default:
+		return tcell.ColorYellow
+	}
+}
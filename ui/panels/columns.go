@@ -0,0 +1,230 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package panels
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/config"
+	"github.com/rdmnl/kubepulse/utils"
+	"github.com/rivo/tview"
+)
+
+// nameColumn is the column that identifies a row. It cannot be hidden.
+const nameColumn = "name"
+
+// Now is the current time the ages in the tables are measured from. Tests
+// replace it to get stable output.
+var Now = time.Now
+
+// column is a column of a table whose rows are of type R.
+type column[R any] struct {
+	// name identifies the column in the configuration.
+	name  string
+	title string
+	// wide columns are only shown in wide mode, unless the view lists them.
+	wide bool
+	cell func(row R) *tview.TableCell
+	// compare orders rows by the column, nil when it cannot be sorted by.
+	compare func(a, b R) int
+}
+
+// columnTable is a table whose columns can be sorted by, hidden and
+// reordered through a config.TableView.
+type columnTable[R any] struct {
+	columns []column[R]
+	// key identifies a row, so it stays selected when the rows move.
+	key func(row R) string
+}
+
+// ColumnInfo describes a column of a table for the column editor.
+type ColumnInfo struct {
+	Name     string
+	Visible  bool
+	Sortable bool
+}
+
+// TableColumns returns every column of the table viewName, the visible ones
+// first in the order they are shown.
+func TableColumns(viewName string, view *config.TableView) []ColumnInfo {
+	switch viewName {
+	case config.PodsView:
+		return podTable.info(view)
+	case config.NodesView:
+		return nodeTable.info(view)
+	}
+	return nil
+}
+
+func (t columnTable[R]) info(view *config.TableView) []ColumnInfo {
+	var infos []ColumnInfo
+	visible := t.visible(view)
+	for _, c := range visible {
+		infos = append(infos, ColumnInfo{Name: c.name, Visible: true, Sortable: c.compare != nil})
+	}
+	for _, c := range t.columns {
+		if !slices.ContainsFunc(visible, func(v column[R]) bool { return v.name == c.name }) {
+			infos = append(infos, ColumnInfo{Name: c.name, Sortable: c.compare != nil})
+		}
+	}
+	return infos
+}
+
+// find returns the column called name.
+func (t columnTable[R]) find(name string) (column[R], bool) {
+	for _, c := range t.columns {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return column[R]{}, false
+}
+
+// visible returns the columns view shows, in order. Without a view, or one
+// listing no columns, these are the columns that are not wide. Wide mode adds
+// the remaining columns at the end.
+func (t columnTable[R]) visible(view *config.TableView) []column[R] {
+	var columns []column[R]
+	if view != nil && len(view.Columns) > 0 {
+		for _, name := range view.Columns {
+			c, ok := t.find(name)
+			if !ok {
+				utils.Warn(fmt.Sprintf("Ignoring unknown column %q", name))
+				continue
+			}
+			columns = append(columns, c)
+		}
+		if !slices.ContainsFunc(columns, func(c column[R]) bool { return c.name == nameColumn }) {
+			name, _ := t.find(nameColumn)
+			columns = append([]column[R]{name}, columns...)
+		}
+	} else {
+		for _, c := range t.columns {
+			if !c.wide {
+				columns = append(columns, c)
+			}
+		}
+	}
+
+	if view != nil && view.Wide {
+		for _, c := range t.columns {
+			if !slices.ContainsFunc(columns, func(v column[R]) bool { return v.name == c.name }) {
+				columns = append(columns, c)
+			}
+		}
+	}
+	return columns
+}
+
+// fill replaces the rows of table with rows, sorted and laid out as view
+// says. Every cell keeps its row as reference, and the selected row stays
+// selected wherever it moves.
+func (t columnTable[R]) fill(table *tview.Table, view *config.TableView, rows []R) {
+	selected := ""
+	if row, _ := table.GetSelection(); row > 0 {
+		if current, ok := table.GetCell(row, 0).GetReference().(R); ok {
+			selected = t.key(current)
+		}
+	}
+
+	rows = slices.Clone(rows)
+	if view != nil && view.SortBy != "" {
+		if c, ok := t.find(view.SortBy); ok && c.compare != nil {
+			slices.SortStableFunc(rows, func(a, b R) int {
+				if view.Descending {
+					return c.compare(b, a)
+				}
+				return c.compare(a, b)
+			})
+		}
+	}
+
+	columns := t.visible(view)
+	table.Clear()
+	for col, c := range columns {
+		title := c.title
+		if view != nil && view.SortBy == c.name {
+			title += sortIndicator(view.Descending)
+		}
+		table.SetCell(0, col, tview.NewTableCell(title).
+			SetTextColor(tcell.ColorWhite).
+			SetSelectable(false).
+			SetAlign(tview.AlignCenter))
+	}
+
+	for i, row := range rows {
+		for col, c := range columns {
+			table.SetCell(i+1, col, c.cell(row).
+				SetSelectable(c.name == nameColumn).
+				SetReference(row))
+		}
+		if selected != "" && t.key(row) == selected {
+			table.Select(i+1, 0)
+		}
+	}
+}
+
+// rows returns the rows of a table filled by fill.
+func (t columnTable[R]) rows(table *tview.Table) []R {
+	var rows []R
+	for row := 1; row < table.GetRowCount(); row++ {
+		if current, ok := table.GetCell(row, 0).GetReference().(R); ok {
+			rows = append(rows, current)
+		}
+	}
+	return rows
+}
+
+// sortIndicator marks the header of the column the rows are sorted by.
+func sortIndicator(descending bool) string {
+	if descending {
+		return "↓"
+	}
+	return "↑"
+}
+
+// textCell is a left-aligned cell showing text.
+func textCell(text string, color tcell.Color) *tview.TableCell {
+	return tview.NewTableCell(tview.Escape(text)).
+		SetTextColor(color).
+		SetAlign(tview.AlignLeft)
+}
+
+// ageCell shows how long ago created is, like kubectl.
+func ageCell(created time.Time) *tview.TableCell {
+	return tview.NewTableCell(FormatAge(created)).
+		SetTextColor(tcell.ColorWhite).
+		SetAlign(tview.AlignRight)
+}
+
+// FormatAge shows the time since t in its largest unit, such as "45s", "12m",
+// "5h" or "3d", or a dash when t is unset.
+func FormatAge(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	age := Now().Sub(t)
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", max(age/time.Second, 0))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", age/time.Minute)
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", age/time.Hour)
+	default:
+		return fmt.Sprintf("%dd", age/(24*time.Hour))
+	}
+}
+
+// compareCreated orders by age, the youngest first.
+func compareCreated(a time.Time, b time.Time) int {
+	return b.Compare(a)
+}
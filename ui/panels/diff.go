@@ -0,0 +1,40 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package panels
+
+import (
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// HighlightDiff colors the lines of a unified diff: additions green, removals
+// red and hunk headers cyan.
+func HighlightDiff(diff string) string {
+	var builder strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		color := ""
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			color = "white::b"
+		case strings.HasPrefix(line, "@@"):
+			color = "lightcyan"
+		case strings.HasPrefix(line, "+"):
+			color = "lightgreen"
+		case strings.HasPrefix(line, "-"):
+			color = "red"
+		}
+
+		if color != "" {
+			builder.WriteString("[" + color + "]" + tview.Escape(line) + "[-:-:-]\n")
+		} else {
+			builder.WriteString(tview.Escape(line) + "\n")
+		}
+	}
+	return builder.String()
+}
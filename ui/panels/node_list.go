@@ -8,12 +8,78 @@
 package panels
 
 import (
+	"cmp"
+	"strings"
+
 	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/config"
 	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/pkg/metrics"
 	"github.com/rivo/tview"
 )
 
-func SetupNodeListPanel(client kubernetes.KubernetesClient) *tview.Table {
+// nodeRow is a row of the node table: a node with its usage.
+type nodeRow struct {
+	node    kubernetes.Node
+	samples []kubernetes.Usage
+	// cpu and memory come from the metrics API when there are no samples.
+	cpu    string
+	memory string
+}
+
+// nodeTable lays out the node table like podTable.
+var nodeTable = columnTable[nodeRow]{
+	columns: []column[nodeRow]{
+		{name: nameColumn, title: "Node Name", cell: func(row nodeRow) *tview.TableCell {
+			return textCell(row.node.Name, tcell.ColorLightYellow)
+		}, compare: func(a, b nodeRow) int { return cmp.Compare(a.node.Name, b.node.Name) }},
+		{name: "cpu", title: "CPU", cell: func(row nodeRow) *tview.TableCell {
+			if len(row.samples) == 0 {
+				return usageCell(row.cpu, tcell.ColorLightGreen)
+			}
+			return usageCell(kubernetes.FormatCPU(row.samples[len(row.samples)-1].CPU), tcell.ColorLightGreen)
+		}, compare: func(a, b nodeRow) int { return cmp.Compare(latestCPU(a.samples), latestCPU(b.samples)) }},
+		{name: "memory", title: "Memory", cell: func(row nodeRow) *tview.TableCell {
+			if len(row.samples) == 0 {
+				return usageCell(row.memory, tcell.ColorLightBlue)
+			}
+			return usageCell(kubernetes.FormatMemory(row.samples[len(row.samples)-1].Memory), tcell.ColorLightBlue)
+		}, compare: func(a, b nodeRow) int { return cmp.Compare(latestMemory(a.samples), latestMemory(b.samples)) }},
+		{name: "status", title: "Status", cell: func(row nodeRow) *tview.TableCell {
+			color := tcell.ColorLightGreen
+			if row.node.Status != "Ready" {
+				color = tcell.ColorRed
+				if strings.HasPrefix(row.node.Status, "Ready") {
+					color = tcell.ColorYellow
+				}
+			}
+			return textCell(row.node.Status, color)
+		}, compare: func(a, b nodeRow) int { return cmp.Compare(a.node.Status, b.node.Status) }},
+		{name: "age", title: "Age", cell: func(row nodeRow) *tview.TableCell {
+			return ageCell(row.node.Created)
+		}, compare: func(a, b nodeRow) int { return compareCreated(a.node.Created, b.node.Created) }},
+		{name: "cpuTrend", cell: func(row nodeRow) *tview.TableCell {
+			cpu, _ := UsageSeries(row.samples)
+			return trendCell(cpu, tcell.ColorLightGreen)
+		}},
+		{name: "memoryTrend", cell: func(row nodeRow) *tview.TableCell {
+			_, memory := UsageSeries(row.samples)
+			return trendCell(memory, tcell.ColorLightBlue)
+		}},
+		{name: "roles", title: "Roles", wide: true, cell: func(row nodeRow) *tview.TableCell {
+			return textCell(row.node.Roles, tcell.ColorWhite)
+		}, compare: func(a, b nodeRow) int { return cmp.Compare(a.node.Roles, b.node.Roles) }},
+		{name: "version", title: "Version", wide: true, cell: func(row nodeRow) *tview.TableCell {
+			return textCell(row.node.Version, tcell.ColorWhite)
+		}, compare: func(a, b nodeRow) int { return cmp.Compare(a.node.Version, b.node.Version) }},
+		{name: "ip", title: "Internal IP", wide: true, cell: func(row nodeRow) *tview.TableCell {
+			return textCell(row.node.InternalIP, tcell.ColorWhite)
+		}, compare: func(a, b nodeRow) int { return cmp.Compare(a.node.InternalIP, b.node.InternalIP) }},
+	},
+	key: func(row nodeRow) string { return row.node.Name },
+}
+
+func SetupNodeListPanel(client kubernetes.KubernetesClient, view *config.TableView) *tview.Table {
 	table := tview.NewTable()
 
 	table.SetBorders(false).
@@ -23,42 +89,66 @@ func SetupNodeListPanel(client kubernetes.KubernetesClient) *tview.Table {
 		SetBorder(true).
 		SetBorderColor(tcell.ColorLightCyan)
 
-	table.SetCell(0, 0, tview.NewTableCell("Node Name").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	table.SetCell(0, 1, tview.NewTableCell("CPU").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	table.SetCell(0, 2, tview.NewTableCell("Memory").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-
-	nodes, err := client.GetNodes()
+	nodes, err := client.ListNodes(kubernetes.Filter{})
 	if err != nil {
+		nodeTable.fill(table, view, nil)
+		ShowTableMessage(table, kubernetes.DescribeAccessError(err, "list", kubernetes.NodeResource, "", ""))
 		return table
 	}
 
-	for row, node := range nodes {
-		cpuUsage, memoryUsage, err := client.GetNodeMetrics(node)
-		if err != nil {
-			cpuUsage = "N/A"
-			memoryUsage = "N/A"
+	FillNodeTable(table, client, nil, view, nodes)
+	return table
+}
+
+// FillNodeTable replaces the rows of table with nodes and their usage, taken
+// from history like FillPodTable does. history may be nil.
+func FillNodeTable(table *tview.Table, client kubernetes.KubernetesClient, history *metrics.History, view *config.TableView, nodes []kubernetes.Node) {
+	var rows []nodeRow
+	for _, node := range nodes {
+		row := nodeRow{node: node}
+		if history != nil {
+			row.samples = history.Node(node.Name)
+		}
+		if len(row.samples) == 0 {
+			cpuUsage, memoryUsage, err := client.GetNodeMetrics(node.Name)
+			if err != nil {
+				cpuUsage, memoryUsage = "N/A", "N/A"
+			}
+			row.cpu, row.memory = cpuUsage, memoryUsage
+		}
+		rows = append(rows, row)
+	}
+	nodeTable.fill(table, view, rows)
+}
+
+// RefreshNodeUsage updates the usage of the nodes in table from the latest
+// samples in history, without calling the API, and sorts them again.
+func RefreshNodeUsage(table *tview.Table, history *metrics.History, view *config.TableView) {
+	rows := nodeTable.rows(table)
+	if len(rows) == 0 {
+		return
+	}
+	for i, row := range rows {
+		if samples := history.Node(row.node.Name); len(samples) > 0 {
+			rows[i].samples = samples
 		}
+	}
+	nodeTable.fill(table, view, rows)
+}
 
-		table.SetCell(row+1, 0, tview.NewTableCell(node).
-			SetTextColor(tcell.ColorLightYellow).
-			SetSelectable(true).
-			SetAlign(tview.AlignLeft))
-		table.SetCell(row+1, 1, tview.NewTableCell(cpuUsage).
-			SetTextColor(tcell.ColorLightGreen).
-			SetAlign(tview.AlignRight))
-		table.SetCell(row+1, 2, tview.NewTableCell(memoryUsage).
-			SetTextColor(tcell.ColorLightBlue).
-			SetAlign(tview.AlignRight))
+// LayoutNodeTable lays out the rows of table again after view changed.
+func LayoutNodeTable(table *tview.Table, view *config.TableView) {
+	if rows := nodeTable.rows(table); len(rows) > 0 {
+		nodeTable.fill(table, view, rows)
 	}
+}
 
-	return table
+// NodeAt returns the name of the node shown in row of a table filled by
+// FillNodeTable.
+func NodeAt(table *tview.Table, row int) (string, bool) {
+	if row < 1 || row >= table.GetRowCount() {
+		return "", false
+	}
+	current, ok := table.GetCell(row, 0).GetReference().(nodeRow)
+	return current.node.Name, ok
 }
@@ -8,15 +8,92 @@
 package panels
 
 import (
+	"cmp"
 	"fmt"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/config"
 	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/pkg/metrics"
 	"github.com/rdmnl/kubepulse/utils"
 	"github.com/rivo/tview"
 )
 
-func SetupPodListPanel(client kubernetes.KubernetesClient) *tview.Table {
+// MemoryLimitWarning is the percentage of its memory limit above which a
+// container risks being killed, and its pod is shown in red.
+const MemoryLimitWarning = 90
+
+// podRow is a row of the pod table: a pod with its usage.
+type podRow struct {
+	pod     kubernetes.Pod
+	samples []kubernetes.Usage
+	// usage is the latest sample with requests and limits, nil when the pod
+	// has not been sampled.
+	usage *kubernetes.PodUsage
+	// cpu and memory come from the metrics API when there are no samples.
+	cpu    string
+	memory string
+}
+
+// podTable lays out the pod table. The trends come last, so a narrow panel
+// cuts them off before the values.
+var podTable = columnTable[podRow]{
+	columns: []column[podRow]{
+		{name: nameColumn, title: "Pod Name", cell: podNameCell, compare: func(a, b podRow) int {
+			return cmp.Or(cmp.Compare(a.pod.Name, b.pod.Name), cmp.Compare(a.pod.Namespace, b.pod.Namespace))
+		}},
+		{name: "namespace", title: "Namespace", cell: func(row podRow) *tview.TableCell {
+			return textCell(row.pod.Namespace, tcell.ColorLightGreen)
+		}, compare: func(a, b podRow) int { return cmp.Compare(a.pod.Namespace, b.pod.Namespace) }},
+		{name: "cpu", title: "CPU", cell: func(row podRow) *tview.TableCell {
+			if len(row.samples) == 0 {
+				return usageCell(row.cpu, tcell.ColorLightGreen)
+			}
+			return usageCell(kubernetes.FormatCPU(row.samples[len(row.samples)-1].CPU), tcell.ColorLightGreen)
+		}, compare: func(a, b podRow) int { return cmp.Compare(latestCPU(a.samples), latestCPU(b.samples)) }},
+		{name: "memory", title: "Memory", cell: func(row podRow) *tview.TableCell {
+			if len(row.samples) == 0 {
+				return usageCell(row.memory, tcell.ColorLightBlue)
+			}
+			return usageCell(kubernetes.FormatMemory(row.samples[len(row.samples)-1].Memory), tcell.ColorLightBlue)
+		}, compare: func(a, b podRow) int { return cmp.Compare(latestMemory(a.samples), latestMemory(b.samples)) }},
+		{name: "status", title: "Status", cell: func(row podRow) *tview.TableCell {
+			return textCell(row.pod.Status, podStatusColor(row.pod.Status))
+		}, compare: func(a, b podRow) int { return cmp.Compare(a.pod.Status, b.pod.Status) }},
+		{name: "restarts", title: "Restarts", cell: func(row podRow) *tview.TableCell {
+			color := tcell.ColorGray
+			if row.pod.Restarts > 0 {
+				color = tcell.ColorOrange
+			}
+			return tview.NewTableCell(fmt.Sprint(row.pod.Restarts)).SetTextColor(color).SetAlign(tview.AlignRight)
+		}, compare: func(a, b podRow) int { return cmp.Compare(a.pod.Restarts, b.pod.Restarts) }},
+		{name: "age", title: "Age", cell: func(row podRow) *tview.TableCell {
+			return ageCell(row.pod.Created)
+		}, compare: func(a, b podRow) int { return compareCreated(a.pod.Created, b.pod.Created) }},
+		podPercentColumn("cpuRequest", "CPU/Req", func(usage *kubernetes.PodUsage) (int64, int64) { return usage.CPU, usage.Requests.CPU }, false),
+		podPercentColumn("cpuLimit", "CPU/Lim", func(usage *kubernetes.PodUsage) (int64, int64) { return usage.CPU, usage.Limits.CPU }, true),
+		podPercentColumn("memoryRequest", "Mem/Req", func(usage *kubernetes.PodUsage) (int64, int64) { return usage.Memory, usage.Requests.Memory }, false),
+		podPercentColumn("memoryLimit", "Mem/Lim", func(usage *kubernetes.PodUsage) (int64, int64) { return usage.Memory, usage.Limits.Memory }, true),
+		{name: "cpuTrend", cell: func(row podRow) *tview.TableCell {
+			cpu, _ := UsageSeries(row.samples)
+			return trendCell(cpu, tcell.ColorLightGreen)
+		}},
+		{name: "memoryTrend", cell: func(row podRow) *tview.TableCell {
+			_, memory := UsageSeries(row.samples)
+			return trendCell(memory, tcell.ColorLightBlue)
+		}},
+		{name: "node", title: "Node", wide: true, cell: func(row podRow) *tview.TableCell {
+			return textCell(row.pod.NodeName, tcell.ColorWhite)
+		}, compare: func(a, b podRow) int { return cmp.Compare(a.pod.NodeName, b.pod.NodeName) }},
+		{name: "ip", title: "IP", wide: true, cell: func(row podRow) *tview.TableCell {
+			return textCell(row.pod.IP, tcell.ColorWhite)
+		}, compare: func(a, b podRow) int { return cmp.Compare(a.pod.IP, b.pod.IP) }},
+	},
+	key: func(row podRow) string { return row.pod.Namespace + "/" + row.pod.Name },
+}
+
+func SetupPodListPanel(client kubernetes.KubernetesClient, view *config.TableView) *tview.Table {
 	table := tview.NewTable()
 
 	table.SetBorders(false).
@@ -26,64 +103,179 @@ func SetupPodListPanel(client kubernetes.KubernetesClient) *tview.Table {
 		SetBorder(true).
 		SetBorderColor(tcell.ColorLightCyan)
 
-	table.SetCell(0, 0, tview.NewTableCell("Pod Name").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	table.SetCell(0, 1, tview.NewTableCell("Namespace").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	table.SetCell(0, 2, tview.NewTableCell("CPU").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-	table.SetCell(0, 3, tview.NewTableCell("Memory").
-		SetTextColor(tcell.ColorWhite).
-		SetSelectable(false).
-		SetAlign(tview.AlignCenter))
-
 	pods, err := client.GetPods()
 	if err != nil {
 		utils.Info(fmt.Sprintf("Error fetching pods: %v", err))
+		podTable.fill(table, view, nil)
+		ShowTableMessage(table, kubernetes.DescribeAccessError(err, "list", kubernetes.PodResource, "", client.Namespace()))
 		return table
 	}
 
-	for row, pod := range pods {
+	FillPodTable(table, client, nil, view, pods)
+	utils.Info("PodListPanel setup completed with Kubernetes data.")
+	return table
+}
+
+// FillPodTable replaces the rows of table with pods and their usage, laid
+// out as view says. Usage comes from the latest sample in history when there
+// is one, and from the metrics API otherwise; history may be nil.
+func FillPodTable(table *tview.Table, client kubernetes.KubernetesClient, history *metrics.History, view *config.TableView, pods []kubernetes.Pod) {
+	var rows []podRow
+	for _, pod := range pods {
 		if pod.Name == "" {
 			continue
 		}
 
-		cpuUsage, memoryUsage, err := client.GetPodMetrics(pod)
-		if err != nil {
-			cpuUsage = "N/A"
-			memoryUsage = "N/A"
-			utils.Info(fmt.Sprintf("Error fetching metrics for pod %s/%s: %v", pod.Namespace, pod.Name, err))
+		row := podRow{pod: pod}
+		if history != nil {
+			row.samples = history.Pod(pod)
 		}
+		if len(row.samples) > 0 {
+			if usage, ok := history.Latest(pod); ok {
+				row.usage = &usage
+			}
+		} else {
+			cpuUsage, memoryUsage, err := client.GetPodMetrics(pod)
+			if err != nil {
+				cpuUsage, memoryUsage = "N/A", "N/A"
+				utils.Warn(fmt.Sprintf("Error fetching metrics for pod %s/%s: %v", pod.Namespace, pod.Name, err))
+			}
+			row.cpu, row.memory = cpuUsage, memoryUsage
+		}
+		rows = append(rows, row)
+	}
+	podTable.fill(table, view, rows)
+}
 
-		table.SetCell(row+1, 0, tview.NewTableCell(pod.Name).
-			SetTextColor(tcell.ColorLightYellow).
-			SetBackgroundColor(tcell.ColorBlack).
-			SetSelectable(true).
-			SetAlign(tview.AlignLeft))
-
-		table.SetCell(row+1, 1, tview.NewTableCell(pod.Namespace).
-			SetTextColor(tcell.ColorLightGreen).
-			SetBackgroundColor(tcell.ColorBlack).
-			SetSelectable(false).
-			SetAlign(tview.AlignLeft))
-
-		table.SetCell(row+1, 2, tview.NewTableCell(cpuUsage).
-			SetTextColor(tcell.ColorLightGreen).
-			SetSelectable(false).
-			SetAlign(tview.AlignRight))
-
-		table.SetCell(row+1, 3, tview.NewTableCell(memoryUsage).
-			SetTextColor(tcell.ColorLightBlue).
-			SetSelectable(false).
-			SetAlign(tview.AlignRight))
+// RefreshPodUsage updates the usage of the pods in table from the latest
+// samples in history, without calling the API, and sorts them again.
+func RefreshPodUsage(table *tview.Table, history *metrics.History, view *config.TableView) {
+	rows := podTable.rows(table)
+	if len(rows) == 0 {
+		return
 	}
+	for i, row := range rows {
+		if samples := history.Pod(row.pod); len(samples) > 0 {
+			rows[i].samples = samples
+		}
+		if usage, ok := history.Latest(row.pod); ok {
+			rows[i].usage = &usage
+		}
+	}
+	podTable.fill(table, view, rows)
+}
 
-	utils.Info("PodListPanel setup completed with Kubernetes data.")
-	return table
+// LayoutPodTable lays out the rows of table again after view changed.
+func LayoutPodTable(table *tview.Table, view *config.TableView) {
+	if rows := podTable.rows(table); len(rows) > 0 {
+		podTable.fill(table, view, rows)
+	}
+}
+
+// PodAt returns the pod shown in row of a table filled by FillPodTable.
+func PodAt(table *tview.Table, row int) (kubernetes.Pod, bool) {
+	if row < 1 || row >= table.GetRowCount() {
+		return kubernetes.Pod{}, false
+	}
+	current, ok := table.GetCell(row, 0).GetReference().(podRow)
+	return current.pod, ok
+}
+
+// podNameCell shows the name of a pod, in red when a container is close to
+// its memory limit.
+func podNameCell(row podRow) *tview.TableCell {
+	color := tcell.ColorLightYellow
+	if row.usage != nil && NearMemoryLimit(*row.usage) {
+		color = tcell.ColorRed
+	}
+	return textCell(row.pod.Name, color).SetBackgroundColor(tcell.ColorBlack)
+}
+
+// podPercentColumn shows the usage of a pod as a percentage of one of its
+// requests or limits, which value returns.
+func podPercentColumn(name string, title string, value func(usage *kubernetes.PodUsage) (int64, int64), limit bool) column[podRow] {
+	percent := func(row podRow) int64 {
+		if row.usage == nil {
+			return -1
+		}
+		if percent, ok := kubernetes.Percent(value(row.usage)); ok {
+			return percent
+		}
+		return -1
+	}
+	return column[podRow]{
+		name:  name,
+		title: title,
+		cell: func(row podRow) *tview.TableCell {
+			if row.usage == nil {
+				return tview.NewTableCell("")
+			}
+			usage, total := value(row.usage)
+			return percentCell(usage, total, limit)
+		},
+		compare: func(a, b podRow) int { return cmp.Compare(percent(a), percent(b)) },
+	}
+}
+
+// podStatusColor highlights the pods that are not running or completed.
+func podStatusColor(status string) tcell.Color {
+	switch status {
+	case "Running", "Completed", "Succeeded":
+		return tcell.ColorLightGreen
+	case "Pending", "ContainerCreating", "Terminating":
+		return tcell.ColorYellow
+	default:
+		if strings.HasPrefix(status, "Init:") && !strings.Contains(status, "Err") && !strings.Contains(status, "BackOff") {
+			return tcell.ColorYellow
+		}
+		return tcell.ColorRed
+	}
+}
+
+// percentCell shows usage as a percentage of total with a gauge, or a dash
+// when total is unset. Usage above a request is common and shown in yellow,
+// while usage close to a limit is shown in yellow and then red.
+func percentCell(usage int64, total int64, limit bool) *tview.TableCell {
+	percent, ok := kubernetes.Percent(usage, total)
+	if !ok {
+		return tview.NewTableCell("-").
+			SetTextColor(tcell.ColorGray).
+			SetAlign(tview.AlignCenter)
+	}
+	return tview.NewTableCell(FormatPercent(percent)).
+		SetTextColor(PercentColor(percent, limit)).
+		SetAlign(tview.AlignRight)
+}
+
+// FormatPercent draws percent as a gauge followed by its value.
+func FormatPercent(percent int64) string {
+	return fmt.Sprintf("%s%4d%%", Gauge(percent, GaugeWidth), percent)
+}
+
+// PercentColor is the color of a percentage of a request, or of a limit when
+// limit is set.
+func PercentColor(percent int64, limit bool) tcell.Color {
+	switch {
+	case limit && percent >= MemoryLimitWarning:
+		return tcell.ColorRed
+	case limit && percent >= 75, !limit && percent > 100:
+		return tcell.ColorYellow
+	default:
+		return tcell.ColorLightGreen
+	}
+}
+
+// NearMemoryLimit reports whether the pod or one of its containers uses at
+// least MemoryLimitWarning percent of its memory limit. Limits apply to each
+// container, so a single one can be killed while the pod total looks fine.
+func NearMemoryLimit(usage kubernetes.PodUsage) bool {
+	if percent, ok := kubernetes.Percent(usage.Memory, usage.Limits.Memory); ok && percent >= MemoryLimitWarning {
+		return true
+	}
+	for _, container := range usage.Containers {
+		if percent, ok := kubernetes.Percent(container.Memory, container.Limits.Memory); ok && percent >= MemoryLimitWarning {
+			return true
+		}
+	}
+	return false
 }
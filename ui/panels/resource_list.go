@@ -0,0 +1,59 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package panels
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rivo/tview"
+)
+
+func SetupResourceListPanel() *tview.Table {
+	table := tview.NewTable()
+
+	table.SetBorders(false).
+		SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBackgroundColor(tcell.ColorBlack).
+		SetBorder(true).
+		SetBorderColor(tcell.ColorLightCyan)
+
+	return table
+}
+
+// RenderResourceTable fills table with the columns and rows returned by the
+// server-side Table printer. Each row keeps its ResourceRow as cell reference.
+func RenderResourceTable(table *tview.Table, resourceTable *kubernetes.ResourceTable) {
+	table.Clear()
+
+	for col, name := range resourceTable.Columns {
+		table.SetCell(0, col, tview.NewTableCell(name).
+			SetTextColor(tcell.ColorWhite).
+			SetSelectable(false).
+			SetAlign(tview.AlignCenter))
+	}
+
+	for row, resourceRow := range resourceTable.Rows {
+		for col, value := range resourceRow.Cells {
+			color := tcell.ColorLightGreen
+			if col == 0 {
+				color = tcell.ColorLightYellow
+			}
+			table.SetCell(row+1, col, tview.NewTableCell(tview.Escape(value)).
+				SetTextColor(color).
+				SetReference(resourceRow).
+				SetSelectable(col == 0).
+				SetAlign(tview.AlignLeft))
+		}
+	}
+
+	table.ScrollToBeginning()
+	if len(resourceTable.Rows) > 0 {
+		table.Select(1, 0)
+	}
+}
@@ -0,0 +1,115 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package panels
+
+import (
+	"strings"
+)
+
+// SparklineWidth is the width of the trend columns in the node and pod tables.
+const SparklineWidth = 8
+
+// blocks are the eighths of a character cell, from one eighth to full.
+var blocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws the last width values as one line of bars, scaled between
+// zero and the largest value, so a spike and a steady climb look different.
+// Shorter series are padded on the left to keep the bars right-aligned.
+func Sparkline(values []int64, width int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	peak := Peak(values)
+
+	var line strings.Builder
+	line.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, value := range values {
+		level := 0
+		if peak > 0 {
+			level = int(value * int64(len(blocks)-1) / peak)
+		}
+		line.WriteRune(blocks[level])
+	}
+	return line.String()
+}
+
+// Chart draws the last width values as bars height lines tall, scaled between
+// zero and the largest value. The lines are returned top to bottom.
+func Chart(values []int64, width int, height int) []string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	peak := Peak(values)
+	eighths := make([]int64, len(values))
+	for i, value := range values {
+		if peak > 0 {
+			eighths[i] = value * int64(height*len(blocks)) / peak
+		}
+	}
+
+	lines := make([]string, height)
+	for row := range lines {
+		floor := int64((height - 1 - row) * len(blocks))
+		var line strings.Builder
+		for _, filled := range eighths {
+			switch {
+			case filled-floor >= int64(len(blocks)):
+				line.WriteRune(blocks[len(blocks)-1])
+			case filled-floor > 0:
+				line.WriteRune(blocks[filled-floor-1])
+			case row == height-1:
+				// Keep a baseline, so idle periods are still visible.
+				line.WriteRune(blocks[0])
+			default:
+				line.WriteRune(' ')
+			}
+		}
+		lines[row] = line.String()
+	}
+	return lines
+}
+
+// Peak returns the largest of values, or zero.
+func Peak(values []int64) int64 {
+	var peak int64
+	for _, value := range values {
+		if value > peak {
+			peak = value
+		}
+	}
+	return peak
+}
+
+// GaugeWidth is the width of the bar of the percentage columns in the pod
+// table.
+const GaugeWidth = 4
+
+// partials are the eighths of a character cell filled from the left.
+var partials = []rune("▏▎▍▌▋▊▉█")
+
+// Gauge draws percent as a horizontal bar width cells long, full at 100%.
+// Larger values are drawn as a full bar.
+func Gauge(percent int64, width int) string {
+	eighths := min(max(percent, 0)*int64(width*len(partials))/100, int64(width*len(partials)))
+	var bar strings.Builder
+	for cell := 0; cell < width; cell++ {
+		filled := eighths - int64(cell*len(partials))
+		switch {
+		case filled >= int64(len(partials)):
+			bar.WriteRune(partials[len(partials)-1])
+		case filled > 0:
+			bar.WriteRune(partials[filled-1])
+		default:
+			bar.WriteRune('░')
+		}
+	}
+	return bar.String()
+}
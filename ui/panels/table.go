@@ -0,0 +1,71 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package panels
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rivo/tview"
+)
+
+// ShowTableMessage replaces the rows below the header of table with message,
+// for example to explain why a list could not be loaded.
+func ShowTableMessage(table *tview.Table, message string) {
+	for table.GetRowCount() > 1 {
+		table.RemoveRow(table.GetRowCount() - 1)
+	}
+	table.SetCell(1, 0, tview.NewTableCell(tview.Escape(message)).
+		SetTextColor(tcell.ColorRed).
+		SetSelectable(false).
+		SetExpansion(1))
+}
+
+// usageCell shows a CPU or memory value.
+func usageCell(text string, color tcell.Color) *tview.TableCell {
+	return tview.NewTableCell(text).
+		SetTextColor(color).
+		SetAlign(tview.AlignRight)
+}
+
+// trendCell draws values as a sparkline, or nothing when there are none.
+func trendCell(values []int64, color tcell.Color) *tview.TableCell {
+	if len(values) == 0 {
+		return tview.NewTableCell("")
+	}
+	return tview.NewTableCell(Sparkline(values, SparklineWidth)).
+		SetTextColor(color).
+		SetAlign(tview.AlignLeft)
+}
+
+// latestCPU is the CPU usage of the last of samples, -1 when there are none
+// so unknown usage sorts first.
+func latestCPU(samples []kubernetes.Usage) int64 {
+	if len(samples) == 0 {
+		return -1
+	}
+	return samples[len(samples)-1].CPU
+}
+
+// latestMemory is the memory usage of the last of samples, like latestCPU.
+func latestMemory(samples []kubernetes.Usage) int64 {
+	if len(samples) == 0 {
+		return -1
+	}
+	return samples[len(samples)-1].Memory
+}
+
+// UsageSeries splits samples into their CPU and memory values.
+func UsageSeries(samples []kubernetes.Usage) ([]int64, []int64) {
+	cpu := make([]int64, len(samples))
+	memory := make([]int64, len(samples))
+	for i, sample := range samples {
+		cpu[i] = sample.CPU
+		memory[i] = sample.Memory
+	}
+	return cpu, memory
+}
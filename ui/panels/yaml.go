@@ -0,0 +1,173 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package panels
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	yamlKeyColor     = "lightcyan"
+	yamlStringColor  = "lightgreen"
+	yamlLiteralColor = "yellow"
+	yamlCommentColor = "gray"
+	yamlSymbolColor  = "white"
+)
+
+var (
+	yamlKeyPattern     = regexp.MustCompile(`^(\s*)((?:- )*)("[^"]*"|'[^']*'|[^\s:#'"][^:#]*?):(\s|$)`)
+	yamlListPattern    = regexp.MustCompile(`^(\s*)((?:- )+|-$)`)
+	yamlLiteralPattern = regexp.MustCompile(`^(-?[0-9][0-9_.eE+-]*|true|false|null|~)$`)
+)
+
+// HighlightYAML converts a YAML document into tview color tags. Every match of
+// query (case-insensitive) is wrapped in a region named "match-<n>" so it can be
+// highlighted and scrolled to. It returns the tagged text and the number of matches.
+func HighlightYAML(manifest string, query string) (string, int) {
+	var builder strings.Builder
+	matches := 0
+	blockIndent := -1
+
+	for _, line := range strings.Split(strings.TrimRight(manifest, "\n"), "\n") {
+		colors := make([]string, len(line))
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if blockIndent >= 0 && (indent > blockIndent || strings.TrimSpace(line) == "") {
+			fill(colors, 0, len(line), yamlStringColor)
+		} else {
+			blockIndent = -1
+			colorYAMLLine(line, colors, &blockIndent)
+		}
+
+		writeSegments(&builder, line, colors, findMatches(line, query), &matches)
+		builder.WriteString("\n")
+	}
+
+	return builder.String(), matches
+}
+
+func colorYAMLLine(line string, colors []string, blockIndent *int) {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "#") {
+		fill(colors, 0, len(line), yamlCommentColor)
+		return
+	}
+
+	valueStart := 0
+	if m := yamlKeyPattern.FindStringSubmatchIndex(line); m != nil {
+		fill(colors, m[4], m[5], yamlSymbolColor)
+		fill(colors, m[6], m[7], yamlKeyColor)
+		fill(colors, m[7], m[7]+1, yamlSymbolColor)
+		valueStart = m[7] + 1
+	} else if m := yamlListPattern.FindStringSubmatchIndex(line); m != nil {
+		fill(colors, m[4], m[5], yamlSymbolColor)
+		valueStart = m[5]
+	}
+
+	value := strings.TrimSpace(line[valueStart:])
+	switch {
+	case value == "":
+	case strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">"):
+		fill(colors, valueStart, len(line), yamlSymbolColor)
+		*blockIndent = len(line) - len(strings.TrimLeft(line, " "))
+	case strings.HasPrefix(value, "#"):
+		fill(colors, valueStart, len(line), yamlCommentColor)
+	case yamlLiteralPattern.MatchString(value):
+		fill(colors, valueStart, len(line), yamlLiteralColor)
+	default:
+		fill(colors, valueStart, len(line), yamlStringColor)
+	}
+}
+
+// findMatches returns the byte ranges of every case-insensitive match of query in
+// line as consecutive start, end pairs.
+func findMatches(line string, query string) []int {
+	if query == "" {
+		return nil
+	}
+
+	haystack, needle := strings.ToLower(line), strings.ToLower(query)
+	if len(haystack) != len(line) {
+		haystack, needle = line, query
+	}
+
+	var ranges []int
+	for offset := 0; ; {
+		i := strings.Index(haystack[offset:], needle)
+		if i < 0 {
+			break
+		}
+		start := offset + i
+		ranges = append(ranges, start, start+len(needle))
+		offset = start + len(needle)
+	}
+	return ranges
+}
+
+// writeSegments writes line with a color tag whenever the color changes and a
+// region tag around each range in matchRanges.
+func writeSegments(builder *strings.Builder, line string, colors []string, matchRanges []int, matches *int) {
+	currentColor := ""
+	start := 0
+
+	flush := func(end int) {
+		if end > start {
+			builder.WriteString(escapeYAML(line[start:end]))
+		}
+		start = end
+	}
+
+	nextMatch := 0
+	inMatch := false
+	for i := 0; i < len(line); i++ {
+		if inMatch && matchRanges[nextMatch*2+1] == i {
+			flush(i)
+			builder.WriteString(`[""]`)
+			inMatch = false
+			nextMatch++
+		}
+		if !inMatch && nextMatch*2 < len(matchRanges) && matchRanges[nextMatch*2] == i {
+			flush(i)
+			fmt.Fprintf(builder, `["match-%d"]`, *matches)
+			*matches++
+			inMatch = true
+		}
+		color := colors[i]
+		if color == "" {
+			color = yamlSymbolColor
+		}
+		if color != currentColor {
+			flush(i)
+			fmt.Fprintf(builder, "[%s]", color)
+			currentColor = color
+		}
+	}
+	flush(len(line))
+	if inMatch {
+		builder.WriteString(`[""]`)
+	}
+	if currentColor != "" {
+		builder.WriteString("[-]")
+	}
+}
+
+func fill(colors []string, start int, end int, color string) {
+	for i := start; i < end && i < len(colors); i++ {
+		colors[i] = color
+	}
+}
+
+// escapeYAML escapes a piece of a line written between color and region
+// tags. tview.Escape only escapes brackets it sees closed, so a piece such as
+// "[a:b:c" would run into the tag that follows it. Following every "[" with
+// the empty style tag "[::]" ends it as plain text instead.
+func escapeYAML(text string) string {
+	return strings.ReplaceAll(text, "[", "[[::]")
+}
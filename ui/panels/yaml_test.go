@@ -0,0 +1,60 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package panels
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+)
+
+func TestHighlightYAML(t *testing.T) {
+	for _, test := range []struct {
+		manifest, query string
+		want            string
+		matches         int
+	}{
+		{"name: web", "", "[lightcyan]name[white]:[lightgreen] web[-]\n", 0},
+		{"replicas: 3\npaused: false", "", "[lightcyan]replicas[white]:[yellow] 3[-]\n[lightcyan]paused[white]:[yellow] false[-]\n", 0},
+		{"# managed by helm", "", "[gray]# managed by helm[-]\n", 0},
+		{"- name: app", "", "[white]- [lightcyan]name[white]:[lightgreen] app[-]\n", 0},
+		{"script: |\n  echo [red]", "", "[lightcyan]script[white]: |[-]\n[lightgreen]  echo [[::]red][-]\n", 0},
+		{"image: Nginx:1.27\nname: nginx", "NGINX", "[lightcyan]image[white]:[lightgreen] [\"match-0\"]Nginx[\"\"]:1.27[-]\n" +
+			"[lightcyan]name[white]:[lightgreen] [\"match-1\"]nginx[\"\"][-]\n", 2},
+	} {
+		got, matches := HighlightYAML(test.manifest, test.query)
+		if got != test.want || matches != test.matches {
+			t.Errorf("HighlightYAML(%q, %q) = %q, %d, want %q, %d", test.manifest, test.query, got, matches, test.want, test.matches)
+		}
+	}
+}
+
+// TestHighlightYAMLKeepsBrackets renders highlighted lines the way the details
+// panel does, so brackets in the manifest or the query cannot turn into tags.
+func TestHighlightYAMLKeepsBrackets(t *testing.T) {
+	for _, test := range []struct {
+		manifest, query string
+	}{
+		{`args: ["--port", "8080"]`, `["--`},
+		{`args: [red]`, `[red`},
+		{`args: [red]`, `d]`},
+		{`label: a[b:c:d`, `a[b`},
+		{`label: [::b::# x`, `x`},
+		{`"[key]": [value]`, `y]": [v`},
+	} {
+		text, matches := HighlightYAML(test.manifest, test.query)
+		view := tview.NewTextView().SetDynamicColors(true).SetRegions(true).SetText(text)
+		if shown := strings.TrimSuffix(view.GetText(true), "\n"); shown != test.manifest {
+			t.Errorf("HighlightYAML(%q, %q) shows %q", test.manifest, test.query, shown)
+		}
+		if region := view.GetRegionText("match-0"); matches != 1 || region != test.query {
+			t.Errorf("HighlightYAML(%q, %q) highlights %q in %d matches, want the query once", test.manifest, test.query, region, matches)
+		}
+	}
+}
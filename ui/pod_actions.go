@@ -0,0 +1,77 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/utils"
+)
+
+func (controller *UIController) HandleDeletePod(force bool) {
+	pod, err := controller.getSelectedPodObject()
+	if err != nil {
+		errorMessage := fmt.Sprintf("Cannot delete: %v", err)
+		utils.Warn(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+	if !controller.requireAccess("delete", kubernetes.PodResource, "", pod.Namespace) {
+		return
+	}
+
+	title := "Delete Pod"
+	confirmLabel := "Delete"
+	message := fmt.Sprintf("Delete pod %s/%s?\n\nThe pod is terminated gracefully.", pod.Namespace, pod.Name)
+	if force {
+		title = "Force Delete Pod"
+		confirmLabel = "Force Delete"
+		message = fmt.Sprintf("Force delete pod %s/%s?\n\nThe pod is removed immediately with a grace period of 0. Its containers may keep running on the node until the kubelet notices.", pod.Namespace, pod.Name)
+	}
+
+	controller.showConfirm(title, message, confirmLabel, pod.Name, func() {
+		err := controller.KubernetesClient.DeletePod(pod, force)
+		controller.reportPodAction(pod, "deleted", kubernetes.DescribeError, err)
+	})
+}
+
+func (controller *UIController) HandleEvictPod() {
+	pod, err := controller.getSelectedPodObject()
+	if err != nil {
+		errorMessage := fmt.Sprintf("Cannot evict: %v", err)
+		utils.Warn(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+	if !controller.requireAccess("create", kubernetes.PodResource, "eviction", pod.Namespace) {
+		return
+	}
+
+	message := fmt.Sprintf("Evict pod %s/%s?\n\nThe eviction is refused if it would violate a PodDisruptionBudget.", pod.Namespace, pod.Name)
+	controller.showConfirm("Evict Pod", message, "Evict", pod.Name, func() {
+		err := controller.KubernetesClient.EvictPod(pod)
+		controller.reportPodAction(pod, "evicted", kubernetes.DescribeEvictionError, err)
+	})
+}
+
+// reportPodAction shows the outcome of a pod action in the status bar, with
+// errors explained by describe, and refreshes the pod list on success.
+func (controller *UIController) reportPodAction(pod kubernetes.Pod, action string, describe func(error) string, err error) {
+	if err != nil {
+		errorMessage := fmt.Sprintf("Pod %s/%s not %s: %s", pod.Namespace, pod.Name, action, strings.ReplaceAll(describe(err), "\n", " "))
+		utils.Warn(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+
+	utils.Info(fmt.Sprintf("Pod %s/%s %s", pod.Namespace, pod.Name, action))
+	controller.refreshPods()
+	controller.UIManager.StatusBar.SetText(fmt.Sprintf("[green]Pod %s/%s %s", pod.Namespace, pod.Name, action))
+}
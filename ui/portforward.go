@@ -0,0 +1,196 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/utils"
+	"github.com/rivo/tview"
+)
+
+const portForwardRefreshInterval = time.Second
+
+func (controller *UIController) HandlePortForward() {
+	ref, err := controller.getSelectedObject()
+	if err != nil {
+		errorMessage := fmt.Sprintf("Cannot port-forward: %v", err)
+		utils.Warn(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+	if !controller.requireAccess("create", kubernetes.PodResource, "portforward", ref.Namespace) {
+		return
+	}
+
+	target := kubernetes.PortForwardTarget{Namespace: ref.Namespace, Name: ref.Name}
+	switch {
+	case ref.Resource.Group == "" && ref.Resource.Name == "pods":
+	case ref.Resource.Group == "" && ref.Resource.Name == "services":
+		target.Service = true
+	default:
+		controller.UIManager.StatusBar.SetText(fmt.Sprintf("[red]Cannot port-forward to %s, select a pod or service", ref))
+		return
+	}
+
+	ports, err := controller.KubernetesClient.ListTargetPorts(target)
+	if err != nil {
+		errorMessage := fmt.Sprintf("Error fetching ports of %s: %v", ref, err)
+		utils.Error(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+
+	defaultPort := ""
+	if len(ports) > 0 {
+		defaultPort = strconv.Itoa(ports[0])
+	}
+
+	form := tview.NewForm().
+		AddInputField("Remote port:", defaultPort, 8, tview.InputFieldInteger, nil).
+		AddInputField("Local port:", defaultPort, 8, tview.InputFieldInteger, nil)
+
+	form.AddButton("Start", func() {
+		remotePort, err := strconv.Atoi(form.GetFormItem(0).(*tview.InputField).GetText())
+		if err != nil || remotePort <= 0 || remotePort > 65535 {
+			controller.UIManager.StatusBar.SetText("[red]Invalid remote port")
+			return
+		}
+		localPort, err := strconv.Atoi(form.GetFormItem(1).(*tview.InputField).GetText())
+		if err != nil || localPort < 0 || localPort > 65535 {
+			controller.UIManager.StatusBar.SetText("[red]Invalid local port")
+			return
+		}
+
+		controller.closeModal()
+		target.RemotePort = remotePort
+		forward, err := controller.KubernetesClient.StartPortForward(target, localPort)
+		if err != nil {
+			errorMessage := fmt.Sprintf("Error starting port-forward to %s: %v", target, err)
+			utils.Error(errorMessage)
+			controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+			return
+		}
+		controller.UIManager.StatusBar.SetText(fmt.Sprintf("[green]Forwarding 127.0.0.1:%d -> %s (':pf' to manage)", forward.LocalPort, target))
+	}).
+		AddButton("Cancel", func() {
+			controller.closeModal()
+		})
+
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf("Port-forward %s", ref)).
+		SetTitleAlign(tview.AlignCenter)
+
+	controller.showModal(form, 60, 9)
+}
+
+// ShowPortForwards lists the active port-forwards and refreshes their status
+// while open. Forwards keep running in the background when it is closed.
+func (controller *UIController) ShowPortForwards() {
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBackgroundColor(tcell.ColorBlack).
+		SetBorder(true).
+		SetBorderColor(tcell.ColorLightGreen).
+		SetTitle(" Port-forwards ('x' Stop | Esc Close) ").
+		SetTitleAlign(tview.AlignLeft)
+
+	controller.renderPortForwards(table)
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if (event.Key() == tcell.KeyRune && event.Rune() == 'x') || event.Key() == tcell.KeyDelete {
+			row, _ := table.GetSelection()
+			forward, ok := table.GetCell(row, 0).GetReference().(kubernetes.PortForward)
+			if !ok {
+				return nil
+			}
+			if err := controller.KubernetesClient.StopPortForward(forward.ID); err != nil {
+				utils.Warn(fmt.Sprintf("Error stopping port-forward %d: %v", forward.ID, err))
+			}
+			controller.renderPortForwards(table)
+			return nil
+		}
+		return event
+	})
+
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(portForwardRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				controller.Application.QueueUpdateDraw(func() {
+					controller.renderPortForwards(table)
+				})
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	controller.showModal(table, 0, 0)
+	controller.modalCleanup = func() { close(stop) }
+}
+
+func (controller *UIController) renderPortForwards(table *tview.Table) {
+	selected, _ := table.GetSelection()
+	table.Clear()
+
+	headers := []string{"Local", "Target", "Pod", "In", "Out", "Status"}
+	for col, header := range headers {
+		table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorWhite).
+			SetSelectable(false).
+			SetAlign(tview.AlignCenter))
+	}
+
+	forwards := controller.KubernetesClient.ListPortForwards()
+	if len(forwards) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No active port-forwards. Press 'F' on a pod or service to start one.").
+			SetTextColor(tcell.ColorGray).
+			SetSelectable(false))
+		return
+	}
+
+	for row, forward := range forwards {
+		statusColor := tcell.ColorLightGreen
+		status := forward.Status
+		if forward.Status != kubernetes.PortForwardActive {
+			statusColor = tcell.ColorYellow
+		}
+		if forward.Error != "" {
+			status = fmt.Sprintf("%s: %s", forward.Status, forward.Error)
+		}
+
+		cells := []*tview.TableCell{
+			tview.NewTableCell(fmt.Sprintf("127.0.0.1:%d", forward.LocalPort)).SetTextColor(tcell.ColorLightYellow),
+			tview.NewTableCell(forward.Target.String()).SetTextColor(tcell.ColorLightCyan),
+			tview.NewTableCell(forward.Pod).SetTextColor(tcell.ColorLightGreen),
+			tview.NewTableCell(utils.FormatBytes(forward.BytesIn)).SetTextColor(tcell.ColorLightBlue).SetAlign(tview.AlignRight),
+			tview.NewTableCell(utils.FormatBytes(forward.BytesOut)).SetTextColor(tcell.ColorLightBlue).SetAlign(tview.AlignRight),
+			tview.NewTableCell(tview.Escape(status)).SetTextColor(statusColor),
+		}
+		for col, cell := range cells {
+			table.SetCell(row+1, col, cell.SetReference(forward))
+		}
+	}
+
+	if selected < 1 {
+		selected = 1
+	}
+	if selected >= table.GetRowCount() {
+		selected = table.GetRowCount() - 1
+	}
+	table.Select(selected, 0)
+}
@@ -0,0 +1,103 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const defaultProtectionColor = "red"
+
+// updateHeader shows the current context and the cluster health in the
+// header and makes protected contexts and read-only mode stand out.
+func (controller *UIController) updateHeader() {
+	header := controller.UIManager.Header
+	contextName := controller.KubernetesClient.ContextName()
+	text := fmt.Sprintf(" KubePulse %s | Context: %s ", Version, tview.Escape(contextName))
+	if summary := controller.clusterSummary; summary != nil && summary.ServerVersion != "" {
+		text += fmt.Sprintf("| Kubernetes %s ", tview.Escape(summary.ServerVersion))
+	}
+	if controller.KubernetesClient.ReadOnly() {
+		text += "| [::b]READ-ONLY[::-] "
+	}
+	health := "\n" + controller.clusterHealth()
+
+	protection := controller.Protection
+	if protection == nil {
+		header.SetText(text + health).
+			SetTextColor(tcell.ColorLightCyan).
+			SetBorderColor(tcell.ColorWhite)
+		return
+	}
+
+	colorName := protection.Color
+	if colorName == "" {
+		colorName = defaultProtectionColor
+	}
+	color := tcell.GetColor(colorName)
+	banner := protection.Banner
+	if banner == "" {
+		banner = fmt.Sprintf("PROTECTED CONTEXT %s", contextName)
+	}
+
+	header.SetText(fmt.Sprintf("[::b]⚠ %s ⚠[::-] |%s%s", tview.Escape(banner), text, health)).
+		SetTextColor(color).
+		SetBorderColor(color)
+}
+
+// requiresTypedName reports whether changes in the current context must be
+// confirmed by typing the name of the object.
+func (controller *UIController) requiresTypedName() bool {
+	return controller.Protection != nil && controller.Protection.RequiresTypedName()
+}
+
+// confirmProtected runs action right away, unless the context is protected and
+// the user first has to type name to confirm it.
+func (controller *UIController) confirmProtected(title string, name string, action func()) {
+	if !controller.requiresTypedName() {
+		action()
+		return
+	}
+	message := fmt.Sprintf("%s is a protected context.", controller.KubernetesClient.ContextName())
+	controller.showTypedConfirm(title, message, "Confirm", name, action)
+}
+
+// showTypedConfirm asks the user to type name before confirmed runs.
+func (controller *UIController) showTypedConfirm(title string, message string, confirmLabel string, name string, confirmed func()) {
+	text := tview.NewTextView().
+		SetWrap(true).
+		SetText(fmt.Sprintf("%s\n\nType %q to confirm.", message, name))
+
+	form := tview.NewForm().
+		AddInputField("Name:", "", 40, nil, nil)
+	form.AddButton(confirmLabel, func() {
+		if form.GetFormItem(0).(*tview.InputField).GetText() != name {
+			controller.UIManager.StatusBar.SetText(fmt.Sprintf("[red]Type %q exactly to confirm", name))
+			return
+		}
+		controller.closeModal()
+		confirmed()
+	}).
+		AddButton(cancelButton, func() {
+			controller.closeModal()
+		})
+
+	dialog := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(text, 0, 1, false).
+		AddItem(form, 5, 0, true)
+	dialog.SetBorder(true).
+		SetBorderColor(tcell.ColorRed).
+		SetTitle(" " + title + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	controller.showModal(dialog, 70, 14)
+}
@@ -0,0 +1,166 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/ui/panels"
+	"github.com/rdmnl/kubepulse/utils"
+	"github.com/rivo/tview"
+)
+
+func (controller *UIController) HandleCommandPrompt() {
+	input := tview.NewInputField().
+		SetLabel(":").
+		SetFieldWidth(0)
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		command := input.GetText()
+		controller.closeModal()
+		if key == tcell.KeyEnter {
+			controller.runCommand(command)
+		}
+	})
+
+	input.SetBorder(true).
+		SetTitle("Command").
+		SetTitleAlign(tview.AlignLeft)
+
+	controller.showModal(input, 60, 3)
+}
+
+func (controller *UIController) runCommand(command string) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return
+	}
+
+	switch fields[0] {
+	case "resource", "res":
+		if len(fields) == 1 {
+			controller.showResourcePicker()
+			return
+		}
+		resource, err := controller.KubernetesClient.FindAPIResource(fields[1])
+		if err != nil {
+			errorMessage := fmt.Sprintf("Error finding resource %s: %v", fields[1], err)
+			utils.Warn(errorMessage)
+			controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+			return
+		}
+		controller.showResource(resource)
+	case "pf", "portforwards":
+		controller.ShowPortForwards()
+	case "history", "audit":
+		controller.ShowHistory()
+	case "namespaces", "ns":
+		controller.ShowNamespaceOverview()
+	case "rightsize", "rsz":
+		controller.ShowRightSizing()
+	default:
+		errorMessage := fmt.Sprintf("Unknown command: %s", fields[0])
+		utils.Warn(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+	}
+}
+
+func (controller *UIController) showResourcePicker() {
+	resources, err := controller.KubernetesClient.ListAPIResources()
+	if err != nil {
+		errorMessage := fmt.Sprintf("Error discovering API resources: %v", err)
+		utils.Warn(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+
+	list := tview.NewList().ShowSecondaryText(false)
+	for _, resource := range resources {
+		resource := resource
+		list.AddItem(fmt.Sprintf("%s [gray](%s)", resource.String(), resource.Kind), "", 0, func() {
+			controller.closeModal()
+			controller.showResource(resource)
+		})
+	}
+	list.SetDoneFunc(func() {
+		controller.closeModal()
+	})
+
+	list.SetBorder(true).
+		SetTitle("Select Resource").
+		SetTitleAlign(tview.AlignCenter)
+
+	controller.showModal(list, 60, 0)
+}
+
+func (controller *UIController) showResource(resource kubernetes.APIResource) {
+	table, err := controller.KubernetesClient.GetResourceTable(resource)
+	if err != nil {
+		errorMessage := fmt.Sprintf("Error listing %s: %v", resource, err)
+		utils.Warn(errorMessage)
+		table = &kubernetes.ResourceTable{Resource: resource, Columns: []string{"Name"}}
+		panels.RenderResourceTable(controller.UIManager.ResourceListPanel, table)
+		panels.ShowTableMessage(controller.UIManager.ResourceListPanel, kubernetes.DescribeAccessError(err, "list", resource, "", controller.KubernetesClient.Namespace()))
+		controller.UIManager.ActiveResource = &resource
+		controller.setMainList(controller.UIManager.ResourceListPanel)
+		controller.setPanelFocus(0)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+
+	panels.RenderResourceTable(controller.UIManager.ResourceListPanel, table)
+	controller.UIManager.ActiveResource = &resource
+	controller.setMainList(controller.UIManager.ResourceListPanel)
+	controller.setPanelFocus(0)
+	utils.Info(fmt.Sprintf("Displayed %d %s", len(table.Rows), resource))
+}
+
+func (controller *UIController) showPodList() {
+	if controller.UIManager.ActiveResource == nil {
+		return
+	}
+	controller.UIManager.ActiveResource = nil
+	controller.setMainList(controller.UIManager.PodListPanel)
+}
+
+// setMainList swaps the table shown between the node list and the details panel.
+func (controller *UIController) setMainList(table *tview.Table) {
+	column := controller.UIManager.ListColumn
+	column.Clear().
+		AddItem(controller.UIManager.NodeListPanel, 0, 1, false).
+		AddItem(table, 0, 2, true).
+		AddItem(controller.UIManager.DetailsPanel, 0, 1, false)
+}
+
+func (controller *UIController) mainListPanel() *tview.Table {
+	if controller.UIManager.ActiveResource != nil {
+		return controller.UIManager.ResourceListPanel
+	}
+	return controller.UIManager.PodListPanel
+}
+
+func (controller *UIController) HandleResourceSelection() {
+	ref, err := controller.getSelectedObject()
+	if err != nil {
+		utils.Warn(err.Error())
+		return
+	}
+
+	if err := controller.showDetails(ref); err != nil {
+		errorMessage := fmt.Sprintf("Error fetching %s: %v", ref, err)
+		utils.Error(errorMessage)
+		controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+		return
+	}
+
+	controller.setPanelFocus(2)
+	utils.Info(fmt.Sprintf("Updated details panel for %s", ref))
+}
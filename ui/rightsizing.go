@@ -0,0 +1,208 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/pkg/metrics"
+	"github.com/rivo/tview"
+)
+
+// rightSizing is the recommendation for a container of a workload.
+type rightSizing struct {
+	container    metrics.WorkloadContainer
+	recommended  metrics.Recommendation
+	provisioning metrics.Provisioning
+	severity     float64
+}
+
+// recommendOptions returns the right-sizing options from the configuration.
+func (controller *UIController) recommendOptions() metrics.RecommendOptions {
+	options := metrics.RecommendOptions{Percentile: metrics.DefaultPercentile, Headroom: metrics.DefaultHeadroom}
+	if rightSizing := controller.Config.RightSizing; rightSizing != nil {
+		if rightSizing.Percentile != nil {
+			options.Percentile = *rightSizing.Percentile
+		}
+		if rightSizing.Headroom != nil {
+			options.Headroom = *rightSizing.Headroom
+		}
+	}
+	return options
+}
+
+// rightSize recommends requests and limits for the workload containers in
+// namespace, or in every namespace when it is empty. The stored series of a
+// container is used when it holds more samples than the session history,
+// with the peaks of its minutes for the limits. Containers with too few
+// samples are left out.
+func (controller *UIController) rightSize(namespace string) []rightSizing {
+	options := controller.recommendOptions()
+	var results []rightSizing
+	for _, container := range controller.Metrics.WorkloadContainers(namespace) {
+		var peaks []kubernetes.Usage
+		if controller.MetricsStore != nil {
+			key := metrics.ContainerSeries(container.Namespace, container.Workload, container.Container)
+			since := time.Now().Add(-controller.MetricsStore.Retention())
+			if stored := controller.MetricsStore.Series(key, since, time.Minute); len(stored) > len(container.Samples) {
+				container.Samples = stored
+				peaks = controller.MetricsStore.Peaks(key, since, time.Minute)
+			}
+		}
+		recommendation, ok := metrics.Recommend(container.Samples, peaks, options)
+		if !ok {
+			continue
+		}
+		provisioning, severity := metrics.Classify(container.Requests, recommendation.Requests)
+		results = append(results, rightSizing{container, recommendation, provisioning, severity})
+	}
+	return results
+}
+
+// rightSizingSection lists the recommendations for the containers of the
+// workload owning pod, for the details panel.
+func (controller *UIController) rightSizingSection(pod kubernetes.Pod) string {
+	workload := controller.Metrics.Workload(pod)
+	if workload == "" {
+		return ""
+	}
+	var results []rightSizing
+	for _, result := range controller.rightSize(pod.Namespace) {
+		if result.container.Workload == workload {
+			results = append(results, result)
+		}
+	}
+	if len(results) == 0 {
+		return ""
+	}
+
+	nameWidth := 0
+	for _, result := range results {
+		nameWidth = max(nameWidth, len(result.container.Container))
+	}
+	section := fmt.Sprintf("[yellow::b]Right-sizing[-::-] [gray](%s, %d samples over %s%s)[-]\n",
+		describeOptions(controller.recommendOptions()), results[0].recommended.Samples, formatSpan(results[0].recommended.Span), lowConfidence(results[0].recommended))
+	for _, result := range results {
+		indent := strings.Repeat(" ", nameWidth)
+		section += fmt.Sprintf("  [green]%s[-]%s [lightcyan]CPU[-]     request %s  limit %s  [%s]%s[-]\n",
+			tview.Escape(result.container.Container), strings.Repeat(" ", nameWidth-len(result.container.Container)),
+			formatChange(result.container.Requests.CPU, result.recommended.Requests.CPU, kubernetes.FormatCPU),
+			formatChange(result.container.Limits.CPU, result.recommended.Limits.CPU, kubernetes.FormatCPU),
+			provisioningColor(result.provisioning), result.provisioning)
+		section += fmt.Sprintf("  %s [lightcyan]Memory[-]  request %s  limit %s\n", indent,
+			formatChange(result.container.Requests.Memory, result.recommended.Requests.Memory, kubernetes.FormatMemory),
+			formatChange(result.container.Limits.Memory, result.recommended.Limits.Memory, kubernetes.FormatMemory))
+	}
+	return section + "\n"
+}
+
+// ShowRightSizing lists the recommendations for every workload container in
+// the current namespace, the most under-provisioned first and then the most
+// over-provisioned.
+func (controller *UIController) ShowRightSizing() {
+	namespace := controller.KubernetesClient.Namespace()
+	results := controller.rightSize(namespace)
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].provisioning != results[j].provisioning {
+			return results[i].provisioning > results[j].provisioning
+		}
+		return results[i].severity > results[j].severity
+	})
+
+	scope := namespace
+	if scope == "" {
+		scope = "all namespaces"
+	}
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBackgroundColor(tcell.ColorBlack).
+		SetBorder(true).
+		SetBorderColor(tcell.ColorLightGreen).
+		SetTitle(fmt.Sprintf(" Right-sizing: %s (%s) (Esc Close) ", scope, describeOptions(controller.recommendOptions()))).
+		SetTitleAlign(tview.AlignLeft)
+
+	// The namespace column is only needed when listing all of them.
+	headers := []string{"Workload", "Container", "Verdict", "CPU Req", "CPU Lim", "Mem Req", "Mem Lim", "Samples"}
+	if namespace == "" {
+		headers = append([]string{"Namespace"}, headers...)
+	}
+	for col, header := range headers {
+		table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorWhite).
+			SetSelectable(false).
+			SetAlign(tview.AlignCenter))
+	}
+
+	if len(results) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell(fmt.Sprintf("Not enough samples yet. Recommendations need %d samples of a container.", metrics.MinRecommendationSamples)).
+			SetTextColor(tcell.ColorGray).
+			SetSelectable(false))
+	}
+
+	for row, result := range results {
+		color := provisioningColor(result.provisioning)
+		cells := []*tview.TableCell{
+			tview.NewTableCell(tview.Escape(result.container.Workload)).SetTextColor(tcell.ColorLightYellow),
+			tview.NewTableCell(tview.Escape(result.container.Container)).SetTextColor(tcell.ColorLightCyan),
+			tview.NewTableCell(result.provisioning.String()).SetTextColor(color),
+			tview.NewTableCell(formatChange(result.container.Requests.CPU, result.recommended.Requests.CPU, kubernetes.FormatCPU)),
+			tview.NewTableCell(formatChange(result.container.Limits.CPU, result.recommended.Limits.CPU, kubernetes.FormatCPU)),
+			tview.NewTableCell(formatChange(result.container.Requests.Memory, result.recommended.Requests.Memory, kubernetes.FormatMemory)),
+			tview.NewTableCell(formatChange(result.container.Limits.Memory, result.recommended.Limits.Memory, kubernetes.FormatMemory)),
+			tview.NewTableCell(fmt.Sprintf("%d over %s%s", result.recommended.Samples, formatSpan(result.recommended.Span), lowConfidence(result.recommended))).SetTextColor(tcell.ColorGray),
+		}
+		if namespace == "" {
+			cells = append([]*tview.TableCell{tview.NewTableCell(tview.Escape(result.container.Namespace)).SetTextColor(tcell.ColorLightGreen)}, cells...)
+		}
+		for col, cell := range cells {
+			table.SetCell(row+1, col, cell)
+		}
+	}
+
+	controller.showModal(table, 0, 0)
+}
+
+// lowConfidence flags a recommendation whose samples span too short a time
+// to trust it.
+func lowConfidence(recommendation metrics.Recommendation) string {
+	if recommendation.LowConfidence() {
+		return ", [yellow]low confidence[gray]"
+	}
+	return ""
+}
+
+func describeOptions(options metrics.RecommendOptions) string {
+	return fmt.Sprintf("p%d + %d%% headroom", options.Percentile, options.Headroom)
+}
+
+// formatChange shows a current value next to its recommendation, with a dash
+// for unset values.
+func formatChange(current int64, recommended int64, format func(int64) string) string {
+	from := "-"
+	if current > 0 {
+		from = format(current)
+	}
+	return from + " → " + format(recommended)
+}
+
+func provisioningColor(provisioning metrics.Provisioning) tcell.Color {
+	switch provisioning {
+	case metrics.UnderProvisioned:
+		return tcell.ColorRed
+	case metrics.OverProvisioned:
+		return tcell.ColorYellow
+	default:
+		return tcell.ColorLightGreen
+	}
+}
@@ -0,0 +1,80 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+)
+
+func TestRightSizingRanksWorkloads(t *testing.T) {
+	client := newFakeClient()
+	ui := newTestUI(t, client)
+	start := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	// web requests far more than it uses, api far less.
+	ui.sync(func() {
+		for i := 0; i < 10; i++ {
+			at := start.Add(time.Duration(i) * metricsInterval)
+			client.mu.Lock()
+			client.podUsage = []kubernetes.PodUsage{
+				{
+					Pod: kubernetes.Pod{Name: "web-7d4b9", Namespace: "default"}, Workload: "Deployment/web",
+					Usage: kubernetes.Usage{Time: at, CPU: 100, Memory: 200 << 20},
+					Containers: []kubernetes.ContainerUsage{{
+						Name: "app", Usage: kubernetes.Usage{Time: at, CPU: int64(80 + 2*i), Memory: 200 << 20},
+						Requests: kubernetes.Resources{CPU: 500, Memory: 1 << 30},
+					}},
+				},
+				{
+					Pod: kubernetes.Pod{Name: "api-5c8f2", Namespace: "default"}, Workload: "Deployment/api",
+					Usage: kubernetes.Usage{Time: at, CPU: 120, Memory: 200 << 20},
+					Containers: []kubernetes.ContainerUsage{{
+						Name: "app", Usage: kubernetes.Usage{Time: at, CPU: 120, Memory: 200 << 20},
+						Requests: kubernetes.Resources{CPU: 50, Memory: 64 << 20},
+						Limits:   kubernetes.Resources{Memory: 256 << 20},
+					}},
+				},
+			}
+			client.mu.Unlock()
+			ui.controller.sampleMetrics()
+		}
+		ui.controller.refreshUsage()
+	})
+	ui.press(tcell.KeyEnter)
+
+	var details string
+	ui.sync(func() {
+		details = ui.controller.UIManager.DetailsPanel.GetText(true)
+	})
+	for _, want := range []string{
+		"Right-sizing (p90 + 15% headroom, 10 samples over 2m, low confidence)",
+		"app CPU     request 500m → 110m  limit - → 115m  Over-provisioned",
+		"Memory  request 1024Mi → 230Mi  limit - → 230Mi",
+	} {
+		if !strings.Contains(details, want) {
+			t.Errorf("details panel is missing %q:\n%s", want, details)
+		}
+	}
+
+	ui.typeRunes(":rightsize")
+	ui.press(tcell.KeyEnter)
+	ui.assertContains(
+		"Right-sizing: default (p90 + 15% headroom)",
+		"Deployment/api app       Under-provisioned 50m → 140m  - → 140m",
+		"Deployment/web app       Over-provisioned  500m → 110m - → 115m",
+	)
+	screen := ui.text()
+	if strings.Index(screen, "Deployment/api") > strings.Index(screen, "Deployment/web") {
+		t.Errorf("the under-provisioned workload is not listed first:\n%s", screen)
+	}
+}
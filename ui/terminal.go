@@ -0,0 +1,60 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"golang.org/x/term"
+)
+
+// terminalSizeQueue reports the size of the local terminal to an exec session
+// whenever it changes.
+type terminalSizeQueue struct {
+	fd     int
+	resize chan kubernetes.TerminalSize
+	done   chan struct{}
+}
+
+func newTerminalSizeQueue(fd int) *terminalSizeQueue {
+	queue := &terminalSizeQueue{
+		fd:     fd,
+		resize: make(chan kubernetes.TerminalSize, 1),
+		done:   make(chan struct{}),
+	}
+	queue.update()
+	go queue.monitor()
+	return queue
+}
+
+func (queue *terminalSizeQueue) Next() *kubernetes.TerminalSize {
+	select {
+	case size := <-queue.resize:
+		return &size
+	case <-queue.done:
+		return nil
+	}
+}
+
+func (queue *terminalSizeQueue) Stop() {
+	close(queue.done)
+}
+
+// update queues the current terminal size, replacing a size that has not been
+// consumed yet.
+func (queue *terminalSizeQueue) update() {
+	width, height, err := term.GetSize(queue.fd)
+	if err != nil {
+		return
+	}
+
+	select {
+	case <-queue.resize:
+	default:
+	}
+	queue.resize <- kubernetes.TerminalSize{Width: uint16(width), Height: uint16(height)}
+}
@@ -0,0 +1,37 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+//go:build windows
+
+package ui
+
+import (
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Windows has no SIGWINCH, so the console size is polled instead.
+func (queue *terminalSizeQueue) monitor() {
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	lastWidth, lastHeight, _ := term.GetSize(queue.fd)
+	for {
+		select {
+		case <-ticker.C:
+			width, height, err := term.GetSize(queue.fd)
+			if err != nil || (width == lastWidth && height == lastHeight) {
+				continue
+			}
+			lastWidth, lastHeight = width, height
+			queue.update()
+		case <-queue.done:
+			return
+		}
+	}
+}
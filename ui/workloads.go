@@ -0,0 +1,336 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rdmnl/kubepulse/pkg/kubernetes"
+	"github.com/rdmnl/kubepulse/ui/panels"
+	"github.com/rdmnl/kubepulse/utils"
+	"github.com/rivo/tview"
+)
+
+const rolloutRefreshInterval = time.Second
+
+func (controller *UIController) HandleScale() {
+	ref, err := controller.getSelectedWorkload()
+	if err != nil {
+		controller.reportActionError("Cannot scale", err)
+		return
+	}
+	if !controller.requireAccess("patch", ref.Resource, "scale", ref.Namespace) {
+		return
+	}
+	if ref.Resource.Name == "daemonsets" {
+		controller.UIManager.StatusBar.SetText("[red]Daemonsets cannot be scaled, they run one pod per node")
+		return
+	}
+
+	replicas, err := controller.KubernetesClient.GetReplicas(ref)
+	if err != nil {
+		controller.reportActionError(fmt.Sprintf("Error fetching replicas of %s", ref), err)
+		return
+	}
+
+	form := tview.NewForm().
+		AddInputField("Replicas:", strconv.Itoa(int(replicas)), 8, tview.InputFieldInteger, nil)
+
+	form.AddButton("Scale", func() {
+		count, err := strconv.Atoi(form.GetFormItem(0).(*tview.InputField).GetText())
+		if err != nil || count < 0 {
+			controller.UIManager.StatusBar.SetText("[red]Invalid replica count")
+			return
+		}
+
+		controller.closeModal()
+		controller.confirmProtected(fmt.Sprintf("Scale %s to %d", ref, count), ref.Name, func() {
+			if err := controller.KubernetesClient.ScaleWorkload(ref, int32(count)); err != nil {
+				controller.reportActionError(fmt.Sprintf("Error scaling %s", ref), err)
+				return
+			}
+			utils.Info(fmt.Sprintf("Scaled %s from %d to %d replicas", ref, replicas, count))
+			controller.showRolloutProgress(ref, fmt.Sprintf("Scaled from %d to %d replicas", replicas, count))
+		})
+	}).
+		AddButton("Cancel", func() {
+			controller.closeModal()
+		})
+
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf("Scale %s", ref)).
+		SetTitleAlign(tview.AlignCenter)
+
+	controller.showModal(form, 60, 7)
+}
+
+func (controller *UIController) HandleRestart() {
+	ref, err := controller.getSelectedWorkload()
+	if err != nil {
+		controller.reportActionError("Cannot restart", err)
+		return
+	}
+	if !controller.requireAccess("patch", ref.Resource, "", ref.Namespace) {
+		return
+	}
+
+	message := fmt.Sprintf("Restart %s?\n\nAll pods are replaced following the rollout strategy.", ref)
+	controller.showConfirm("Rollout Restart", message, "Restart", ref.Name, func() {
+		if err := controller.KubernetesClient.RestartWorkload(ref); err != nil {
+			controller.reportActionError(fmt.Sprintf("Error restarting %s", ref), err)
+			return
+		}
+		utils.Info(fmt.Sprintf("Restarted %s", ref))
+		controller.showRolloutProgress(ref, "Restart triggered")
+	})
+}
+
+func (controller *UIController) HandlePauseToggle() {
+	ref, err := controller.getSelectedDeployment()
+	if err != nil {
+		controller.reportActionError("Cannot pause", err)
+		return
+	}
+	if !controller.requireAccess("patch", ref.Resource, "", ref.Namespace) {
+		return
+	}
+
+	status, err := controller.KubernetesClient.GetRolloutStatus(ref)
+	if err != nil {
+		controller.reportActionError(fmt.Sprintf("Error fetching rollout status of %s", ref), err)
+		return
+	}
+
+	action := "paused"
+	if status.Paused {
+		action = "resumed"
+	}
+	controller.confirmProtected(fmt.Sprintf("Rollout of %s %s", ref, action), ref.Name, func() {
+		if err := controller.KubernetesClient.SetRolloutPaused(ref, !status.Paused); err != nil {
+			controller.reportActionError(fmt.Sprintf("Rollout of %s not %s", ref, action), err)
+			return
+		}
+
+		utils.Info(fmt.Sprintf("Rollout of %s %s", ref, action))
+		controller.refreshResourceList()
+		controller.UIManager.StatusBar.SetText(fmt.Sprintf("[green]Rollout of %s %s", ref, action))
+	})
+}
+
+// HandleRolloutHistory lists the revisions of a deployment. Selecting an older
+// revision rolls the deployment back to it.
+func (controller *UIController) HandleRolloutHistory() {
+	ref, err := controller.getSelectedDeployment()
+	if err != nil {
+		controller.reportActionError("Cannot show history", err)
+		return
+	}
+	if !controller.requireAccess("list", kubernetes.ReplicaSetResource, "", ref.Namespace) {
+		return
+	}
+
+	revisions, err := controller.KubernetesClient.GetRolloutHistory(ref)
+	if err != nil {
+		controller.reportActionError(fmt.Sprintf("Error fetching history of %s", ref), err)
+		return
+	}
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBackgroundColor(tcell.ColorBlack).
+		SetBorder(true).
+		SetBorderColor(tcell.ColorLightGreen).
+		SetTitle(fmt.Sprintf(" History of %s (Enter Undo | Esc Close) ", ref)).
+		SetTitleAlign(tview.AlignLeft)
+
+	headers := []string{"Revision", "ReplicaSet", "Pods", "Created", "Images", "Change Cause"}
+	for col, header := range headers {
+		table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorWhite).
+			SetSelectable(false).
+			SetAlign(tview.AlignCenter))
+	}
+
+	for row, revision := range revisions {
+		number := strconv.FormatInt(revision.Number, 10)
+		if row == 0 {
+			number += " (current)"
+		}
+		cells := []*tview.TableCell{
+			tview.NewTableCell(number).SetTextColor(tcell.ColorLightYellow),
+			tview.NewTableCell(revision.ReplicaSet).SetTextColor(tcell.ColorLightCyan),
+			tview.NewTableCell(strconv.Itoa(int(revision.Replicas))).SetTextColor(tcell.ColorLightGreen).SetAlign(tview.AlignRight),
+			tview.NewTableCell(revision.Created.Format("2006-01-02 15:04")).SetTextColor(tcell.ColorLightGreen),
+			tview.NewTableCell(tview.Escape(strings.Join(revision.Images, ", "))).SetTextColor(tcell.ColorLightBlue),
+			tview.NewTableCell(tview.Escape(revision.ChangeCause)).SetTextColor(tcell.ColorGray),
+		}
+		for col, cell := range cells {
+			table.SetCell(row+1, col, cell.SetReference(revision))
+		}
+	}
+
+	table.SetSelectedFunc(func(row int, _ int) {
+		revision, ok := table.GetCell(row, 0).GetReference().(kubernetes.Revision)
+		if !ok {
+			return
+		}
+		if row == 1 {
+			controller.UIManager.StatusBar.SetText(fmt.Sprintf("[yellow]Revision %d is already the current revision", revision.Number))
+			return
+		}
+
+		controller.closeModal()
+		message := fmt.Sprintf("Roll %s back to revision %d?\n\nImages: %s", ref, revision.Number, strings.Join(revision.Images, ", "))
+		controller.showConfirm("Rollout Undo", message, "Undo", ref.Name, func() {
+			if err := controller.KubernetesClient.UndoRollout(ref, revision.Number); err != nil {
+				controller.reportActionError(fmt.Sprintf("Error rolling back %s", ref), err)
+				return
+			}
+			utils.Info(fmt.Sprintf("Rolled %s back to revision %d", ref, revision.Number))
+			controller.showRolloutProgress(ref, fmt.Sprintf("Rolled back to revision %d", revision.Number))
+		})
+	})
+
+	if len(revisions) > 1 {
+		table.Select(2, 0)
+	} else {
+		table.Select(1, 0)
+	}
+	controller.showModal(table, 0, len(revisions)+3)
+}
+
+// showRolloutProgress follows the rollout of ref until it completes or the
+// dialog is closed, then refreshes the resource list.
+func (controller *UIController) showRolloutProgress(ref kubernetes.ObjectRef, action string) {
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(true)
+	view.SetBackgroundColor(tcell.ColorBlack).
+		SetBorder(true).
+		SetBorderColor(tcell.ColorLightGreen).
+		SetTitle(fmt.Sprintf(" Rollout of %s (Esc Close) ", ref)).
+		SetTitleAlign(tview.AlignLeft)
+
+	view.SetText(fmt.Sprintf("[green]%s\n\n[gray]Fetching rollout status...", action))
+
+	// The status is fetched off the UI goroutine, so a slow API server does
+	// not freeze the dialog.
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(rolloutRefreshInterval)
+		defer ticker.Stop()
+		for {
+			status, err := controller.KubernetesClient.GetRolloutStatus(ref)
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			controller.Application.QueueUpdateDraw(func() {
+				renderRolloutStatus(view, action, status, err)
+			})
+			if err == nil && status.Done {
+				return
+			}
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	controller.showModal(view, 70, 10)
+	controller.modalCleanup = func() {
+		close(stop)
+		controller.refreshResourceList()
+	}
+}
+
+// renderRolloutStatus writes the rollout status of a workload, or the error
+// fetching it, into view.
+func renderRolloutStatus(view *tview.TextView, action string, status kubernetes.RolloutStatus, err error) {
+	if err != nil {
+		view.SetText(fmt.Sprintf("[green]%s\n\n[red]Error fetching rollout status: %s", action, tview.Escape(err.Error())))
+		return
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("[green]%s\n\n", action))
+	builder.WriteString(fmt.Sprintf("[white]Desired:   [lightyellow]%d\n", status.Desired))
+	builder.WriteString(fmt.Sprintf("[white]Updated:   [lightyellow]%d\n", status.Updated))
+	builder.WriteString(fmt.Sprintf("[white]Ready:     [lightyellow]%d\n", status.Ready))
+	builder.WriteString(fmt.Sprintf("[white]Available: [lightyellow]%d\n\n", status.Available))
+
+	switch {
+	case status.Done:
+		builder.WriteString("[lightgreen]" + tview.Escape(status.Message))
+	case status.Paused:
+		builder.WriteString("[yellow]Rollout is paused ('P' to resume)")
+	default:
+		builder.WriteString("[yellow]" + tview.Escape(status.Message) + "...")
+	}
+	view.SetText(builder.String())
+}
+
+// getSelectedWorkload returns the workload selected in the focused panel,
+// refusing objects that do not support rollout operations.
+func (controller *UIController) getSelectedWorkload() (kubernetes.ObjectRef, error) {
+	ref, err := controller.getSelectedObject()
+	if err != nil {
+		return kubernetes.ObjectRef{}, err
+	}
+	if !kubernetes.IsWorkload(ref.Resource) {
+		return kubernetes.ObjectRef{}, fmt.Errorf("%s is not a deployment, statefulset, daemonset or replicaset", ref)
+	}
+	return ref, nil
+}
+
+// getSelectedDeployment returns the deployment selected in the focused panel,
+// refusing other workloads that cannot be paused or rolled back.
+func (controller *UIController) getSelectedDeployment() (kubernetes.ObjectRef, error) {
+	ref, err := controller.getSelectedWorkload()
+	if err != nil {
+		return kubernetes.ObjectRef{}, err
+	}
+	if !kubernetes.IsDeployment(ref.Resource) {
+		return kubernetes.ObjectRef{}, fmt.Errorf("%s is not a deployment, only deployments support pause, resume and rollout history", ref)
+	}
+	return ref, nil
+}
+
+// refreshResourceList reloads the resource browser without moving the focus.
+func (controller *UIController) refreshResourceList() {
+	resource := controller.UIManager.ActiveResource
+	if resource == nil {
+		return
+	}
+
+	table, err := controller.KubernetesClient.GetResourceTable(*resource)
+	if err != nil {
+		utils.Warn(fmt.Sprintf("Error refreshing %s: %v", resource, err))
+		return
+	}
+	row, _ := controller.UIManager.ResourceListPanel.GetSelection()
+	panels.RenderResourceTable(controller.UIManager.ResourceListPanel, table)
+	if row > 0 && row < controller.UIManager.ResourceListPanel.GetRowCount() {
+		controller.UIManager.ResourceListPanel.Select(row, 0)
+	}
+}
+
+func (controller *UIController) reportActionError(message string, err error) {
+	errorMessage := fmt.Sprintf("%s: %s", message, strings.ReplaceAll(kubernetes.DescribeError(err), "\n", " "))
+	utils.Warn(errorMessage)
+	controller.UIManager.StatusBar.SetText("[red]" + errorMessage)
+}
@@ -0,0 +1,180 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContextLines = 3
+
+// maxDiffCells bounds the size of the LCS table. Changes larger than that are
+// shown as the old lines removed and the new ones added.
+const maxDiffCells = 4 << 20
+
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// UnifiedDiff returns a unified diff of two texts with three lines of context,
+// or an empty string if they are identical.
+func UnifiedDiff(oldName string, newName string, oldText string, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	lines := diffLines(splitLines(oldText), splitLines(newText))
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", oldName, newName)
+
+	oldLine, newLine := 1, 1
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+
+		// Extend the hunk until a run of unchanged lines is long enough to
+		// separate it from the next change.
+		start := i - diffContextLines
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(lines) {
+			if lines[end].op != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(lines) && lines[run].op == ' ' {
+				run++
+			}
+			if run == len(lines) || run-end > 2*diffContextLines {
+				end += min(diffContextLines, run-end)
+				break
+			}
+			end = run
+		}
+
+		hunkOld, hunkNew := oldLine-(i-start), newLine-(i-start)
+		oldCount, newCount := 0, 0
+		for _, line := range lines[start:end] {
+			if line.op != '+' {
+				oldCount++
+			}
+			if line.op != '-' {
+				newCount++
+			}
+		}
+		// An empty side of a hunk starts at the line before it, as in diff -u.
+		if oldCount == 0 {
+			hunkOld--
+		}
+		if newCount == 0 {
+			hunkNew--
+		}
+		fmt.Fprintf(&builder, "@@ -%d,%d +%d,%d @@\n", hunkOld, oldCount, hunkNew, newCount)
+		for _, line := range lines[start:end] {
+			builder.WriteByte(line.op)
+			builder.WriteString(line.text)
+			builder.WriteByte('\n')
+		}
+
+		for _, line := range lines[i:end] {
+			if line.op != '+' {
+				oldLine++
+			}
+			if line.op != '-' {
+				newLine++
+			}
+		}
+		i = end
+	}
+
+	return builder.String()
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines computes a line-based edit script using the longest common
+// subsequence of a and b. Common prefixes and suffixes are trimmed first, which
+// keeps the table small for the typical edit of a few lines.
+func diffLines(a []string, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := appendLines(nil, ' ', a[:prefix])
+	x, y := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(x)*len(y) > maxDiffCells {
+		result = appendLines(result, '-', x)
+		result = appendLines(result, '+', y)
+	} else {
+		result = append(result, lcsDiff(x, y)...)
+	}
+	return appendLines(result, ' ', a[len(a)-suffix:])
+}
+
+// lcsDiff computes the edit script from x to y with a longest common
+// subsequence table of len(x)*len(y) cells.
+func lcsDiff(x []string, y []string) []diffLine {
+	lcs := make([][]int32, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var result []diffLine
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			result = append(result, diffLine{' ', x[i]})
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			result = append(result, diffLine{'-', x[i]})
+			i++
+		default:
+			result = append(result, diffLine{'+', y[j]})
+			j++
+		}
+	}
+	return result
+}
+
+func appendLines(result []diffLine, op byte, lines []string) []diffLine {
+	for _, line := range lines {
+		result = append(result, diffLine{op, line})
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+// KubePulse - Kubernetes Cluster Monitor (TUI)
+//
+// Author: Erdem Unal
+// Year: 2024
+// Version: 0.1.0
+// License: MIT
+
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{
+			name: "identical",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "changed line",
+			old:  "a\nb\nc\n",
+			new:  "a\nB\nc\n",
+			want: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name: "empty old side",
+			old:  "",
+			new:  "a\nb\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "empty new side",
+			old:  "a\nb\n",
+			new:  "",
+			want: "--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name: "appended line",
+			old:  "a\n",
+			new:  "a\nb\n",
+			want: "--- old\n+++ new\n@@ -1,1 +1,2 @@\n a\n+b\n",
+		},
+		{
+			name: "separate hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			new:  "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			want: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+		{
+			name: "merged hunks",
+			old:  "1\n2\n3\n4\n5\n",
+			new:  "one\n2\n3\n4\nfive\n",
+			want: "--- old\n+++ new\n@@ -1,5 +1,5 @@\n-1\n+one\n 2\n 3\n 4\n-5\n+five\n",
+		},
+	}
+	for _, test := range tests {
+		if got := UnifiedDiff("old", "new", test.old, test.new); got != test.want {
+			t.Errorf("%s: UnifiedDiff =\n%s\nwant\n%s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestUnifiedDiffLargeChange(t *testing.T) {
+	var old, new strings.Builder
+	for i := 0; i < 3000; i++ {
+		old.WriteString("old\n")
+		new.WriteString("new\n")
+	}
+	diff := UnifiedDiff("old", "new", old.String(), new.String())
+	if !strings.HasPrefix(diff, "--- old\n+++ new\n@@ -1,3000 +1,3000 @@\n-old\n") || strings.Count(diff, "\n+new") != 3000 {
+		t.Errorf("UnifiedDiff of a large change starts with %q", diff[:min(len(diff), 60)])
+	}
+}
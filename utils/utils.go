@@ -8,6 +8,8 @@
 package utils
 
 import (
+	"fmt"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
@@ -25,3 +27,19 @@ func CreateTextView(defaultText string, borderColor tcell.Color, textColor tcell
 
 	return textView
 }
+
+// FormatBytes renders a byte count with a binary unit, e.g. "1.5Ki".
+func FormatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+	value := float64(bytes)
+	suffixes := []string{"Ki", "Mi", "Gi", "Ti"}
+	i := -1
+	for value >= unit && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f%s", value, suffixes[i])
+}